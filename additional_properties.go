@@ -0,0 +1,168 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// AdditionalPropertiesMap pairs the typed properties of an object schema,
+// the struct T, with the additionalProperties of type V it allows. When
+// unmarshaling, every property T has no field for goes into
+// AdditionalProperties, and when marshaling, AdditionalProperties are
+// written alongside T's fields, so that models with additionalProperties
+// don't need to decode twice and delete the known keys by hand.
+//
+// Property names are matched against T's fields case-insensitively, as
+// encoding/json does. Additional properties named like one of T's fields
+// are dropped when marshaling. Numbers unmarshaled into interface{} values
+// become json.Number while SetUseJSONNumber is in effect.
+type AdditionalPropertiesMap[T any, V any] struct {
+	Fields               T
+	AdditionalProperties map[string]V
+}
+
+// Get returns the additional property name.
+func (m AdditionalPropertiesMap[T, V]) Get(name string) (V, bool) {
+	v, ok := m.AdditionalProperties[name]
+	return v, ok
+}
+
+// Set sets the additional property name to value.
+func (m *AdditionalPropertiesMap[T, V]) Set(name string, value V) {
+	if m.AdditionalProperties == nil {
+		m.AdditionalProperties = make(map[string]V)
+	}
+	m.AdditionalProperties[name] = value
+}
+
+func (m AdditionalPropertiesMap[T, V]) MarshalJSON() ([]byte, error) {
+	data, err := jsonMarshal(m.Fields)
+	if err != nil {
+		return nil, err
+	}
+	if len(m.AdditionalProperties) == 0 {
+		return data, nil
+	}
+	var object map[string]json.RawMessage
+	if err := jsonUnmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("fields of %T don't marshal as an object: %w", m.Fields, err)
+	}
+	if object == nil {
+		object = make(map[string]json.RawMessage, len(m.AdditionalProperties))
+	}
+	known := knownJSONProperties(reflect.TypeOf(m.Fields))
+	for name, value := range m.AdditionalProperties {
+		if _, ok := lookupJSONProperty(known, name); ok {
+			continue
+		}
+		raw, err := jsonMarshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling additional property '%s': %w", name, err)
+		}
+		object[name] = raw
+	}
+	return jsonMarshal(object)
+}
+
+func (m *AdditionalPropertiesMap[T, V]) UnmarshalJSON(data []byte) error {
+	var object map[string]json.RawMessage
+	if err := jsonUnmarshal(data, &object); err != nil {
+		return err
+	}
+	var fields T
+	known := knownJSONProperties(reflect.TypeOf(fields))
+	// The properties of T are unmarshaled from the decoded object rather
+	// than from data, so that the additional ones aren't decoded twice.
+	knownObject := make(map[string]json.RawMessage, len(known))
+	var additional map[string]V
+	for name, raw := range object {
+		if _, ok := lookupJSONProperty(known, name); ok {
+			knownObject[name] = raw
+			continue
+		}
+		var value V
+		if err := jsonUnmarshalDynamic(raw, &value); err != nil {
+			return fmt.Errorf("error unmarshaling additional property '%s': %w", name, err)
+		}
+		if additional == nil {
+			additional = make(map[string]V)
+		}
+		additional[name] = value
+	}
+	if object != nil {
+		knownData, err := jsonMarshal(knownObject)
+		if err != nil {
+			return err
+		}
+		if err := jsonUnmarshalDynamic(knownData, &fields); err != nil {
+			return err
+		}
+	}
+	m.Fields = fields
+	m.AdditionalProperties = additional
+	return nil
+}
+
+var knownJSONPropertiesCache sync.Map // reflect.Type -> map[string]reflect.Type
+
+// knownJSONProperties returns the types of the fields of the struct type t
+// by the names of their JSON properties, including those promoted from
+// embedded structs. Look names up with lookupJSONProperty.
+func knownJSONProperties(t reflect.Type) map[string]reflect.Type {
+	if t == nil {
+		return nil
+	}
+	if known, ok := knownJSONPropertiesCache.Load(t); ok {
+		return known.(map[string]reflect.Type)
+	}
+	known := make(map[string]reflect.Type)
+	collectJSONProperties(t, known)
+	knownJSONPropertiesCache.Store(t, known)
+	return known
+}
+
+func collectJSONProperties(t reflect.Type, known map[string]reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get(tagName)
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" {
+			collectJSONProperties(field.Type, known)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		known[name] = field.Type
+	}
+}
+
+// lookupJSONProperty returns the type of the field of known which the JSON
+// property name is unmarshaled into, preferring an exact match to a
+// case-insensitive one, as encoding/json does.
+func lookupJSONProperty(known map[string]reflect.Type, name string) (reflect.Type, bool) {
+	if t, ok := known[name]; ok {
+		return t, true
+	}
+	for knownName, t := range known {
+		if strings.EqualFold(knownName, name) {
+			return t, true
+		}
+	}
+	return nil, false
+}
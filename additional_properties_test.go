@@ -0,0 +1,51 @@
+package runtime
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdditionalPropertiesMap(t *testing.T) {
+	type Base struct {
+		ID int `json:"id"`
+	}
+	type Pet struct {
+		Base
+		Name    string `json:"name"`
+		Ignored string `json:"-"`
+	}
+
+	var pet AdditionalPropertiesMap[Pet, int]
+	require.NoError(t, json.Unmarshal([]byte(`{"id":1,"name":"rex","Ignored":2,"age":3,"legs":4}`), &pet))
+	assert.Equal(t, Pet{Base: Base{ID: 1}, Name: "rex"}, pet.Fields)
+	assert.Equal(t, map[string]int{"Ignored": 2, "age": 3, "legs": 4}, pet.AdditionalProperties)
+	age, ok := pet.Get("age")
+	assert.True(t, ok)
+	assert.Equal(t, 3, age)
+
+	pet.Set("NAME", 5)
+	pet.Set("weight", 20)
+	delete(pet.AdditionalProperties, "Ignored")
+	data, err := json.Marshal(pet)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":1,"name":"rex","age":3,"legs":4,"weight":20}`, string(data))
+
+	data, err = json.Marshal(AdditionalPropertiesMap[Pet, int]{Fields: Pet{Name: "tom"}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":0,"name":"tom"}`, string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"name":"rex","age":"old"}`), &pet))
+
+	// Names are matched with the case folding of encoding/json, to which
+	// the long s is an s.
+	type Status struct {
+		Status string `json:"status"`
+	}
+	var status AdditionalPropertiesMap[Status, string]
+	require.NoError(t, json.Unmarshal([]byte(`{"ſtatus":"ok","code":"a"}`), &status))
+	assert.Equal(t, "ok", status.Fields.Status)
+	assert.Equal(t, map[string]string{"code": "a"}, status.AdditionalProperties)
+}
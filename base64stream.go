@@ -0,0 +1,53 @@
+package runtime
+
+import (
+	"encoding/base64"
+	"io"
+)
+
+// NewBase64Encoder returns a writer which base64 encodes everything written
+// to it into w, in the standard padded alphabet which OpenAPI's format: byte
+// specifies. The final partial block is only written by Close, so Close must
+// be called once all data is written.
+func NewBase64Encoder(w io.Writer) io.WriteCloser {
+	return base64.NewEncoder(base64.StdEncoding, w)
+}
+
+// NewBase64Decoder returns a reader which decodes base64 data from r as it
+// is read, so that large format: byte values never need to be held in memory
+// in both their encoded and decoded forms. Clients disagree on the exact
+// flavour of base64 to send, so both the standard and URL safe alphabets are
+// accepted, with or without padding, and line breaks are ignored.
+func NewBase64Decoder(r io.Reader) io.Reader {
+	return base64.NewDecoder(base64.RawStdEncoding, &base64Normalizer{r: r})
+}
+
+// base64Normalizer rewrites any flavour of base64 into unpadded standard
+// base64 on the fly.
+type base64Normalizer struct {
+	r io.Reader
+}
+
+func (n *base64Normalizer) Read(p []byte) (int, error) {
+	for {
+		read, err := n.r.Read(p)
+		kept := 0
+		for _, c := range p[:read] {
+			switch c {
+			case '-':
+				c = '+'
+			case '_':
+				c = '/'
+			case '=', '\r', '\n', ' ', '\t':
+				continue
+			}
+			p[kept] = c
+			kept++
+		}
+		// Don't report an empty read for a chunk which was all padding or
+		// whitespace, which callers could mistake for a stalled reader.
+		if kept > 0 || err != nil {
+			return kept, err
+		}
+	}
+}
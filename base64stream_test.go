@@ -0,0 +1,58 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBase64Stream(t *testing.T) {
+	data := make([]byte, 10000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+
+	var encoded bytes.Buffer
+	w := NewBase64Encoder(&encoded)
+	for i := 0; i < len(data); i += 333 {
+		end := i + 333
+		if end > len(data) {
+			end = len(data)
+		}
+		_, err := w.Write(data[i:end])
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	assert.Equal(t, base64.StdEncoding.EncodeToString(data), encoded.String())
+
+	for name, input := range map[string]string{
+		"std":     base64.StdEncoding.EncodeToString(data),
+		"raw std": base64.RawStdEncoding.EncodeToString(data),
+		"url":     base64.URLEncoding.EncodeToString(data),
+		"raw url": base64.RawURLEncoding.EncodeToString(data),
+		"mime":    strings.Join(splitEvery(base64.StdEncoding.EncodeToString(data), 76), "\r\n"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			decoded, err := io.ReadAll(NewBase64Decoder(strings.NewReader(input)))
+			require.NoError(t, err)
+			assert.Equal(t, data, decoded)
+		})
+	}
+
+	_, err := io.ReadAll(NewBase64Decoder(strings.NewReader("not*base64")))
+	assert.Error(t, err)
+}
+
+func splitEvery(s string, n int) []string {
+	var parts []string
+	for len(s) > n {
+		parts = append(parts, s[:n])
+		s = s[n:]
+	}
+	return append(parts, s)
+}
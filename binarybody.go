@@ -0,0 +1,129 @@
+package runtime
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// BodyTooLargeError is returned when a body exceeds the size limit it is
+// being read with.
+type BodyTooLargeError struct {
+	Limit int64
+}
+
+func (e *BodyTooLargeError) Error() string {
+	return fmt.Sprintf("body exceeds the limit of %d bytes", e.Limit)
+}
+
+// ReadBinaryBody prepares an application/octet-stream body, such as a server
+// request's or a client response's, for streaming. It returns the body
+// itself rather than its contents, so that large payloads can be passed
+// through without being held in memory.
+//
+// When maxBytes is positive, a body whose Content-Length exceeds it is
+// rejected up front, and reading more than maxBytes from the returned reader
+// fails with a *BodyTooLargeError, so chunked bodies are limited as well.
+// Closing the returned reader closes body.
+func ReadBinaryBody(body io.ReadCloser, header http.Header, maxBytes int64) (io.ReadCloser, error) {
+	if body == nil {
+		body = http.NoBody
+	}
+	if maxBytes <= 0 {
+		return body, nil
+	}
+	if contentLength, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64); err == nil && contentLength > maxBytes {
+		_ = body.Close()
+		return nil, &BodyTooLargeError{Limit: maxBytes}
+	}
+	return &limitedBody{ReadCloser: body, remaining: maxBytes, limit: maxBytes}, nil
+}
+
+// limitedBody reads at most limit bytes of a body, failing instead of
+// truncating it silently when there is more.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	limit     int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, &BodyTooLargeError{Limit: b.limit}
+	}
+	// Read one byte past the limit to tell a body of exactly limit bytes
+	// from a larger one.
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n + int(b.remaining), &BodyTooLargeError{Limit: b.limit}
+	}
+	return n, err
+}
+
+// WriteBinaryBody sets body as the body of the client request req, sending it
+// as contentType, which defaults to application/octet-stream. The body is
+// streamed rather than buffered. Its length is sent when it can be
+// determined without reading it, and bodies which can be rewound support
+// redirects and retries via http.Request.GetBody. As the transport closes the
+// body once sent, a body which can be rewound, such as an *os.File, isn't
+// closed, so that it can still be replayed; the caller closes it once the
+// response is received. Other bodies implementing io.Closer are closed.
+//
+// On the server side, nethttp.StreamResponse streams binary responses the
+// same way.
+func WriteBinaryBody(req *http.Request, body io.Reader, contentType string) error {
+	if contentType == "" {
+		contentType = octetStreamContentType
+	}
+	req.Header.Set("Content-Type", contentType)
+	if body == nil {
+		req.Body, req.GetBody, req.ContentLength = http.NoBody, nil, 0
+		return nil
+	}
+
+	req.ContentLength = -1
+	switch b := body.(type) {
+	case *bytes.Buffer:
+		req.ContentLength = int64(b.Len())
+	case *bytes.Reader:
+		req.ContentLength = int64(b.Len())
+	case *strings.Reader:
+		req.ContentLength = int64(b.Len())
+	}
+	req.GetBody = nil
+	if seeker, ok := body.(io.ReadSeeker); ok {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return fmt.Errorf("error finding body offset: %w", err)
+		}
+		if req.ContentLength < 0 {
+			end, err := seeker.Seek(0, io.SeekEnd)
+			if err != nil {
+				return fmt.Errorf("error finding body size: %w", err)
+			}
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return fmt.Errorf("error rewinding body: %w", err)
+			}
+			req.ContentLength = end - start
+		}
+		req.GetBody = func() (io.ReadCloser, error) {
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return nil, err
+			}
+			return io.NopCloser(seeker), nil
+		}
+	}
+	if rc, ok := body.(io.ReadCloser); ok && req.GetBody == nil {
+		req.Body = rc
+	} else {
+		req.Body = io.NopCloser(body)
+	}
+	return nil
+}
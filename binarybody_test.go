@@ -0,0 +1,101 @@
+package runtime
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadBinaryBody(t *testing.T) {
+	t.Run("within limit", func(t *testing.T) {
+		body, err := ReadBinaryBody(io.NopCloser(strings.NewReader("12345")), http.Header{}, 5)
+		require.NoError(t, err)
+		data, err := io.ReadAll(body)
+		require.NoError(t, err)
+		assert.Equal(t, "12345", string(data))
+	})
+
+	t.Run("content length over limit", func(t *testing.T) {
+		header := http.Header{"Content-Length": {"100"}}
+		_, err := ReadBinaryBody(io.NopCloser(strings.NewReader("")), header, 10)
+		var tooLarge *BodyTooLargeError
+		require.True(t, errors.As(err, &tooLarge))
+		assert.Equal(t, int64(10), tooLarge.Limit)
+	})
+
+	t.Run("chunked body over limit", func(t *testing.T) {
+		body, err := ReadBinaryBody(io.NopCloser(strings.NewReader("123456")), http.Header{}, 5)
+		require.NoError(t, err)
+		data, err := io.ReadAll(body)
+		var tooLarge *BodyTooLargeError
+		assert.True(t, errors.As(err, &tooLarge))
+		assert.Equal(t, "12345", string(data))
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		body, err := ReadBinaryBody(nil, http.Header{}, 0)
+		require.NoError(t, err)
+		data, err := io.ReadAll(body)
+		require.NoError(t, err)
+		assert.Empty(t, data)
+	})
+}
+
+func TestWriteBinaryBody(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPut, "https://example.com/files/1", nil)
+	require.NoError(t, err)
+
+	reader := bytes.NewReader([]byte("binary data"))
+	_, _ = reader.Seek(7, io.SeekStart)
+	require.NoError(t, WriteBinaryBody(req, io.MultiReader(reader), ""))
+	assert.Equal(t, "application/octet-stream", req.Header.Get("Content-Type"))
+	assert.Equal(t, int64(-1), req.ContentLength)
+	assert.Nil(t, req.GetBody)
+
+	_, _ = reader.Seek(7, io.SeekStart)
+	require.NoError(t, WriteBinaryBody(req, struct{ io.ReadSeeker }{reader}, "image/png"))
+	assert.Equal(t, "image/png", req.Header.Get("Content-Type"))
+	assert.Equal(t, int64(4), req.ContentLength)
+	data, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+
+	require.NotNil(t, req.GetBody)
+	replay, err := req.GetBody()
+	require.NoError(t, err)
+	data, err = io.ReadAll(replay)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+}
+
+func TestWriteBinaryBody_File(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "upload.bin")
+	require.NoError(t, os.WriteFile(name, []byte("binary data"), 0o600))
+	f, err := os.Open(name)
+	require.NoError(t, err)
+	defer f.Close()
+
+	req, err := http.NewRequest(http.MethodPut, "https://example.com/files/1", nil)
+	require.NoError(t, err)
+	require.NoError(t, WriteBinaryBody(req, f, ""))
+	assert.Equal(t, int64(11), req.ContentLength)
+
+	// The transport closing the body doesn't close the file, which a retry
+	// reads again.
+	_, err = io.ReadAll(req.Body)
+	require.NoError(t, err)
+	require.NoError(t, req.Body.Close())
+	replay, err := req.GetBody()
+	require.NoError(t, err)
+	data, err := io.ReadAll(replay)
+	require.NoError(t, err)
+	assert.Equal(t, "binary data", string(data))
+}
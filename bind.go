@@ -22,3 +22,11 @@ package runtime
 type Binder interface {
 	Bind(src string) error
 }
+
+// ArrayBinder is implemented by types, such as types.Set, which hold the
+// items of an array parameter without being slices themselves. Once the
+// parameter has been split according to its style, the items are passed to
+// BindArray, as they would be bound to the items of a slice.
+type ArrayBinder interface {
+	BindArray(items []string) error
+}
@@ -0,0 +1,134 @@
+package runtime
+
+import "fmt"
+
+// BindingError is implemented by the errors which the parameter binding
+// functions return when a parameter's value can't be bound, so that servers
+// can tell them apart from other failures and respond with a 400, using
+// errors.As on either this interface or one of the concrete types below.
+// Errors in how a binding function is called, such as an unknown style,
+// don't implement it.
+type BindingError interface {
+	error
+	// Param returns the name of the parameter which failed to bind.
+	Param() string
+	// In returns the location of the parameter which failed to bind.
+	In() ParamLocation
+}
+
+// String returns the name OpenAPI uses for the location, such as "query",
+// or an empty string for ParamLocationUndefined.
+func (l ParamLocation) String() string {
+	switch l {
+	case ParamLocationQuery:
+		return "query"
+	case ParamLocationPath:
+		return "path"
+	case ParamLocationHeader:
+		return "header"
+	case ParamLocationCookie:
+		return "cookie"
+	default:
+		return ""
+	}
+}
+
+// DescribeParam renders a parameter for error messages, such as
+// "query parameter 'id'".
+func DescribeParam(paramName string, location ParamLocation) string {
+	if location == ParamLocationUndefined {
+		return fmt.Sprintf("parameter '%s'", paramName)
+	}
+	return fmt.Sprintf("%s parameter '%s'", location, paramName)
+}
+
+// causeSuffix renders an optional cause at the end of an error message.
+func causeSuffix(err error) string {
+	if err == nil {
+		return ""
+	}
+	return ": " + err.Error()
+}
+
+// RequiredParamError is returned when a required parameter is missing or
+// empty.
+type RequiredParamError struct {
+	ParamName string
+	Location  ParamLocation
+	Err       error
+}
+
+func (e *RequiredParamError) Error() string {
+	return DescribeParam(e.ParamName, e.Location) + " is required" + causeSuffix(e.Err)
+}
+
+func (e *RequiredParamError) Unwrap() error     { return e.Err }
+func (e *RequiredParamError) Param() string     { return e.ParamName }
+func (e *RequiredParamError) In() ParamLocation { return e.Location }
+
+// InvalidParamFormatError is returned when a parameter's value doesn't match
+// the format its style requires, such as a label parameter without its
+// leading period, or can't be unescaped.
+type InvalidParamFormatError struct {
+	ParamName string
+	Location  ParamLocation
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return DescribeParam(e.ParamName, e.Location) + " has invalid format" + causeSuffix(e.Err)
+}
+
+func (e *InvalidParamFormatError) Unwrap() error     { return e.Err }
+func (e *InvalidParamFormatError) Param() string     { return e.ParamName }
+func (e *InvalidParamFormatError) In() ParamLocation { return e.Location }
+
+// UnmarshalingParamError is returned when a parameter's value is well formed,
+// but can't be converted to its destination type, such as "abc" for an
+// integer.
+type UnmarshalingParamError struct {
+	ParamName string
+	Location  ParamLocation
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return "error binding " + DescribeParam(e.ParamName, e.Location) + causeSuffix(e.Err)
+}
+
+func (e *UnmarshalingParamError) Unwrap() error     { return e.Err }
+func (e *UnmarshalingParamError) Param() string     { return e.ParamName }
+func (e *UnmarshalingParamError) In() ParamLocation { return e.Location }
+
+// TooManyValuesError is returned when a parameter, or one of the fields of
+// an object parameter, which takes a single value is specified more than
+// once.
+type TooManyValuesError struct {
+	ParamName string
+	Location  ParamLocation
+	Err       error
+}
+
+func (e *TooManyValuesError) Error() string {
+	return DescribeParam(e.ParamName, e.Location) + " has too many values" + causeSuffix(e.Err)
+}
+
+func (e *TooManyValuesError) Unwrap() error     { return e.Err }
+func (e *TooManyValuesError) Param() string     { return e.ParamName }
+func (e *TooManyValuesError) In() ParamLocation { return e.Location }
+
+// LimitExceededError is returned when a parameter's value exceeds one of the
+// limits binding enforces against abusive input.
+type LimitExceededError struct {
+	ParamName string
+	Location  ParamLocation
+	Err       error
+}
+
+func (e *LimitExceededError) Error() string {
+	return DescribeParam(e.ParamName, e.Location) + " exceeds a limit" + causeSuffix(e.Err)
+}
+
+func (e *LimitExceededError) Unwrap() error     { return e.Err }
+func (e *LimitExceededError) Param() string     { return e.ParamName }
+func (e *LimitExceededError) In() ParamLocation { return e.Location }
@@ -0,0 +1,99 @@
+package runtime
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindingErrors(t *testing.T) {
+	var id int
+	err := BindStyledParameterWithOptions("simple", "id", "", &id, BindStyledParameterOptions{
+		ParamLocation: ParamLocationPath,
+		Required:      true,
+	})
+	var required *RequiredParamError
+	require.True(t, errors.As(err, &required))
+	assert.Equal(t, "id", required.ParamName)
+	assert.Equal(t, ParamLocationPath, required.Location)
+	assert.EqualError(t, err, "path parameter 'id' is required")
+
+	err = BindStyledParameterWithOptions("simple", "id", "abc", &id, BindStyledParameterOptions{
+		ParamLocation: ParamLocationHeader,
+	})
+	var unmarshaling *UnmarshalingParamError
+	require.True(t, errors.As(err, &unmarshaling))
+	assert.Equal(t, ParamLocationHeader, unmarshaling.Location)
+	assert.Error(t, errors.Unwrap(err))
+
+	var ids []int
+	err = BindStyledParameterWithOptions("label", "ids", "3,4", &ids, BindStyledParameterOptions{
+		ParamLocation: ParamLocationPath,
+	})
+	var invalid *InvalidParamFormatError
+	require.True(t, errors.As(err, &invalid))
+	assert.Equal(t, "ids", invalid.ParamName)
+
+	err = BindQueryParameter("form", true, true, "id", url.Values{}, &id)
+	require.True(t, errors.As(err, &required))
+	assert.Equal(t, ParamLocationQuery, required.Location)
+
+	err = BindQueryParameter("form", true, true, "id", url.Values{"id": {"1", "2"}}, &id)
+	var tooMany *TooManyValuesError
+	require.True(t, errors.As(err, &tooMany))
+	assert.Equal(t, "id", tooMany.ParamName)
+
+	var obj struct {
+		Name string `json:"name"`
+	}
+	err = UnmarshalDeepObject(&obj, "obj", url.Values{"obj[name]": {"a", "b"}})
+	require.True(t, errors.As(err, &tooMany))
+	assert.Equal(t, "obj", tooMany.ParamName)
+
+	var bindingErr BindingError
+	require.True(t, errors.As(err, &bindingErr))
+	assert.Equal(t, "obj", bindingErr.Param())
+	assert.Equal(t, ParamLocationQuery, bindingErr.In())
+
+	err = BindQueryParameter("unknown", true, true, "id", url.Values{"id": {"1"}}, &id)
+	assert.False(t, errors.As(err, &bindingErr))
+}
+
+func TestDescribeParam(t *testing.T) {
+	assert.Equal(t, "query parameter 'foo'", DescribeParam("foo", ParamLocationQuery))
+	assert.Equal(t, "path parameter 'foo'", DescribeParam("foo", ParamLocationPath))
+	assert.Equal(t, "header parameter 'foo'", DescribeParam("foo", ParamLocationHeader))
+	assert.Equal(t, "cookie parameter 'foo'", DescribeParam("foo", ParamLocationCookie))
+	assert.Equal(t, "parameter 'foo'", DescribeParam("foo", ParamLocationUndefined))
+}
+
+func TestErrorsNameParameter(t *testing.T) {
+	var id int
+	err := BindStyledParameterWithOptions("simple", "session", "abc", &id, BindStyledParameterOptions{
+		ParamLocation: ParamLocationCookie,
+	})
+	assert.ErrorContains(t, err, "cookie parameter 'session'")
+
+	var obj struct {
+		Name string `json:"name"`
+	}
+	err = BindStyledParameterWithOptions("unknown", "obj", "a", &obj, BindStyledParameterOptions{
+		ParamLocation: ParamLocationPath,
+	})
+	assert.ErrorContains(t, err, "path parameter 'obj'")
+
+	err = BindQueryParameter("unknown", true, true, "id", url.Values{"id": {"1"}}, &id)
+	assert.ErrorContains(t, err, "query parameter 'id'")
+	err = BindQueryParameter("deepObject", false, true, "obj", url.Values{}, &obj)
+	assert.ErrorContains(t, err, "query parameter 'obj'")
+
+	_, err = StyleParamWithLocation("unknown", false, "id", ParamLocationHeader, 5)
+	assert.EqualError(t, err, "error styling header parameter 'id': unsupported style 'unknown'")
+	_, err = StyleParamWithLocation("form", true, "ids", ParamLocationQuery, []interface{}{1, struct{}{}})
+	assert.ErrorContains(t, err, "error styling query parameter 'ids': error formatting item 1")
+	_, err = StyleParamWithLocation("deepObject", false, "obj", ParamLocationQuery, obj)
+	assert.EqualError(t, err, "error styling query parameter 'obj': deepObjects must be exploded")
+}
@@ -1,7 +1,6 @@
 package runtime
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"mime/multipart"
@@ -21,6 +20,9 @@ type RequestBodyEncoding struct {
 	Style       string
 	Explode     *bool
 	Required    *bool
+	// Headers are extra headers sent with the property's part of a
+	// multipart body.
+	Headers map[string]string
 }
 
 func BindMultipart(ptr interface{}, reader multipart.Reader) error {
@@ -55,7 +57,7 @@ func BindForm(ptr interface{}, form map[string][]string, files map[string][]*mul
 			value := values[0]
 			if encoding.ContentType != "" {
 				if strings.HasPrefix(encoding.ContentType, jsonContentType) {
-					if err := json.Unmarshal([]byte(value), ptr); err != nil {
+					if err := jsonUnmarshalDynamic([]byte(value), ptr); err != nil {
 						return err
 					}
 				}
@@ -85,7 +87,7 @@ func BindForm(ptr interface{}, form map[string][]string, files map[string][]*mul
 		}
 	}
 
-	return nil
+	return validateBound(ptr)
 }
 
 func MarshalForm(ptr interface{}, encodings map[string]RequestBodyEncoding) (url.Values, error) {
@@ -108,7 +110,7 @@ func MarshalForm(ptr interface{}, encodings map[string]RequestBodyEncoding) (url
 		tag = strings.Split(tag, ",")[0] // extract the name of the tag
 		if encoding, ok := encodings[tag]; ok && encoding.ContentType != "" {
 			if strings.HasPrefix(encoding.ContentType, jsonContentType) {
-				if data, err := json.Marshal(field); err != nil { //nolint:staticcheck
+				if data, err := jsonMarshal(field); err != nil { //nolint:staticcheck
 					return nil, err
 				} else {
 					result[tag] = append(result[tag], string(data))
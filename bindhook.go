@@ -0,0 +1,132 @@
+package runtime
+
+import (
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+// BindFailure describes a parameter which failed to bind.
+type BindFailure struct {
+	// OperationID is the operation the parameter belongs to, when the
+	// caller passed it in its options, or "" otherwise.
+	OperationID string
+	ParamName   string
+	Location    ParamLocation
+	Err         error
+}
+
+// BindFailureFunc is called with every parameter which fails to bind.
+type BindFailureFunc func(f BindFailure)
+
+type bindFailureHolder struct {
+	hook BindFailureFunc
+}
+
+var bindFailureHook atomic.Pointer[bindFailureHolder]
+
+// SetBindFailureHook installs a hook which is called whenever
+// BindStyledParameterWithOptions, BindQueryParameterWithOptions or
+// BindRequest fails to bind a parameter, so that operators can log and
+// monitor malformed requests without wrapping every generated call site.
+// Passing nil removes the hook. The hook is called synchronously, by the
+// goroutine serving the request, so it should be quick. It panics after
+// FreezeSettings.
+func SetBindFailureHook(hook BindFailureFunc) {
+	checkSettingsFrozen("SetBindFailureHook")
+	if hook == nil {
+		bindFailureHook.Store(nil)
+		return
+	}
+	bindFailureHook.Store(&bindFailureHolder{hook: hook})
+}
+
+// reportBindFailure passes a parameter which failed to bind to the hook
+// installed with SetBindFailureHook, if any.
+func reportBindFailure(operationID, paramName string, location ParamLocation, err error) {
+	if h := bindFailureHook.Load(); h != nil {
+		h.hook(BindFailure{OperationID: operationID, ParamName: paramName, Location: location, Err: err})
+	}
+}
+
+// BindTrace describes how a parameter was bound.
+type BindTrace struct {
+	// OperationID is the operation the parameter belongs to, when the
+	// caller passed it in its options, or "" otherwise.
+	OperationID string
+	ParamName   string
+	Location    ParamLocation
+	// Raw holds the values received for the parameter, as they appeared in
+	// the request.
+	Raw []string
+	// Value is what the destination holds after binding.
+	Value    interface{}
+	Duration time.Duration
+	// Err is the error binding failed with, if any.
+	Err error
+}
+
+// BindTraceFunc is called after every parameter is bound.
+type BindTraceFunc func(t BindTrace)
+
+type bindTraceHolder struct {
+	hook BindTraceFunc
+}
+
+var bindTraceHook atomic.Pointer[bindTraceHolder]
+
+// SetBindTraceHook installs a hook which is called after every parameter
+// BindStyledParameterWithOptions, BindQueryParameterWithOptions or
+// BindRequest binds, successfully or not, with the raw values received and
+// the Go value they were bound to. This is meant as a debug mode showing
+// exactly how a request was interpreted, for example to diagnose a client
+// using a different style or explode setting than the spec. Passing nil
+// removes the hook, which costs nothing while it isn't installed. It panics
+// after FreezeSettings.
+func SetBindTraceHook(hook BindTraceFunc) {
+	checkSettingsFrozen("SetBindTraceHook")
+	if hook == nil {
+		bindTraceHook.Store(nil)
+		return
+	}
+	bindTraceHook.Store(&bindTraceHolder{hook: hook})
+}
+
+// bindObserver reports the outcome of binding one parameter to the failure
+// and trace hooks. Its zero value only reports failures.
+type bindObserver struct {
+	trace *bindTraceHolder
+	start time.Time
+}
+
+// observeBind starts timing a parameter being bound, when tracing.
+func observeBind() bindObserver {
+	o := bindObserver{trace: bindTraceHook.Load()}
+	if o.trace != nil {
+		o.start = time.Now()
+	}
+	return o
+}
+
+// done reports a parameter bound into dest from raw.
+func (o bindObserver) done(operationID, paramName string, location ParamLocation, raw []string, dest interface{}, err error) {
+	if err != nil {
+		reportBindFailure(operationID, paramName, location, err)
+	}
+	if o.trace == nil {
+		return
+	}
+	var value interface{}
+	if v := reflect.ValueOf(dest); v.Kind() == reflect.Ptr && !v.IsNil() {
+		value = v.Elem().Interface()
+	}
+	o.trace.hook(BindTrace{
+		OperationID: operationID,
+		ParamName:   paramName,
+		Location:    location,
+		Raw:         raw,
+		Value:       value,
+		Duration:    time.Since(o.start),
+		Err:         err,
+	})
+}
@@ -0,0 +1,95 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetBindFailureHook(t *testing.T) {
+	var failures []BindFailure
+	defer SetBindFailureHook(nil)
+	SetBindFailureHook(func(f BindFailure) {
+		failures = append(failures, f)
+	})
+
+	var id int
+	err := BindStyledParameterWithOptions("simple", "id", "x", &id, BindStyledParameterOptions{
+		ParamLocation: ParamLocationPath,
+		OperationID:   "getPet",
+	})
+	require.Error(t, err)
+	require.NoError(t, BindStyledParameterWithOptions("simple", "id", "5", &id, BindStyledParameterOptions{ParamLocation: ParamLocationPath}))
+
+	var limit int
+	err = BindQueryParameterWithOptions("form", "limit", url.Values{}, &limit, BindQueryParameterOptions{
+		Required:    true,
+		OperationID: "listPets",
+	})
+	require.Error(t, err)
+
+	require.Len(t, failures, 2)
+	assert.Equal(t, "getPet", failures[0].OperationID)
+	assert.Equal(t, "id", failures[0].ParamName)
+	assert.Equal(t, ParamLocationPath, failures[0].Location)
+	assert.Equal(t, "listPets", failures[1].OperationID)
+	assert.Equal(t, ParamLocationQuery, failures[1].Location)
+	assert.IsType(t, &RequiredParamError{}, failures[1].Err)
+
+	failures = nil
+	r := httptest.NewRequest(http.MethodPost, "/pets/x?limit=many", nil)
+	r = r.WithContext(WithOperationID(context.Background(), "updatePet"))
+	var obj bindRequestObject
+	require.Error(t, BindRequest(r, map[string]string{"id": "x"}, &obj))
+	require.Len(t, failures, 3, "the missing body isn't a parameter")
+	for _, f := range failures {
+		assert.Equal(t, "updatePet", f.OperationID)
+	}
+	assert.Equal(t, []string{"id", "limit", "X-Key"}, []string{failures[0].ParamName, failures[1].ParamName, failures[2].ParamName})
+}
+
+func TestSetBindTraceHook(t *testing.T) {
+	var traces []BindTrace
+	defer SetBindTraceHook(nil)
+	SetBindTraceHook(func(tr BindTrace) {
+		traces = append(traces, tr)
+	})
+
+	var ids []int
+	require.NoError(t, BindStyledParameterWithOptions("label", "ids", ".1,2", &ids, BindStyledParameterOptions{
+		ParamLocation: ParamLocationPath,
+		OperationID:   "getPets",
+	}))
+	var limit *int
+	err := BindQueryParameterWithOptions("form", "limit", url.Values{"limit": {"x"}}, &limit, BindQueryParameterOptions{})
+	require.Error(t, err)
+
+	require.Len(t, traces, 2)
+	assert.Equal(t, "getPets", traces[0].OperationID)
+	assert.Equal(t, []string{".1,2"}, traces[0].Raw)
+	assert.Equal(t, []int{1, 2}, traces[0].Value)
+	assert.NoError(t, traces[0].Err)
+	assert.Equal(t, "limit", traces[1].ParamName)
+	assert.Equal(t, ParamLocationQuery, traces[1].Location)
+	assert.Equal(t, []string{"x"}, traces[1].Raw)
+	assert.Error(t, traces[1].Err)
+
+	traces = nil
+	r := httptest.NewRequest(http.MethodPost, "/pets/5?tags=a&tags=b", strings.NewReader(`{"name":"Rex"}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-Key", "secret")
+	var obj bindRequestObject
+	require.NoError(t, BindRequest(r, map[string]string{"id": "5"}, &obj))
+	require.Len(t, traces, 6, "every parameter but the body")
+	assert.Equal(t, []string{"5"}, traces[0].Raw)
+	assert.Equal(t, 5, traces[0].Value)
+	assert.Equal(t, []string{"a", "b"}, traces[1].Raw)
+	assert.Equal(t, []string{"a", "b"}, traces[1].Value)
+	assert.Nil(t, traces[2].Raw)
+}
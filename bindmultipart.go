@@ -0,0 +1,151 @@
+package runtime
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"reflect"
+	"strings"
+
+	"github.com/oapi-codegen/runtime/types"
+)
+
+var fileType = reflect.TypeOf(types.File{})
+
+// BindMultipartBody reads a multipart/form-data body part by part and binds
+// each part to the field of the struct dest whose JSON name matches the
+// part's form name. Unlike BindMultipart, it does not buffer the form to
+// disk first.
+//
+// Parts bound to types.File fields keep their file name. Parts sent with a
+// JSON content type are unmarshaled into their field, and all other parts are
+// bound as plain strings, the same way form values are. Repeated parts are
+// appended to slice fields, and parts without a matching field are skipped
+// without being read. Each bound part is read into memory, so parts larger
+// than Limits.MaxMultipartPartBytes fail with a *PartTooLargeError as soon as
+// they exceed it.
+func BindMultipartBody(reader *multipart.Reader, dest interface{}) error {
+	return BindMultipartBodyWithOptions(reader, dest, BindMultipartBodyOptions{})
+}
+
+// BindMultipartBodyOptions defines optional arguments for
+// BindMultipartBodyWithOptions.
+type BindMultipartBodyOptions struct {
+	// Limits overrides the package-wide limits set with SetLimits.
+	Limits *Limits
+}
+
+// BindMultipartBodyWithOptions is BindMultipartBody with its optional
+// arguments.
+func BindMultipartBodyWithOptions(reader *multipart.Reader, dest interface{}, opts BindMultipartBodyOptions) error {
+	if err := bindMultipartBody(reader, dest, resolveLimits(opts.Limits)); err != nil {
+		return err
+	}
+	return validateBound(dest)
+}
+
+// bindMultipartBody is BindMultipartBody without the validation.
+func bindMultipartBody(reader *multipart.Reader, dest interface{}, limits Limits) error {
+	ptrVal := reflect.ValueOf(dest)
+	if ptrVal.Kind() != reflect.Ptr || ptrVal.Elem().Kind() != reflect.Struct {
+		return errors.New("multipart body destination should be a pointer to a struct")
+	}
+	fields := multipartFields(ptrVal.Elem())
+
+	for n := 1; ; n++ {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading multipart body: %w", err)
+		}
+		if err := limits.checkMultipartParts(n); err != nil {
+			_ = part.Close()
+			return err
+		}
+		name := part.FormName()
+		field, ok := fields[name]
+		if !ok {
+			_ = part.Close()
+			continue
+		}
+		data, err := limits.readMultipartPart(name, part)
+		_ = part.Close()
+		var tooLarge *PartTooLargeError
+		if errors.As(err, &tooLarge) {
+			return err
+		}
+		if err != nil {
+			return fmt.Errorf("error reading part '%s': %w", name, err)
+		}
+		if err := bindMultipartPart(field, part, data); err != nil {
+			return fmt.Errorf("error binding part '%s': %w", name, err)
+		}
+	}
+}
+
+// multipartFields indexes the settable fields of v by their JSON name.
+func multipartFields(v reflect.Value) map[string]reflect.Value {
+	fields := make(map[string]reflect.Value, v.NumField())
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get(tagName)
+		if !v.Field(i).CanSet() || tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = t.Field(i).Name
+		}
+		fields[name] = v.Field(i)
+	}
+	return fields
+}
+
+func bindMultipartPart(v reflect.Value, part *multipart.Part, data []byte) error {
+	switch {
+	case v.Kind() == reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return bindMultipartPart(v.Elem(), part, data)
+	case v.Type() == fileType:
+		var file types.File
+		file.InitFromBytes(data, part.FileName())
+		v.Set(reflect.ValueOf(file))
+		return nil
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 &&
+		!(isJSONPart(part) && isJSONArray(data)):
+		// Each part holds one element of the array, unless it is a JSON
+		// array holding all of them.
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if err := bindMultipartPart(elem, part, data); err != nil {
+			return err
+		}
+		v.Set(reflect.Append(v, elem))
+		return nil
+	case isJSONPart(part) && v.Kind() != reflect.String:
+		return jsonUnmarshal(data, v.Addr().Interface())
+	case v.Kind() == reflect.Slice:
+		v.SetBytes(data)
+		return nil
+	default:
+		return BindStringToObject(string(data), v.Addr().Interface())
+	}
+}
+
+func isJSONPart(part *multipart.Part) bool {
+	mediaType, _, err := mime.ParseMediaType(part.Header.Get("Content-Type"))
+	return err == nil && isJSONMediaType(mediaType)
+}
+
+// isJSONArray reports whether data is a JSON array, as opposed to another
+// JSON value.
+func isJSONArray(data []byte) bool {
+	data = bytes.TrimLeft(data, " \t\r\n")
+	return len(data) > 0 && data[0] == '['
+}
@@ -0,0 +1,111 @@
+package runtime
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/textproto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oapi-codegen/runtime/types"
+)
+
+func TestBindMultipartBody(t *testing.T) {
+	type Meta struct {
+		Owner string `json:"owner"`
+	}
+	type Upload struct {
+		Name        string       `json:"name"`
+		Count       *int         `json:"count,omitempty"`
+		Tags        []string     `json:"tags"`
+		Meta        Meta         `json:"meta"`
+		Avatar      types.File   `json:"avatar"`
+		Attachments []types.File `json:"attachments"`
+		Raw         []byte       `json:"raw"`
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	require.NoError(t, w.WriteField("name", "rex"))
+	require.NoError(t, w.WriteField("count", "3"))
+	require.NoError(t, w.WriteField("tags", "a"))
+	require.NoError(t, w.WriteField("tags", "b"))
+	require.NoError(t, w.WriteField("ignored", "x"))
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="meta"`)
+	header.Set("Content-Type", "application/json")
+	part, err := w.CreatePart(header)
+	require.NoError(t, err)
+	_, _ = part.Write([]byte(`{"owner":"alice"}`))
+	part, err = w.CreateFormFile("avatar", "rex.png")
+	require.NoError(t, err)
+	_, _ = part.Write([]byte("png"))
+	for _, name := range []string{"one.txt", "two.txt"} {
+		part, err = w.CreateFormFile("attachments", name)
+		require.NoError(t, err)
+		_, _ = part.Write([]byte(name))
+	}
+	require.NoError(t, w.WriteField("raw", "\x00\x01"))
+	require.NoError(t, w.Close())
+
+	var dest Upload
+	require.NoError(t, BindMultipartBody(multipart.NewReader(&buf, w.Boundary()), &dest))
+
+	assert.Equal(t, "rex", dest.Name)
+	require.NotNil(t, dest.Count)
+	assert.Equal(t, 3, *dest.Count)
+	assert.Equal(t, []string{"a", "b"}, dest.Tags)
+	assert.Equal(t, "alice", dest.Meta.Owner)
+	assert.Equal(t, "rex.png", dest.Avatar.Filename())
+	data, err := dest.Avatar.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, "png", string(data))
+	require.Len(t, dest.Attachments, 2)
+	assert.Equal(t, "two.txt", dest.Attachments[1].Filename())
+	assert.Equal(t, []byte{0, 1}, dest.Raw)
+}
+
+func TestBindMultipartBody_ArrayOfObjects(t *testing.T) {
+	type Item struct {
+		ID int `json:"id"`
+	}
+	type Body struct {
+		Items []Item `json:"items"`
+		Owner []Item `json:"owner"`
+	}
+
+	// EncodeMultipartBody sends each object of an array as a JSON part.
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	require.NoError(t, EncodeMultipartBody(w, &Body{Items: []Item{{1}, {2}}}, nil))
+	// A single part may also hold the whole array.
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="owner"`)
+	header.Set("Content-Type", "application/json")
+	part, err := w.CreatePart(header)
+	require.NoError(t, err)
+	_, _ = part.Write([]byte(` [{"id":3},{"id":4}]`))
+	require.NoError(t, w.Close())
+
+	var dest Body
+	require.NoError(t, BindMultipartBody(multipart.NewReader(&buf, w.Boundary()), &dest))
+	assert.Equal(t, []Item{{1}, {2}}, dest.Items)
+	assert.Equal(t, []Item{{3}, {4}}, dest.Owner)
+}
+
+func TestBindMultipartBody_Errors(t *testing.T) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	require.NoError(t, w.WriteField("count", "many"))
+	require.NoError(t, w.Close())
+
+	var dest struct {
+		Count int `json:"count"`
+	}
+	err := BindMultipartBody(multipart.NewReader(&buf, w.Boundary()), &dest)
+	assert.ErrorContains(t, err, "part 'count'")
+
+	assert.Error(t, BindMultipartBody(multipart.NewReader(&buf, w.Boundary()), dest))
+}
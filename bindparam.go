@@ -15,9 +15,9 @@ package runtime
 
 import (
 	"encoding"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"reflect"
 	"strings"
@@ -63,17 +63,33 @@ type BindStyledParameterOptions struct {
 	Explode bool
 	// Whether the parameter is required in the query
 	Required bool
+	// Limits overrides the package-wide limits set with SetLimits.
+	Limits *Limits
+	// OperationID is passed on to the hooks installed with
+	// SetBindFailureHook and SetBindTraceHook.
+	OperationID string
 }
 
 // BindStyledParameterWithOptions binds a parameter as described in the Path Parameters
 // section here to a Go object:
 // https://swagger.io/docs/specification/serialization/
 func BindStyledParameterWithOptions(style string, paramName string, value string, dest any, opts BindStyledParameterOptions) error {
+	o := observeBind()
+	err := bindStyledParameter(style, paramName, value, dest, opts)
+	o.done(opts.OperationID, paramName, opts.ParamLocation, []string{value}, dest, err)
+	return err
+}
+
+func bindStyledParameter(style string, paramName string, value string, dest any, opts BindStyledParameterOptions) error {
 	if opts.Required {
 		if value == "" {
-			return fmt.Errorf("parameter '%s' is empty, can't bind its value", paramName)
+			return &RequiredParamError{ParamName: paramName, Location: opts.ParamLocation}
 		}
 	}
+	limits := resolveLimits(opts.Limits)
+	if err := limits.checkParamLength(paramName, opts.ParamLocation, value); err != nil {
+		return err
+	}
 
 	// Based on the location of the parameter, we need to unescape it properly.
 	var err error
@@ -83,21 +99,42 @@ func BindStyledParameterWithOptions(style string, paramName string, value string
 		// since prior to this refactoring, they always query unescaped.
 		value, err = url.QueryUnescape(value)
 		if err != nil {
-			return fmt.Errorf("error unescaping query parameter '%s': %v", paramName, err)
+			return &InvalidParamFormatError{ParamName: paramName, Location: opts.ParamLocation, Err: err}
 		}
 	case ParamLocationPath:
 		value, err = url.PathUnescape(value)
 		if err != nil {
-			return fmt.Errorf("error unescaping path parameter '%s': %v", paramName, err)
+			return &InvalidParamFormatError{ParamName: paramName, Location: opts.ParamLocation, Err: err}
 		}
 	default:
 		// Headers and cookies aren't escaped.
 	}
 
+	if ab, ok := dest.(ArrayBinder); ok {
+		parts, err := splitStyledParameter(style, opts.Explode, false, paramName, opts.ParamLocation, value)
+		if err != nil {
+			return err
+		}
+		if err := limits.checkArrayItems(paramName, opts.ParamLocation, len(parts)); err != nil {
+			return err
+		}
+		if err := ab.BindArray(parts); err != nil {
+			return &UnmarshalingParamError{ParamName: paramName, Location: opts.ParamLocation, Err: err}
+		}
+		return nil
+	}
+
 	// If the destination implements encoding.TextUnmarshaler we use it for binding
 	if tu, ok := dest.(encoding.TextUnmarshaler); ok {
 		if err := tu.UnmarshalText([]byte(value)); err != nil {
-			return fmt.Errorf("error unmarshaling '%s' text as %T: %s", value, dest, err)
+			return &UnmarshalingParamError{
+				ParamName: paramName,
+				Location:  opts.ParamLocation,
+				Err:       fmt.Errorf("error unmarshaling '%s' text as %T: %w", value, dest, err),
+			}
+		}
+		if err := checkNilUUID(reflect.Indirect(reflect.ValueOf(dest))); err != nil {
+			return &UnmarshalingParamError{ParamName: paramName, Location: opts.ParamLocation, Err: err}
 		}
 
 		return nil
@@ -112,26 +149,40 @@ func BindStyledParameterWithOptions(style string, paramName string, value string
 	if t.Kind() == reflect.Struct {
 		// We've got a destination object, we'll create a JSON representation
 		// of the input value, and let the json library deal with the unmarshaling
-		parts, err := splitStyledParameter(style, opts.Explode, true, paramName, value)
+		parts, err := splitStyledParameter(style, opts.Explode, true, paramName, opts.ParamLocation, value)
 		if err != nil {
 			return err
 		}
 
-		return bindSplitPartsToDestinationStruct(paramName, parts, opts.Explode, dest)
+		return bindSplitPartsToDestinationStruct(paramName, opts.ParamLocation, parts, opts.Explode, dest)
 	}
 
 	if t.Kind() == reflect.Slice {
+		// Arrays of arrays need the outer array's items to be separated
+		// by something other than the inner arrays' commas.
+		if isNestedArray(t) && !(opts.Explode && (style == "form" || style == "matrix" || style == "label")) {
+			return fmt.Errorf("%s: %w", DescribeParam(paramName, opts.ParamLocation), ErrNestedArrayStyle)
+		}
 		// Chop up the parameter into parts based on its style
-		parts, err := splitStyledParameter(style, opts.Explode, false, paramName, value)
+		parts, err := splitStyledParameter(style, opts.Explode, false, paramName, opts.ParamLocation, value)
 		if err != nil {
-			return fmt.Errorf("error splitting input '%s' into parts: %s", value, err)
+			return err
+		}
+		if err := limits.checkArrayItems(paramName, opts.ParamLocation, len(parts)); err != nil {
+			return err
 		}
 
-		return bindSplitPartsToDestinationArray(parts, dest)
+		if err := bindSplitPartsToDestinationArray(parts, dest); err != nil {
+			return &UnmarshalingParamError{ParamName: paramName, Location: opts.ParamLocation, Err: err}
+		}
+		return nil
 	}
 
 	// Try to bind the remaining types as a base type.
-	return BindStringToObject(value, dest)
+	if err := BindStringToObject(value, dest); err != nil {
+		return &UnmarshalingParamError{ParamName: paramName, Location: opts.ParamLocation, Err: err}
+	}
+	return nil
 }
 
 // This is a complex set of operations, but each given parameter style can be
@@ -140,12 +191,16 @@ func BindStyledParameterWithOptions(style string, paramName string, value string
 // as input any parameter format, and unpacks it to a simple list of strings
 // or key-values which we can then treat generically.
 // Why, oh why, great Swagger gods, did you have to make this so complicated?
-func splitStyledParameter(style string, explode bool, object bool, paramName string, value string) ([]string, error) {
+// Values which don't match the style are reported as *InvalidParamFormatError.
+func splitStyledParameter(style string, explode bool, object bool, paramName string, paramLocation ParamLocation, value string) ([]string, error) {
+	invalidFormat := func(format string, args ...interface{}) error {
+		return &InvalidParamFormatError{ParamName: paramName, Location: paramLocation, Err: fmt.Errorf(format, args...)}
+	}
+
 	switch style {
 	case "simple":
 		// In the simple case, we always split on comma
-		parts := strings.Split(value, ",")
-		return parts, nil
+		return splitParts(value, ",", ""), nil
 	case "label":
 		// In the label case, it's more tricky. In the no explode case, we have
 		// /users/.3,4,5 for arrays
@@ -154,79 +209,127 @@ func splitStyledParameter(style string, explode bool, object bool, paramName str
 		// /users/.3.4.5
 		// /users/.role=admin.firstName=Alex
 		if explode {
-			// In the exploded case, split everything on periods.
-			parts := strings.Split(value, ".")
-			// The first part should be an empty string because we have a
-			// leading period.
-			if parts[0] != "" {
-				return nil, fmt.Errorf("invalid format for label parameter '%s', should start with '.'", paramName)
+			// An empty value has no parts at all.
+			if value == "" {
+				return []string{}, nil
 			}
-			return parts[1:], nil
-
+			// Otherwise, it must start with a period, and everything after
+			// it is split on periods.
+			if value[0] != '.' {
+				return nil, invalidFormat("label parameter should start with '.'")
+			}
+			return splitParts(value[1:], ".", ""), nil
 		} else {
 			// In the unexploded case, we strip off the leading period.
-			if value[0] != '.' {
-				return nil, fmt.Errorf("invalid format for label parameter '%s', should start with '.'", paramName)
+			if value == "" || value[0] != '.' {
+				return nil, invalidFormat("label parameter should start with '.'")
 			}
 			// The rest is comma separated.
-			return strings.Split(value[1:], ","), nil
+			return splitParts(value[1:], ",", ""), nil
 		}
 
 	case "matrix":
 		if explode {
-			// In the exploded case, we break everything up on semicolon
-			parts := strings.Split(value, ";")
-			// The first part should always be empty string, since we started
-			// with ;something
-			if parts[0] != "" {
-				return nil, fmt.Errorf("invalid format for matrix parameter '%s', should start with ';'", paramName)
+			// In the exploded case, we break everything up on semicolon, and
+			// the value should always start with one.
+			if value == "" {
+				return []string{}, nil
+			}
+			if value[0] != ';' {
+				return nil, invalidFormat("matrix parameter should start with ';'")
 			}
-			parts = parts[1:]
 			// Now, if we have an object, we just have a list of x=y statements.
 			// for a non-object, like an array, we have id=x, id=y. id=z, etc,
 			// so we need to strip the prefix from each of them.
-			if !object {
-				prefix := paramName + "="
-				for i := range parts {
-					parts[i] = strings.TrimPrefix(parts[i], prefix)
-				}
+			if object {
+				return splitParts(value[1:], ";", ""), nil
 			}
-			return parts, nil
+			return splitParts(value[1:], ";", paramName), nil
 		} else {
 			// In the unexploded case, parameters will start with ;paramName=
-			prefix := ";" + paramName + "="
-			if !strings.HasPrefix(value, prefix) {
-				return nil, fmt.Errorf("expected parameter '%s' to start with %s", paramName, prefix)
+			if !hasMatrixPrefix(value, paramName) {
+				return nil, invalidFormat("expected value to start with %s", ";"+paramName+"=")
 			}
-			str := strings.TrimPrefix(value, prefix)
-			return strings.Split(str, ","), nil
+			return splitParts(value[len(paramName)+2:], ",", ""), nil
 		}
 	case "form":
-		var parts []string
 		if explode {
-			parts = strings.Split(value, "&")
-			if !object {
-				prefix := paramName + "="
-				for i := range parts {
-					parts[i] = strings.TrimPrefix(parts[i], prefix)
-				}
-			}
-			return parts, nil
-		} else {
-			parts = strings.Split(value, ",")
-			prefix := paramName + "="
-			for i := range parts {
-				parts[i] = strings.TrimPrefix(parts[i], prefix)
+			if object {
+				return splitParts(value, "&", ""), nil
 			}
+			return splitParts(value, "&", paramName), nil
+		}
+		return splitParts(value, ",", paramName), nil
+	}
+
+	return nil, fmt.Errorf("%s: unhandled parameter style: %s", DescribeParam(paramName, paramLocation), style)
+}
+
+// splitParts splits s at every occurrence of the single byte separator sep,
+// like strings.Split, and strips a leading "paramName=" from each part when
+// paramName isn't empty. It scans s once, and allocates nothing but the
+// result.
+func splitParts(s string, sep string, paramName string) []string {
+	parts := make([]string, 0, strings.Count(s, sep)+1)
+	for {
+		i := strings.IndexByte(s, sep[0])
+		if i < 0 {
+			return append(parts, trimParamName(s, paramName))
 		}
-		return parts, nil
+		parts = append(parts, trimParamName(s[:i], paramName))
+		s = s[i+1:]
+	}
+}
+
+// trimParamName strips a leading "paramName=" from part.
+func trimParamName(part string, paramName string) string {
+	if paramName != "" && len(part) > len(paramName) && part[len(paramName)] == '=' && strings.HasPrefix(part, paramName) {
+		return part[len(paramName)+1:]
+	}
+	return part
+}
+
+// hasMatrixPrefix reports whether value starts with ";paramName=".
+func hasMatrixPrefix(value string, paramName string) bool {
+	return len(value) > len(paramName)+1 &&
+		value[0] == ';' &&
+		value[len(paramName)+1] == '=' &&
+		strings.HasPrefix(value[1:], paramName)
+}
+
+// ErrNestedArrayStyle is returned when styling or binding an array of
+// arrays in a style which separates the outer array's items with commas,
+// since those can't be told apart from the commas separating the items of
+// the inner arrays. Arrays of arrays are supported in exploded form, matrix
+// and label styles, such as ?m=1,2&m=3,4 for [][]int{{1, 2}, {3, 4}}, and
+// are styled, but not yet bound, in spaceDelimited and pipeDelimited ones.
+var ErrNestedArrayStyle = errors.New("arrays of arrays need a style which doesn't separate items with commas")
+
+// isNestedArray reports whether t is a slice of slices, other than of
+// []byte.
+func isNestedArray(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice {
+		return false
+	}
+	elem := t.Elem()
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
 	}
+	return elem.Kind() == reflect.Slice && elem.Elem().Kind() != reflect.Uint8
+}
 
-	return nil, fmt.Errorf("unhandled parameter style: %s", style)
+// bindArrayParts binds the items of an array parameter to dest, a slice or
+// an ArrayBinder.
+func bindArrayParts(parts []string, dest interface{}) error {
+	if ab, ok := dest.(ArrayBinder); ok {
+		return ab.BindArray(parts)
+	}
+	return bindSplitPartsToDestinationArray(parts, dest)
 }
 
 // Given a set of values as a slice, create a slice to hold them all, and
-// assign to each one by one.
+// assign to each one by one. The items of an array of arrays are split on
+// commas into the inner arrays.
 func bindSplitPartsToDestinationArray(parts []string, dest interface{}) error {
 	// Everything comes in by pointer, dereference it
 	v := reflect.Indirect(reflect.ValueOf(dest))
@@ -238,8 +341,14 @@ func bindSplitPartsToDestinationArray(parts []string, dest interface{}) error {
 	// This generates a slice of the correct element type and length to
 	// hold all the parts.
 	newArray := reflect.MakeSlice(t, len(parts), len(parts))
+	nested := isNestedArray(t)
 	for i, p := range parts {
-		err := BindStringToObject(p, newArray.Index(i).Addr().Interface())
+		var err error
+		if nested {
+			err = bindSplitPartsToDestinationArray(strings.Split(p, ","), newArray.Index(i).Addr().Interface())
+		} else {
+			err = BindStringToObject(p, newArray.Index(i).Addr().Interface())
+		}
 		if err != nil {
 			return fmt.Errorf("error setting array element: %w", err)
 		}
@@ -260,7 +369,7 @@ func bindSplitPartsToDestinationArray(parts []string, dest interface{}) error {
 // We punt the hard work of binding these values to the object to the json
 // library. We'll turn those arrays into JSON strings, and unmarshal
 // into the struct.
-func bindSplitPartsToDestinationStruct(paramName string, parts []string, explode bool, dest interface{}) error {
+func bindSplitPartsToDestinationStruct(paramName string, paramLocation ParamLocation, parts []string, explode bool, dest interface{}) error {
 	// We've got a destination object, we'll create a JSON representation
 	// of the input value, and let the json library deal with the unmarshaling
 	var fields []string
@@ -269,13 +378,21 @@ func bindSplitPartsToDestinationStruct(paramName string, parts []string, explode
 		for i, property := range parts {
 			propertyParts := strings.Split(property, "=")
 			if len(propertyParts) != 2 {
-				return fmt.Errorf("parameter '%s' has invalid exploded format", paramName)
+				return &InvalidParamFormatError{
+					ParamName: paramName,
+					Location:  paramLocation,
+					Err:       fmt.Errorf("property '%s' is not a key=value pair", property),
+				}
 			}
 			fields[i] = "\"" + propertyParts[0] + "\":\"" + propertyParts[1] + "\""
 		}
 	} else {
 		if len(parts)%2 != 0 {
-			return fmt.Errorf("parameter '%s' has invalid format, property/values need to be pairs", paramName)
+			return &InvalidParamFormatError{
+				ParamName: paramName,
+				Location:  paramLocation,
+				Err:       errors.New("property/values need to be pairs"),
+			}
 		}
 		fields = make([]string, len(parts)/2)
 		for i := 0; i < len(parts); i += 2 {
@@ -285,9 +402,9 @@ func bindSplitPartsToDestinationStruct(paramName string, parts []string, explode
 		}
 	}
 	jsonParam := "{" + strings.Join(fields, ",") + "}"
-	err := json.Unmarshal([]byte(jsonParam), dest)
+	err := jsonUnmarshal([]byte(jsonParam), dest)
 	if err != nil {
-		return fmt.Errorf("error binding parameter %s fields: %s", paramName, err)
+		return &UnmarshalingParamError{ParamName: paramName, Location: paramLocation, Err: err}
 	}
 	return nil
 }
@@ -308,6 +425,85 @@ func bindSplitPartsToDestinationStruct(paramName string, parts []string, explode
 // the Content parameter form.
 func BindQueryParameter(style string, explode bool, required bool, paramName string,
 	queryParams url.Values, dest interface{}) error {
+	return BindQueryParameterWithOptions(style, paramName, queryParams, dest, BindQueryParameterOptions{
+		Explode:  explode,
+		Required: required,
+	})
+}
+
+// BindQueryParameterOptions defines optional arguments for BindQueryParameterWithOptions
+type BindQueryParameterOptions struct {
+	// Whether the parameter should use exploded structure
+	Explode bool
+	// Whether the parameter is required in the query
+	Required bool
+	// Limits overrides the package-wide limits set with SetLimits.
+	Limits *Limits
+	// OperationID is passed on to the hooks installed with
+	// SetBindFailureHook and SetBindTraceHook.
+	OperationID string
+	// Warnings, when set, switches to lenient binding, as described for
+	// WithBindWarnings, recording problems in it rather than failing.
+	Warnings *BindWarnings
+}
+
+// BindQueryParameterWithOptions is BindQueryParameter with its optional
+// arguments passed in opts.
+func BindQueryParameterWithOptions(style string, paramName string, queryParams url.Values, dest interface{}, opts BindQueryParameterOptions) error {
+	o := observeBind()
+	err := bindQueryParameter(style, paramName, queryParams, nil, dest, opts)
+	o.done(opts.OperationID, paramName, ParamLocationQuery, queryParams[paramName], dest, err)
+	return err
+}
+
+// BindQueryParameterFromRequest is BindQueryParameter for the query of r.
+// Working from r.URL.RawQuery rather than parsed url.Values, it splits
+// unexploded form parameters on their literal commas before unescaping
+// them, so that items holding an escaped comma, %2C, come out whole, and
+// binds deepObject parameters as UnmarshalDeepObjectFromRawQuery does.
+func BindQueryParameterFromRequest(r *http.Request, style string, explode bool, required bool, paramName string, dest interface{}) error {
+	return BindQueryParameterFromRequestWithOptions(r, style, paramName, dest, BindQueryParameterOptions{
+		Explode:  explode,
+		Required: required,
+	})
+}
+
+// BindQueryParameterFromRequestWithOptions is BindQueryParameterFromRequest
+// with its optional arguments passed in opts.
+func BindQueryParameterFromRequestWithOptions(r *http.Request, style string, paramName string, dest interface{}, opts BindQueryParameterOptions) error {
+	queryParams := r.URL.Query()
+	o := observeBind()
+	err := bindQueryParameter(style, paramName, queryParams, &r.URL.RawQuery, dest, opts)
+	o.done(opts.OperationID, paramName, ParamLocationQuery, queryParams[paramName], dest, err)
+	return err
+}
+
+// findRawQueryParam returns the values of the parameter paramName in
+// rawQuery, still escaped.
+func findRawQueryParam(rawQuery string, paramName string) []string {
+	var values []string
+	for rawQuery != "" {
+		var pair string
+		pair, rawQuery, _ = strings.Cut(rawQuery, "&")
+		rawKey, rawValue, _ := strings.Cut(pair, "=")
+		if key, err := url.QueryUnescape(rawKey); err == nil && key == paramName {
+			values = append(values, rawValue)
+		}
+	}
+	return values
+}
+
+// bindQueryParameter binds the query parameter paramName. rawQuery, when
+// set, is the query queryParams were parsed from, to split unexploded
+// parameters before unescaping them.
+func bindQueryParameter(style string, paramName string, queryParams url.Values, rawQuery *string, dest interface{}, opts BindQueryParameterOptions) error {
+	explode, required := opts.Explode, opts.Required
+	limits := resolveLimits(opts.Limits)
+	for _, value := range queryParams[paramName] {
+		if err := limits.checkParamLength(paramName, ParamLocationQuery, value); err != nil {
+			return err
+		}
+	}
 
 	// dv = destination value.
 	dv := reflect.Indirect(reflect.ValueOf(dest))
@@ -353,6 +549,10 @@ func BindQueryParameter(style string, explode bool, required bool, paramName str
 	// This is the basic type of the destination object.
 	t := v.Type()
 	k := t.Kind()
+	// An ArrayBinder is bound like a slice.
+	if _, ok := output.(ArrayBinder); ok {
+		k = reflect.Slice
+	}
 
 	switch style {
 	case "form":
@@ -372,20 +572,25 @@ func BindQueryParameter(style string, explode bool, required bool, paramName str
 
 				if !found {
 					if required {
-						return fmt.Errorf("query parameter '%s' is required", paramName)
+						return &RequiredParamError{ParamName: paramName, Location: ParamLocationQuery}
 					} else {
 						// If an optional parameter is not found, we do nothing,
 						return nil
 					}
 				}
-				err = bindSplitPartsToDestinationArray(values, output)
+				if err = limits.checkArrayItems(paramName, ParamLocationQuery, len(values)); err != nil {
+					return err
+				}
+				if err = bindArrayParts(values, output); err != nil {
+					err = &UnmarshalingParamError{ParamName: paramName, Location: ParamLocationQuery, Err: err}
+				}
 			case reflect.Struct:
 				// This case is really annoying, and error prone, but the
 				// form style object binding doesn't tell us which arguments
 				// in the query string correspond to the object's fields. We'll
 				// try to bind field by field.
 				var fieldsPresent bool
-				fieldsPresent, err = bindParamsToExplodedObject(paramName, queryParams, output)
+				fieldsPresent, err = bindParamsToExplodedObject(paramName, queryParams, output, limits)
 				// If no fields were set, and there is no error, we will not fall
 				// through to assign the destination.
 				if !fieldsPresent {
@@ -396,24 +601,26 @@ func BindQueryParameter(style string, explode bool, required bool, paramName str
 				// unmarshal.
 				if len(values) == 0 {
 					if required {
-						return fmt.Errorf("query parameter '%s' is required", paramName)
+						return &RequiredParamError{ParamName: paramName, Location: ParamLocationQuery}
 					} else {
 						return nil
 					}
 				}
 				if len(values) != 1 {
-					return fmt.Errorf("multiple values for single value parameter '%s'", paramName)
+					return &TooManyValuesError{ParamName: paramName, Location: ParamLocationQuery}
 				}
 
 				if !found {
 					if required {
-						return fmt.Errorf("query parameter '%s' is required", paramName)
+						return &RequiredParamError{ParamName: paramName, Location: ParamLocationQuery}
 					} else {
 						// If an optional parameter is not found, we do nothing,
 						return nil
 					}
 				}
-				err = BindStringToObject(values[0], output)
+				if err = BindStringToObject(values[0], output); err != nil {
+					err = &UnmarshalingParamError{ParamName: paramName, Location: ParamLocationQuery, Err: err}
+				}
 			}
 			if err != nil {
 				return err
@@ -429,34 +636,61 @@ func BindQueryParameter(style string, explode bool, required bool, paramName str
 			values, found := queryParams[paramName]
 			if !found {
 				if required {
-					return fmt.Errorf("query parameter '%s' is required", paramName)
+					return &RequiredParamError{ParamName: paramName, Location: ParamLocationQuery}
 				} else {
 					return nil
 				}
 			}
 			if len(values) != 1 {
-				return fmt.Errorf("parameter '%s' is not exploded, but is specified multiple times", paramName)
+				return &TooManyValuesError{
+					ParamName: paramName,
+					Location:  ParamLocationQuery,
+					Err:       errors.New("parameter is not exploded, but is specified multiple times"),
+				}
+			}
+			if rawQuery == nil {
+				parts = strings.Split(values[0], ",")
+			} else {
+				// queryParams held the value, so the raw query does too.
+				rawParts := strings.Split(findRawQueryParam(*rawQuery, paramName)[0], ",")
+				parts = make([]string, len(rawParts))
+				for i, rawPart := range rawParts {
+					part, err := url.QueryUnescape(rawPart)
+					if err != nil {
+						return &InvalidParamFormatError{ParamName: paramName, Location: ParamLocationQuery, Err: err}
+					}
+					parts[i] = part
+				}
 			}
-			parts = strings.Split(values[0], ",")
 		}
 		var err error
 		switch k {
 		case reflect.Slice:
-			err = bindSplitPartsToDestinationArray(parts, output)
+			if !explode && isNestedArray(t) {
+				return fmt.Errorf("%s: %w", DescribeParam(paramName, ParamLocationQuery), ErrNestedArrayStyle)
+			}
+			if err = limits.checkArrayItems(paramName, ParamLocationQuery, len(parts)); err != nil {
+				return err
+			}
+			if err = bindArrayParts(parts, output); err != nil {
+				err = &UnmarshalingParamError{ParamName: paramName, Location: ParamLocationQuery, Err: err}
+			}
 		case reflect.Struct:
-			err = bindSplitPartsToDestinationStruct(paramName, parts, explode, output)
+			err = bindSplitPartsToDestinationStruct(paramName, ParamLocationQuery, parts, explode, output)
 		default:
 			if len(parts) == 0 {
 				if required {
-					return fmt.Errorf("query parameter '%s' is required", paramName)
+					return &RequiredParamError{ParamName: paramName, Location: ParamLocationQuery}
 				} else {
 					return nil
 				}
 			}
 			if len(parts) != 1 {
-				return fmt.Errorf("multiple values for single value parameter '%s'", paramName)
+				return &TooManyValuesError{ParamName: paramName, Location: ParamLocationQuery}
+			}
+			if err = BindStringToObject(parts[0], output); err != nil {
+				err = &UnmarshalingParamError{ParamName: paramName, Location: ParamLocationQuery, Err: err}
 			}
-			err = BindStringToObject(parts[0], output)
 		}
 		if err != nil {
 			return err
@@ -467,13 +701,17 @@ func BindQueryParameter(style string, explode bool, required bool, paramName str
 		return nil
 	case "deepObject":
 		if !explode {
-			return errors.New("deepObjects must be exploded")
+			return fmt.Errorf("%s: deepObjects must be exploded", DescribeParam(paramName, ParamLocationQuery))
+		}
+		warn := opts.Warnings.paramWarner(paramName, ParamLocationQuery)
+		if rawQuery != nil {
+			return unmarshalDeepObjectFromRawQuery(dest, paramName, *rawQuery, limits, warn)
 		}
-		return UnmarshalDeepObject(dest, paramName, queryParams)
+		return unmarshalDeepObject(dest, paramName, queryParams, limits, warn)
 	case "spaceDelimited", "pipeDelimited":
-		return fmt.Errorf("query arguments of style '%s' aren't yet supported", style)
+		return fmt.Errorf("%s: query arguments of style '%s' aren't yet supported", DescribeParam(paramName, ParamLocationQuery), style)
 	default:
-		return fmt.Errorf("style '%s' on parameter '%s' is invalid", style, paramName)
+		return fmt.Errorf("%s: style '%s' is invalid", DescribeParam(paramName, ParamLocationQuery), style)
 
 	}
 }
@@ -485,7 +723,7 @@ func BindQueryParameter(style string, explode bool, required bool, paramName str
 // set its value. This function returns a boolean, telling us whether there was
 // anything to bind. There will be nothing to bind if a parameter isn't found by name,
 // or none of an exploded object's fields are present.
-func bindParamsToExplodedObject(paramName string, values url.Values, dest interface{}) (bool, error) {
+func bindParamsToExplodedObject(paramName string, values url.Values, dest interface{}, limits Limits) (bool, error) {
 	// Dereference pointers to their destination values
 	binder, v, t := indirect(dest)
 	if binder != nil {
@@ -493,10 +731,13 @@ func bindParamsToExplodedObject(paramName string, values url.Values, dest interf
 		if !found {
 			return false, nil
 		}
-		return true, BindStringToObject(values.Get(paramName), dest)
+		if err := BindStringToObject(values.Get(paramName), dest); err != nil {
+			return true, &UnmarshalingParamError{ParamName: paramName, Location: ParamLocationQuery, Err: err}
+		}
+		return true, nil
 	}
 	if t.Kind() != reflect.Struct {
-		return false, fmt.Errorf("unmarshaling query arg '%s' into wrong type", paramName)
+		return false, fmt.Errorf("%s: unmarshaling into wrong type %s", DescribeParam(paramName, ParamLocationQuery), t)
 	}
 
 	fieldsPresent := false
@@ -524,11 +765,22 @@ func bindParamsToExplodedObject(paramName string, values url.Values, dest interf
 		fieldVal, found := values[fieldName]
 		if found {
 			if len(fieldVal) != 1 {
-				return false, fmt.Errorf("field '%s' specified multiple times for param '%s'", fieldName, paramName)
+				return false, &TooManyValuesError{
+					ParamName: paramName,
+					Location:  ParamLocationQuery,
+					Err:       fmt.Errorf("field '%s' is specified multiple times", fieldName),
+				}
+			}
+			if err := limits.checkParamLength(paramName, ParamLocationQuery, fieldVal[0]); err != nil {
+				return false, err
 			}
 			err := BindStringToObject(fieldVal[0], v.Field(i).Addr().Interface())
 			if err != nil {
-				return false, fmt.Errorf("could not bind query arg '%s' to request object: %s'", paramName, err)
+				return false, &UnmarshalingParamError{
+					ParamName: paramName,
+					Location:  ParamLocationQuery,
+					Err:       fmt.Errorf("field '%s': %w", fieldName, err),
+				}
 			}
 			fieldsPresent = true
 		}
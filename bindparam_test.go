@@ -17,6 +17,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 	"time"
@@ -99,6 +101,7 @@ func TestSplitParameter(t *testing.T) {
 		false,
 		false,
 		"id",
+		ParamLocationUndefined,
 		"5")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedPrimitive, result)
@@ -107,6 +110,7 @@ func TestSplitParameter(t *testing.T) {
 		false,
 		false,
 		"id",
+		ParamLocationUndefined,
 		"3,4,5")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedArray, result)
@@ -115,6 +119,7 @@ func TestSplitParameter(t *testing.T) {
 		false,
 		true,
 		"id",
+		ParamLocationUndefined,
 		"role,admin,firstName,Alex")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedObject, result)
@@ -123,6 +128,7 @@ func TestSplitParameter(t *testing.T) {
 		true,
 		false,
 		"id",
+		ParamLocationUndefined,
 		"5")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedPrimitive, result)
@@ -131,6 +137,7 @@ func TestSplitParameter(t *testing.T) {
 		true,
 		false,
 		"id",
+		ParamLocationUndefined,
 		"3,4,5")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedArray, result)
@@ -139,6 +146,7 @@ func TestSplitParameter(t *testing.T) {
 		true,
 		true,
 		"id",
+		ParamLocationUndefined,
 		"role=admin,firstName=Alex")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedExplodedObject, result)
@@ -148,6 +156,7 @@ func TestSplitParameter(t *testing.T) {
 		false,
 		false,
 		"id",
+		ParamLocationUndefined,
 		".5")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedPrimitive, result)
@@ -156,6 +165,7 @@ func TestSplitParameter(t *testing.T) {
 		false,
 		false,
 		"id",
+		ParamLocationUndefined,
 		".3,4,5")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedArray, result)
@@ -164,6 +174,7 @@ func TestSplitParameter(t *testing.T) {
 		false,
 		true,
 		"id",
+		ParamLocationUndefined,
 		".role,admin,firstName,Alex")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedObject, result)
@@ -172,6 +183,7 @@ func TestSplitParameter(t *testing.T) {
 		true,
 		false,
 		"id",
+		ParamLocationUndefined,
 		".5")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedPrimitive, result)
@@ -180,6 +192,7 @@ func TestSplitParameter(t *testing.T) {
 		true,
 		false,
 		"id",
+		ParamLocationUndefined,
 		".3.4.5")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedArray, result)
@@ -188,6 +201,7 @@ func TestSplitParameter(t *testing.T) {
 		true,
 		true,
 		"id",
+		ParamLocationUndefined,
 		".role=admin.firstName=Alex")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedExplodedObject, result)
@@ -197,6 +211,7 @@ func TestSplitParameter(t *testing.T) {
 		false,
 		false,
 		"id",
+		ParamLocationUndefined,
 		";id=5")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedPrimitive, result)
@@ -205,6 +220,7 @@ func TestSplitParameter(t *testing.T) {
 		false,
 		false,
 		"id",
+		ParamLocationUndefined,
 		";id=3,4,5")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedArray, result)
@@ -213,6 +229,7 @@ func TestSplitParameter(t *testing.T) {
 		false,
 		true,
 		"id",
+		ParamLocationUndefined,
 		";id=role,admin,firstName,Alex")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedObject, result)
@@ -221,6 +238,7 @@ func TestSplitParameter(t *testing.T) {
 		true,
 		false,
 		"id",
+		ParamLocationUndefined,
 		";id=5")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedPrimitive, result)
@@ -229,6 +247,7 @@ func TestSplitParameter(t *testing.T) {
 		true,
 		false,
 		"id",
+		ParamLocationUndefined,
 		";id=3;id=4;id=5")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedArray, result)
@@ -237,6 +256,7 @@ func TestSplitParameter(t *testing.T) {
 		true,
 		true,
 		"id",
+		ParamLocationUndefined,
 		";role=admin;firstName=Alex")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedExplodedObject, result)
@@ -246,6 +266,7 @@ func TestSplitParameter(t *testing.T) {
 		false,
 		false,
 		"id",
+		ParamLocationUndefined,
 		"id=5")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedPrimitive, result)
@@ -254,6 +275,7 @@ func TestSplitParameter(t *testing.T) {
 		false,
 		false,
 		"id",
+		ParamLocationUndefined,
 		"id=3,4,5")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedArray, result)
@@ -262,6 +284,7 @@ func TestSplitParameter(t *testing.T) {
 		false,
 		true,
 		"id",
+		ParamLocationUndefined,
 		"id=role,admin,firstName,Alex")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedObject, result)
@@ -270,6 +293,7 @@ func TestSplitParameter(t *testing.T) {
 		true,
 		false,
 		"id",
+		ParamLocationUndefined,
 		"id=5")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedPrimitive, result)
@@ -278,6 +302,7 @@ func TestSplitParameter(t *testing.T) {
 		true,
 		false,
 		"id",
+		ParamLocationUndefined,
 		"id=3&id=4&id=5")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedArray, result)
@@ -286,6 +311,7 @@ func TestSplitParameter(t *testing.T) {
 		true,
 		true,
 		"id",
+		ParamLocationUndefined,
 		"role=admin&firstName=Alex")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedExplodedObject, result)
@@ -465,14 +491,14 @@ func TestBindParamsToExplodedObject(t *testing.T) {
 	}
 
 	var dstTime time.Time
-	fieldsPresent, err := bindParamsToExplodedObject("time", values, &dstTime)
+	fieldsPresent, err := bindParamsToExplodedObject("time", values, &dstTime, Limits{})
 	assert.NoError(t, err)
 	assert.True(t, fieldsPresent)
 	assert.EqualValues(t, now, dstTime)
 
 	type AliasedTime time.Time
 	var aDstTime AliasedTime
-	fieldsPresent, err = bindParamsToExplodedObject("time", values, &aDstTime)
+	fieldsPresent, err = bindParamsToExplodedObject("time", values, &aDstTime, Limits{})
 	assert.NoError(t, err)
 	assert.True(t, fieldsPresent)
 	assert.EqualValues(t, now, aDstTime)
@@ -480,19 +506,19 @@ func TestBindParamsToExplodedObject(t *testing.T) {
 	expectedDate := MockBinder{Time: time.Date(2020, 11, 6, 0, 0, 0, 0, time.UTC)}
 
 	var dstDate MockBinder
-	fieldsPresent, err = bindParamsToExplodedObject("date", values, &dstDate)
+	fieldsPresent, err = bindParamsToExplodedObject("date", values, &dstDate, Limits{})
 	assert.NoError(t, err)
 	assert.True(t, fieldsPresent)
 	assert.EqualValues(t, expectedDate, dstDate)
 
 	var eDstDate EmbeddedMockBinder
-	fieldsPresent, err = bindParamsToExplodedObject("date", values, &eDstDate)
+	fieldsPresent, err = bindParamsToExplodedObject("date", values, &eDstDate, Limits{})
 	assert.NoError(t, err)
 	assert.True(t, fieldsPresent)
 	assert.EqualValues(t, expectedDate, dstDate)
 
 	var nTDstDate AnotherMockBinder
-	fieldsPresent, err = bindParamsToExplodedObject("date", values, &nTDstDate)
+	fieldsPresent, err = bindParamsToExplodedObject("date", values, &nTDstDate, Limits{})
 	assert.NoError(t, err)
 	assert.True(t, fieldsPresent)
 	assert.EqualValues(t, expectedDate, nTDstDate)
@@ -502,7 +528,7 @@ func TestBindParamsToExplodedObject(t *testing.T) {
 	}
 
 	var optDstTime ObjectWithOptional
-	fieldsPresent, err = bindParamsToExplodedObject("explodedObject", values, &optDstTime)
+	fieldsPresent, err = bindParamsToExplodedObject("explodedObject", values, &optDstTime, Limits{})
 	assert.NoError(t, err)
 	assert.True(t, fieldsPresent)
 	assert.EqualValues(t, &now, optDstTime.Time)
@@ -521,3 +547,118 @@ func TestBindStyledParameterWithLocation(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, *expectedBig, dstBigNumber)
 }
+
+type petStatus string
+
+func (petStatus) EnumValues() []petStatus { return []petStatus{"available", "sold"} }
+
+func TestBindEnumParameter(t *testing.T) {
+	var status types.Enum[petStatus]
+	err := BindQueryParameter("form", true, true, "status", url.Values{"status": {"sold"}}, &status)
+	require.NoError(t, err)
+	assert.Equal(t, petStatus("sold"), status.Value)
+
+	err = BindStyledParameterWithOptions("simple", "status", "lost", &status, BindStyledParameterOptions{
+		ParamLocation: ParamLocationPath,
+	})
+	assert.ErrorIs(t, err, types.ErrInvalidEnumValue)
+
+	styled, err := StyleParamWithLocation("form", true, "status", ParamLocationQuery, types.NewEnum[petStatus]("available"))
+	require.NoError(t, err)
+	assert.Equal(t, "status=available", styled)
+}
+
+func BenchmarkSplitStyledParameter(b *testing.B) {
+	cases := []struct {
+		style   string
+		explode bool
+		object  bool
+		value   string
+	}{
+		{"simple", false, false, "3,4,5,6,7,8"},
+		{"label", true, true, ".role=admin.firstName=Alex.lastName=Smith"},
+		{"matrix", true, false, ";id=3;id=4;id=5;id=6"},
+		{"matrix", false, false, ";id=3,4,5,6"},
+		{"form", true, false, "id=3&id=4&id=5&id=6"},
+		{"form", false, true, "id=role,admin,firstName,Alex"},
+	}
+	for _, c := range cases {
+		b.Run(fmt.Sprintf("%s/explode=%v/object=%v", c.style, c.explode, c.object), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := splitStyledParameter(c.style, c.explode, c.object, "id", ParamLocationQuery, c.value); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestBindNestedArrays(t *testing.T) {
+	var matrix [][]int
+	require.NoError(t, BindQueryParameter("form", true, true, "m", url.Values{"m": {"1,2", "3,4"}}, &matrix))
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}}, matrix)
+
+	matrix = nil
+	require.NoError(t, BindStyledParameterWithOptions("matrix", "m", ";m=1,2;m=3", &matrix, BindStyledParameterOptions{
+		ParamLocation: ParamLocationPath,
+		Explode:       true,
+	}))
+	assert.Equal(t, [][]int{{1, 2}, {3}}, matrix)
+
+	err := BindQueryParameter("form", false, true, "m", url.Values{"m": {"1,2,3,4"}}, &matrix)
+	assert.ErrorIs(t, err, ErrNestedArrayStyle)
+	err = BindStyledParameterWithOptions("simple", "m", "1,2", &matrix, BindStyledParameterOptions{ParamLocation: ParamLocationPath})
+	assert.ErrorIs(t, err, ErrNestedArrayStyle)
+
+	var optional *[][]string
+	require.NoError(t, BindQueryParameter("form", true, false, "m", url.Values{"m": {"a,b"}}, &optional))
+	require.NotNil(t, optional)
+	assert.Equal(t, [][]string{{"a", "b"}}, *optional)
+}
+
+func TestBindParameter_Set(t *testing.T) {
+	var ids types.Set[int]
+	require.NoError(t, BindStyledParameterWithOptions("label", "ids", ".3.1.2", &ids, BindStyledParameterOptions{
+		ParamLocation: ParamLocationPath,
+		Explode:       true,
+	}))
+	assert.Equal(t, []int{1, 2, 3}, ids.Items())
+
+	var tags *types.Set[string]
+	require.NoError(t, BindQueryParameter("form", false, false, "tags", url.Values{"tags": {"b,a"}}, &tags))
+	require.NotNil(t, tags)
+	assert.Equal(t, []string{"a", "b"}, tags.Items())
+
+	err := BindQueryParameter("form", true, true, "ids", url.Values{"ids": {"1", "1"}}, &ids)
+	var unmarshalErr *UnmarshalingParamError
+	assert.ErrorAs(t, err, &unmarshalErr)
+	assert.ErrorIs(t, err, types.ErrDuplicateSetItem)
+}
+
+func TestBindQueryParameterFromRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/?tags=a%2Cb,c&name=x%2Cy&filter[Name]=n&filter[ID]=3", nil)
+
+	var tags []string
+	require.NoError(t, BindQueryParameterFromRequest(r, "form", false, true, "tags", &tags))
+	assert.Equal(t, []string{"a,b", "c"}, tags)
+
+	// Parsed url.Values lose the distinction between escaped and literal
+	// commas.
+	tags = nil
+	require.NoError(t, BindQueryParameter("form", false, true, "tags", r.URL.Query(), &tags))
+	assert.Equal(t, []string{"a", "b", "c"}, tags)
+
+	var name string
+	require.NoError(t, BindQueryParameterFromRequest(r, "form", false, true, "name", &name))
+	assert.Equal(t, "x,y", name)
+
+	var filter *InnerObject
+	require.NoError(t, BindQueryParameterFromRequest(r, "deepObject", true, false, "filter", &filter))
+	assert.Equal(t, &InnerObject{Name: "n", ID: 3}, filter)
+
+	var missing []string
+	err := BindQueryParameterFromRequest(r, "form", false, true, "missing", &missing)
+	var required *RequiredParamError
+	assert.ErrorAs(t, err, &required)
+}
@@ -0,0 +1,246 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// RequiredBodyError is returned by BindRequest when a required request body
+// is missing or empty.
+type RequiredBodyError struct{}
+
+func (e *RequiredBodyError) Error() string {
+	return "request body is required"
+}
+
+// BindingErrors collects the errors of every field BindRequest failed to
+// bind, so that a client learns about all of its mistakes at once.
+type BindingErrors []error
+
+func (e BindingErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors of every field, so that errors.Is and errors.As
+// reach each of them.
+func (e BindingErrors) Unwrap() []error {
+	return e
+}
+
+// BindRequest binds an entire request to the struct dest points to, such as
+// a generated RequestObject, in place of one generated bind call per
+// parameter. Each field to bind carries a param struct tag, as described
+// for BuildRequestURL, naming its location:
+//
+//	ID     string    `param:"id,in=path"`
+//	Limit  *int      `param:"limit,in=query"`
+//	Key    string    `param:"X-Key,in=header,required"`
+//	Token  *string   `param:"token,in=cookie"`
+//	Body   *NewPet   `param:",in=body,required"`
+//
+// pathParams holds the path parameters as the router extracted them, still
+// escaped. Parameters are bound with the same functions generated servers
+// call, and optional ones are left untouched when absent; pointer fields are
+// only allocated when there is a value for them. The body is decoded
+// according to its Content-Type: JSON with BindJSONBody, form-urlencoded
+// with BindURLEncodedBody, multipart/form-data with BindMultipartBody, and
+// any other type into a []byte, string or io.Reader field.
+//
+// Every field is attempted, and failures are returned together as
+// BindingErrors. Parameters are reported to the hooks installed with
+// SetBindFailureHook and SetBindTraceHook, along with the operation ID in
+// the request context. Once everything is bound, dest, body included, is
+// run through the validator installed with SetValidator. A context set up with
+// WithBindWarnings switches to lenient binding.
+func BindRequest(r *http.Request, pathParams map[string]string, dest any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.New("request destination should be a pointer to a struct")
+	}
+	v = v.Elem()
+	t := v.Type()
+	query := r.URL.Query()
+	operationID := GetOperationID(r.Context())
+
+	var errs BindingErrors
+	for i := 0; i < t.NumField(); i++ {
+		pt, ok, err := parseParamTag(t.Field(i))
+		if err != nil {
+			return err
+		}
+		if !ok || !v.Field(i).CanSet() {
+			continue
+		}
+		o := observeBind()
+		err = bindRequestField(r, pathParams, query, pt, v.Field(i))
+		if !pt.Body {
+			o.done(operationID, pt.Name, pt.Location, rawRequestParam(r, pathParams, query, pt), v.Field(i).Addr().Interface(), err)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return validateBound(dest)
+}
+
+func bindRequestField(r *http.Request, pathParams map[string]string, query url.Values, pt paramTag, field reflect.Value) error {
+	opts := BindStyledParameterOptions{
+		ParamLocation: pt.Location,
+		Explode:       pt.Explode,
+		Required:      pt.Required,
+	}
+	switch {
+	case pt.Body:
+		return bindRequestBody(r, pt.Required, field)
+	case pt.Location == ParamLocationQuery:
+		return bindQueryParameter(pt.Style, pt.Name, query, &r.URL.RawQuery, field.Addr().Interface(), BindQueryParameterOptions{
+			Explode:  pt.Explode,
+			Required: pt.Required,
+			Warnings: BindWarningsFromContext(r.Context()),
+		})
+	case pt.Location == ParamLocationPath:
+		value, found := pathParams[pt.Name]
+		if !found {
+			return &RequiredParamError{ParamName: pt.Name, Location: pt.Location}
+		}
+		return setRequestField(field, func(dest interface{}) error {
+			return bindStyledParameter(pt.Style, pt.Name, value, dest, opts)
+		})
+	case pt.Location == ParamLocationHeader:
+		values := HeaderValues(r.Header, pt.Name)
+		if len(values) == 0 {
+			if pt.Required {
+				return &RequiredParamError{ParamName: pt.Name, Location: pt.Location}
+			}
+			return nil
+		}
+		if len(values) > 1 {
+			return &TooManyValuesError{ParamName: pt.Name, Location: pt.Location}
+		}
+		return setRequestField(field, func(dest interface{}) error {
+			return bindStyledParameter(pt.Style, pt.Name, values[0], dest, opts)
+		})
+	default:
+		cookie, err := r.Cookie(pt.Name)
+		if err != nil {
+			if pt.Required {
+				return &RequiredParamError{ParamName: pt.Name, Location: pt.Location}
+			}
+			return nil
+		}
+		return setRequestField(field, func(dest interface{}) error {
+			return bindStyledParameter(pt.Style, pt.Name, cookie.Value, dest, opts)
+		})
+	}
+}
+
+// rawRequestParam returns the values r carries for the parameter pt.
+func rawRequestParam(r *http.Request, pathParams map[string]string, query url.Values, pt paramTag) []string {
+	switch pt.Location {
+	case ParamLocationQuery:
+		return query[pt.Name]
+	case ParamLocationPath:
+		if value, ok := pathParams[pt.Name]; ok {
+			return []string{value}
+		}
+	case ParamLocationHeader:
+		return HeaderValues(r.Header, pt.Name)
+	case ParamLocationCookie:
+		if cookie, err := r.Cookie(pt.Name); err == nil {
+			return []string{cookie.Value}
+		}
+	}
+	return nil
+}
+
+// setRequestField binds into field, allocating it first if it is a pointer,
+// in which case it is only set once bind succeeds.
+func setRequestField(field reflect.Value, bind func(dest interface{}) error) error {
+	if field.Kind() != reflect.Ptr {
+		return bind(field.Addr().Interface())
+	}
+	elem := reflect.New(field.Type().Elem())
+	if err := bind(elem.Interface()); err != nil {
+		return err
+	}
+	field.Set(elem)
+	return nil
+}
+
+// bindRequestBody decodes the body of r into field by its Content-Type.
+func bindRequestBody(r *http.Request, required bool, field reflect.Value) error {
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+		if required {
+			return &RequiredBodyError{}
+		}
+		return nil
+	}
+	if field.Type() == readerType {
+		field.Set(reflect.ValueOf(r.Body))
+		return nil
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil && contentType != "" {
+		return fmt.Errorf("error parsing request content type '%s': %w", contentType, err)
+	}
+	return setRequestField(field, func(dest interface{}) error {
+		switch {
+		case isJSONMediaType(mediaType):
+			return decodeJSONBody(r.Body, dest, BindJSONBodyOptions{
+				DisallowUnknownFields: disallowUnknownFields.Load(),
+				UseNumber:             useJSONNumber.Load(),
+				Warnings:              BindWarningsFromContext(r.Context()),
+			})
+		case mediaType == urlEncodedContentType:
+			data, err := io.ReadAll(r.Body)
+			if err != nil {
+				return fmt.Errorf("error reading request body: %w", err)
+			}
+			values, err := url.ParseQuery(string(data))
+			if err != nil {
+				return fmt.Errorf("error parsing form-urlencoded body: %w", err)
+			}
+			return bindURLEncodedBody(values, dest, nil)
+		case mediaType == "multipart/form-data":
+			reader, err := r.MultipartReader()
+			if err != nil {
+				return err
+			}
+			return bindMultipartBody(reader, dest, GetLimits())
+		}
+		switch d := dest.(type) {
+		case *[]byte:
+			data, err := io.ReadAll(r.Body)
+			if err != nil {
+				return fmt.Errorf("error reading request body: %w", err)
+			}
+			*d = data
+			return nil
+		case *string:
+			data, err := io.ReadAll(r.Body)
+			if err != nil {
+				return fmt.Errorf("error reading request body: %w", err)
+			}
+			*d = string(data)
+			return nil
+		}
+		return fmt.Errorf("can not bind request body with content type '%s' to destination of type %T", contentType, dest)
+	})
+}
+
+var readerType = reflect.TypeOf((*io.Reader)(nil)).Elem()
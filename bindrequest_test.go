@@ -0,0 +1,120 @@
+package runtime
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bindRequestPet struct {
+	Name string `json:"name"`
+}
+
+type bindRequestObject struct {
+	ID      int             `param:"id,in=path"`
+	Tags    []string        `param:"tags,in=query"`
+	Limit   *int            `param:"limit,in=query"`
+	Key     string          `param:"X-Key,in=header,required"`
+	Trace   *string         `param:"X-Trace,in=header"`
+	Session string          `param:"session,in=cookie"`
+	Body    *bindRequestPet `param:",in=body,required"`
+}
+
+func TestBindRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/pets/5?tags=a&tags=b", strings.NewReader(`{"name":"Rex"}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-Key", "secret")
+	r.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+
+	var obj bindRequestObject
+	require.NoError(t, BindRequest(r, map[string]string{"id": "5"}, &obj))
+	assert.Equal(t, 5, obj.ID)
+	assert.Equal(t, []string{"a", "b"}, obj.Tags)
+	assert.Nil(t, obj.Limit)
+	assert.Equal(t, "secret", obj.Key)
+	assert.Nil(t, obj.Trace)
+	assert.Equal(t, "abc", obj.Session)
+	require.NotNil(t, obj.Body)
+	assert.Equal(t, "Rex", obj.Body.Name)
+}
+
+func TestBindRequest_Body(t *testing.T) {
+	type Form struct {
+		Name string `json:"name"`
+	}
+	var form struct {
+		Body Form `param:",in=body"`
+	}
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name=Rex"))
+	r.Header.Set("Content-Type", urlEncodedContentType)
+	require.NoError(t, BindRequest(r, nil, &form))
+	assert.Equal(t, "Rex", form.Body.Name)
+
+	var raw struct {
+		Body []byte `param:",in=body"`
+	}
+	r = httptest.NewRequest(http.MethodPut, "/", strings.NewReader("data"))
+	r.Header.Set("Content-Type", "application/octet-stream")
+	require.NoError(t, BindRequest(r, nil, &raw))
+	assert.Equal(t, []byte("data"), raw.Body)
+
+	raw.Body = nil
+	r = httptest.NewRequest(http.MethodPut, "/", nil)
+	require.NoError(t, BindRequest(r, nil, &raw))
+	assert.Nil(t, raw.Body)
+}
+
+func TestBindRequest_AggregatesErrors(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/pets/x?limit=many", nil)
+
+	var obj bindRequestObject
+	err := BindRequest(r, map[string]string{"id": "x"}, &obj)
+	var errs BindingErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 4)
+
+	var bindingErr BindingError
+	require.True(t, errors.As(errs[0], &bindingErr))
+	assert.Equal(t, "id", bindingErr.Param())
+	assert.Equal(t, ParamLocationPath, bindingErr.In())
+	require.True(t, errors.As(errs[1], &bindingErr))
+	assert.Equal(t, "limit", bindingErr.Param())
+	var required *RequiredParamError
+	require.True(t, errors.As(errs[2], &required))
+	assert.Equal(t, "X-Key", required.ParamName)
+	var requiredBody *RequiredBodyError
+	assert.ErrorAs(t, errs[3], &requiredBody)
+
+	// Each error is reachable through the aggregate.
+	required = nil
+	require.True(t, errors.As(err, &required))
+	assert.Equal(t, "X-Key", required.ParamName)
+}
+
+func TestBindRequest_Validates(t *testing.T) {
+	defer SetValidator(nil)
+	var validated []interface{}
+	SetValidator(func(v interface{}) error {
+		validated = append(validated, v)
+		if obj, ok := v.(*bindRequestObject); ok && obj.ID < 10 {
+			return errors.New("id is too small")
+		}
+		return nil
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/pets/5", strings.NewReader(`{"name":"Rex"}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-Key", "secret")
+
+	var obj bindRequestObject
+	err := BindRequest(r, map[string]string{"id": "5"}, &obj)
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	// The body is validated as part of the request object, not twice.
+	assert.Equal(t, []interface{}{&obj}, validated)
+}
@@ -0,0 +1,106 @@
+package runtime
+
+import (
+	"encoding"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// BindResponse decodes the body of resp into dest, choosing how to decode it
+// from the response's Content-Type:
+//
+//   - JSON media types, including structured suffixes such as
+//     application/problem+json, are unmarshaled with encoding/json.
+//   - text/* bodies may be bound to a *string or an encoding.TextUnmarshaler.
+//   - Any body, including application/octet-stream, may be bound to a
+//     *[]byte, or passed through unread to an *io.Reader or *io.ReadCloser.
+//
+// JSON and text bodies declaring a charset other than UTF-8 are transcoded
+// to UTF-8 first.
+//
+// Responses without a body, such as 204 and 304, or to a HEAD request, leave
+// dest untouched. Unless dest is an *io.Reader or *io.ReadCloser, in which
+// case the caller takes ownership of the body, the body is closed before
+// BindResponse returns, having been read to the end, so that resp.Trailer
+// is complete.
+func BindResponse(resp *http.Response, dest interface{}) error {
+	switch d := dest.(type) {
+	case *io.ReadCloser:
+		*d = resp.Body
+		return nil
+	case *io.Reader:
+		*d = resp.Body
+		return nil
+	}
+	if resp.Body != nil {
+		// Responses built by hand, as in tests, may have a nil Body.
+		defer func() { _ = resp.Body.Close() }()
+	}
+
+	if !responseHasBody(resp) {
+		return nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body: %w", err)
+	}
+	if b, ok := dest.(*[]byte); ok {
+		*b = body
+		return nil
+	}
+	if len(body) == 0 {
+		return nil
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil && contentType != "" {
+		return fmt.Errorf("error parsing response content type '%s': %w", contentType, err)
+	}
+	if isJSONMediaType(mediaType) || strings.HasPrefix(mediaType, "text/") {
+		if body, err = DecodeCharset(body, contentType, CharsetOptions{}); err != nil {
+			return err
+		}
+	}
+	switch {
+	case isJSONMediaType(mediaType):
+		if err := jsonUnmarshal(body, dest); err != nil {
+			return fmt.Errorf("error unmarshaling %s response body into %T: %w", mediaType, dest, err)
+		}
+		return nil
+	case strings.HasPrefix(mediaType, "text/"):
+		switch d := dest.(type) {
+		case *string:
+			*d = string(body)
+			return nil
+		case encoding.TextUnmarshaler:
+			if err := d.UnmarshalText(body); err != nil {
+				return fmt.Errorf("error unmarshaling %s response body into %T: %w", mediaType, dest, err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("can not bind response with content type '%s' to destination of type %T", contentType, dest)
+}
+
+// responseHasBody reports whether resp can carry a body at all.
+func responseHasBody(resp *http.Response) bool {
+	switch {
+	case resp.StatusCode == http.StatusNoContent, resp.StatusCode == http.StatusNotModified:
+		return false
+	case resp.StatusCode >= 100 && resp.StatusCode < 200:
+		return false
+	case resp.Request != nil && resp.Request.Method == http.MethodHead:
+		return false
+	}
+	return resp.Body != nil && resp.Body != http.NoBody
+}
+
+// isJSONMediaType reports whether mediaType is application/json or a JSON
+// structured syntax suffix type such as application/problem+json.
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == jsonContentType || strings.HasSuffix(mediaType, "+json")
+}
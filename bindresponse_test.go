@@ -0,0 +1,103 @@
+package runtime
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oapi-codegen/runtime/types"
+)
+
+type closeTracker struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTracker) Close() error {
+	c.closed = true
+	return nil
+}
+
+func newTestResponse(status int, contentType, body string) (*http.Response, *closeTracker) {
+	tracker := &closeTracker{Reader: strings.NewReader(body)}
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	return &http.Response{StatusCode: status, Header: header, Body: tracker}, tracker
+}
+
+func TestBindResponse(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		var dest struct {
+			Name string `json:"name"`
+		}
+		resp, body := newTestResponse(http.StatusOK, "application/json; charset=utf-8", `{"name":"rex"}`)
+		require.NoError(t, BindResponse(resp, &dest))
+		assert.Equal(t, "rex", dest.Name)
+		assert.True(t, body.closed)
+	})
+
+	t.Run("nil body", func(t *testing.T) {
+		dest := "untouched"
+		resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": {"text/plain"}}}
+		require.NoError(t, BindResponse(resp, &dest))
+		assert.Equal(t, "untouched", dest)
+	})
+
+	t.Run("problem+json", func(t *testing.T) {
+		var dest map[string]interface{}
+		resp, _ := newTestResponse(http.StatusConflict, "application/problem+json", `{"title":"conflict","status":409}`)
+		require.NoError(t, BindResponse(resp, &dest))
+		assert.Equal(t, "conflict", dest["title"])
+	})
+
+	t.Run("text into string", func(t *testing.T) {
+		var dest string
+		resp, _ := newTestResponse(http.StatusOK, "text/plain", "pong")
+		require.NoError(t, BindResponse(resp, &dest))
+		assert.Equal(t, "pong", dest)
+	})
+
+	t.Run("text into TextUnmarshaler", func(t *testing.T) {
+		var dest types.Date
+		resp, _ := newTestResponse(http.StatusOK, "text/plain", "2024-01-02")
+		require.NoError(t, BindResponse(resp, &dest))
+		assert.Equal(t, "2024-01-02", dest.String())
+	})
+
+	t.Run("octet-stream into bytes", func(t *testing.T) {
+		var dest []byte
+		resp, _ := newTestResponse(http.StatusOK, "application/octet-stream", "\x00\x01")
+		require.NoError(t, BindResponse(resp, &dest))
+		assert.Equal(t, []byte{0, 1}, dest)
+	})
+
+	t.Run("reader is passed through", func(t *testing.T) {
+		var dest io.ReadCloser
+		resp, body := newTestResponse(http.StatusOK, "application/octet-stream", "stream")
+		require.NoError(t, BindResponse(resp, &dest))
+		assert.False(t, body.closed)
+		data, err := io.ReadAll(dest)
+		require.NoError(t, err)
+		assert.Equal(t, "stream", string(data))
+	})
+
+	t.Run("no content", func(t *testing.T) {
+		dest := "untouched"
+		resp, body := newTestResponse(http.StatusNoContent, "", "")
+		require.NoError(t, BindResponse(resp, &dest))
+		assert.Equal(t, "untouched", dest)
+		assert.True(t, body.closed)
+	})
+
+	t.Run("unsupported content type", func(t *testing.T) {
+		var dest struct{}
+		resp, _ := newTestResponse(http.StatusOK, "application/octet-stream", "data")
+		assert.Error(t, BindResponse(resp, &dest))
+	})
+}
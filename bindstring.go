@@ -29,7 +29,8 @@ import (
 // via reflection instead of a much simpler type switch so that we can handle
 // type aliases. This function was the easy way out, the better way, since we
 // know the destination type each place that we use this, is to generate code
-// to read each specific type.
+// to read each specific type. An interface{} destination is set to the
+// value the ParamInferrer installed with SetParamInferrer makes of src.
 func BindStringToObject(src string, dst interface{}) error {
 	var err error
 
@@ -80,8 +81,12 @@ func BindStringToObject(src string, dst interface{}) error {
 			v.SetUint(val)
 		}
 	case reflect.String:
-		v.SetString(src)
-		err = nil
+		// String types may validate their values, as types.Email does.
+		if tu, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			err = tu.UnmarshalText([]byte(src))
+		} else {
+			v.SetString(src)
+		}
 	case reflect.Float64, reflect.Float32:
 		var val float64
 		val, err = strconv.ParseFloat(src, 64)
@@ -97,13 +102,20 @@ func BindStringToObject(src string, dst interface{}) error {
 		if err == nil {
 			v.SetBool(val)
 		}
+	case reflect.Interface:
+		// An interface{} declares no type to parse src as, so infer one.
+		if t.NumMethod() > 0 {
+			err = fmt.Errorf("can not bind to destination of type: %s", t)
+			break
+		}
+		setInferred(v, inferParam(src))
 	case reflect.Array:
 		if tu, ok := dst.(encoding.TextUnmarshaler); ok {
 			if err := tu.UnmarshalText([]byte(src)); err != nil {
 				return fmt.Errorf("error unmarshaling '%s' text as %T: %s", src, dst, err)
 			}
 
-			return nil
+			return checkNilUUID(v)
 		}
 		fallthrough
 	case reflect.Struct:
@@ -143,11 +155,10 @@ func BindStringToObject(src string, dst interface{}) error {
 			if src == "" {
 				return nil
 			}
-			parsedTime, err := time.Parse(types.DateFormat, src)
-			if err != nil {
-				return fmt.Errorf("error parsing '%s' as date: %s", src, err)
+			var parsedDate types.Date
+			if err := parsedDate.UnmarshalText([]byte(src)); err != nil {
+				return fmt.Errorf("error parsing '%s' as date: %w", src, err)
 			}
-			parsedDate := types.Date{Time: parsedTime}
 
 			// We have to do the same dance here to assign, just like with times
 			// above.
@@ -172,3 +183,15 @@ func BindStringToObject(src string, dst interface{}) error {
 	}
 	return nil
 }
+
+var uuidType = reflect.TypeOf(types.UUID{})
+
+// checkNilUUID fails the bound value v if it is a UUID holding the nil UUID
+// while types.SetRejectNilUUID is in effect. Other arrays of 16 bytes, which
+// a UUID converts to, are left alone, since all zeros may be valid for them.
+func checkNilUUID(v reflect.Value) error {
+	if v.Type() != uuidType {
+		return nil
+	}
+	return types.CheckUUID(v.Interface().(types.UUID))
+}
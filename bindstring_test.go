@@ -14,8 +14,10 @@
 package runtime
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"net/url"
 	"testing"
 	"time"
 
@@ -209,3 +211,80 @@ func TestBindStringToObject(t *testing.T) {
 	assert.Equal(t, dstUUID.String(), uuidString)
 
 }
+
+func TestBindStringToObject_DateRange(t *testing.T) {
+	defer types.SetDateRange(types.DateRange{})
+	types.SetDateRange(types.DateRange{Min: types.Date{Time: time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)}})
+
+	var date types.Date
+	err := BindStringToObject("1850-06-01", &date)
+	var rangeErr *types.DateOutOfRangeError
+	assert.True(t, errors.As(err, &rangeErr))
+
+	var obj struct {
+		Born types.Date `json:"born"`
+	}
+	err = UnmarshalDeepObject(&obj, "p", url.Values{"p[born]": {"1850-06-01"}})
+	assert.True(t, errors.As(err, &rangeErr))
+
+	// A RangedDate is held to its own range instead.
+	var expiry types.RangedDate[notBefore2000]
+	assert.NoError(t, BindStringToObject("2024-06-01", &expiry))
+	assert.True(t, errors.As(BindStringToObject("1950-06-01", &expiry), &rangeErr))
+	var ranged struct {
+		Expiry types.RangedDate[notBefore2000] `json:"expiry"`
+	}
+	err = UnmarshalDeepObject(&ranged, "p", url.Values{"p[expiry]": {"1950-06-01"}})
+	assert.True(t, errors.As(err, &rangeErr))
+}
+
+type notBefore2000 struct{}
+
+func (notBefore2000) DateRange() types.DateRange {
+	return types.DateRange{Min: types.Date{Time: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)}}
+}
+
+func TestBindStringToObject_NilUUID(t *testing.T) {
+	const nilUUID = "00000000-0000-0000-0000-000000000000"
+	var id types.UUID
+	assert.NoError(t, BindStringToObject(nilUUID, &id))
+
+	defer types.SetRejectNilUUID(false)
+	types.SetRejectNilUUID(true)
+	assert.ErrorIs(t, BindStringToObject(nilUUID, &id), types.ErrNilUUID)
+
+	err := BindStyledParameterWithOptions("simple", "id", nilUUID, &id, BindStyledParameterOptions{ParamLocation: ParamLocationPath})
+	var unmarshaling *UnmarshalingParamError
+	assert.True(t, errors.As(err, &unmarshaling))
+	assert.ErrorIs(t, err, types.ErrNilUUID)
+
+	assert.NoError(t, BindStringToObject("bbca1470-5e1f-4c64-ba99-fa7a6d2687b0", &id))
+
+	// Other arrays of 16 bytes aren't UUIDs.
+	var d digest
+	assert.NoError(t, BindStringToObject("00", &d))
+}
+
+// digest is an array of 16 bytes, as a UUID is, which may be all zeros.
+type digest [16]byte
+
+func (d *digest) UnmarshalText(text []byte) error {
+	*d = digest{}
+	return nil
+}
+
+func TestBindStringToObject_Email(t *testing.T) {
+	var email types.Email
+	assert.NoError(t, BindStringToObject("alex@example.com", &email))
+	assert.Equal(t, types.Email("alex@example.com"), email)
+	// Parameters are only validated while a domain policy is installed.
+	assert.NoError(t, BindStringToObject("alex", &email))
+
+	defer types.SetEmailDomainPolicy(nil)
+	types.SetEmailDomainPolicy(func(domain string) error {
+		return errors.New("not a corporate address")
+	})
+	var domainErr *types.EmailDomainError
+	assert.True(t, errors.As(BindStringToObject("alex@example.com", &email), &domainErr))
+	assert.ErrorIs(t, BindStringToObject("alex", &email), types.ErrValidationEmail)
+}
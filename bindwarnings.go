@@ -0,0 +1,367 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// BindWarning is a problem found while binding leniently which would fail
+// binding otherwise, but which binding recovered from.
+type BindWarning struct {
+	// ParamName and Location identify the parameter the problem was found
+	// in. They are empty for problems found in a body.
+	ParamName string
+	Location  ParamLocation
+	Err       error
+}
+
+func (w BindWarning) String() string {
+	if w.ParamName == "" {
+		return "body: " + w.Err.Error()
+	}
+	return DescribeParam(w.ParamName, w.Location) + ": " + w.Err.Error()
+}
+
+// BindWarnings records the warnings of lenient binding. It is safe for
+// concurrent use.
+type BindWarnings struct {
+	mu       sync.Mutex
+	warnings []BindWarning
+}
+
+// Add records a warning.
+func (w *BindWarnings) Add(warning BindWarning) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.warnings = append(w.warnings, warning)
+}
+
+// Warnings returns the warnings recorded so far.
+func (w *BindWarnings) Warnings() []BindWarning {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]BindWarning(nil), w.warnings...)
+}
+
+type bindWarningsContextKey struct{}
+
+// WithBindWarnings returns a copy of ctx carrying a new BindWarnings, which
+// switches BindRequest to lenient binding for requests with that context, as
+// suits migration periods and permissive public APIs. Rather than failing,
+// binding then records these problems as warnings and proceeds with what it
+// could bind:
+//
+//   - JSON bodies with a property the destination has no field for, when
+//     unknown fields are disallowed, are decoded as if they were allowed.
+//   - Data following a JSON body, which is ignored either way, is reported.
+//   - deepObject keys the destination has no field for are skipped.
+//   - types.Enum values of JSON bodies and deepObject parameters which
+//     aren't one of the allowed values are kept, as they are while
+//     types.SetLenientEnums is on, as are other values which report
+//     themselves invalid through an IsValid method.
+//
+// A router middleware typically installs it, and the handler reads the
+// warnings back with BindWarningsFromContext, to log them or relay them to
+// the client.
+func WithBindWarnings(ctx context.Context) (context.Context, *BindWarnings) {
+	w := &BindWarnings{}
+	return context.WithValue(ctx, bindWarningsContextKey{}, w), w
+}
+
+// BindWarningsFromContext returns the BindWarnings stored in ctx by
+// WithBindWarnings, or nil if there is none.
+func BindWarningsFromContext(ctx context.Context) *BindWarnings {
+	w, _ := ctx.Value(bindWarningsContextKey{}).(*BindWarnings)
+	return w
+}
+
+// paramWarner returns a function recording warnings about a parameter in w,
+// or nil, meaning binding is strict, if w is nil.
+func (w *BindWarnings) paramWarner(paramName string, location ParamLocation) func(error) {
+	if w == nil {
+		return nil
+	}
+	return func(err error) {
+		w.Add(BindWarning{ParamName: paramName, Location: location, Err: err})
+	}
+}
+
+type validityChecker interface {
+	IsValid() bool
+}
+
+// warnInvalidValues calls warn for every value reachable from v which
+// reports itself invalid, naming it by its path of JSON property names.
+func warnInvalidValues(v reflect.Value, path string, warn func(error)) {
+	if !v.IsValid() || (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return
+	}
+	if v.CanInterface() {
+		if c, ok := v.Interface().(validityChecker); ok {
+			if !c.IsValid() {
+				if path == "" {
+					warn(fmt.Errorf("value %v is not one of the allowed values", c))
+				} else {
+					warn(fmt.Errorf("property '%s': value %v is not one of the allowed values", path, c))
+				}
+			}
+			return
+		}
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		warnInvalidValues(v.Elem(), path, warn)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			switch {
+			case !f.IsExported():
+			case f.Anonymous && f.Tag.Get("json") == "":
+				// The fields of embedded structs are promoted.
+				warnInvalidValues(v.Field(i), path, warn)
+			default:
+				warnInvalidValues(v.Field(i), joinPath(path, getFieldName(f)), warn)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			warnInvalidValues(v.Index(i), path+"["+strconv.Itoa(i)+"]", warn)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			warnInvalidValues(iter.Value(), joinPath(path, fmt.Sprint(iter.Key())), warn)
+		}
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// lenientJSONUnmarshaler and lenientBinder are implemented by types.Enum, to
+// accept values it doesn't list while binding leniently.
+type lenientJSONUnmarshaler interface {
+	UnmarshalJSONLenient(data []byte) error
+}
+
+type lenientBinder interface {
+	BindLenient(src string) error
+}
+
+var lenientJSONUnmarshalerType = reflect.TypeOf((*lenientJSONUnmarshaler)(nil)).Elem()
+
+// unmarshalJSONLenient unmarshals data into v, which must be settable, with
+// unmarshal, except that values unmarshaling leniently, wherever they are
+// in v, are unmarshaled that way. Only the parts of v holding such values
+// are walked, and everything else is left to unmarshal.
+func unmarshalJSONLenient(data []byte, v reflect.Value, unmarshal func([]byte, interface{}) error) error {
+	t := v.Type()
+	if reflect.PtrTo(t).Implements(lenientJSONUnmarshalerType) {
+		return v.Addr().Interface().(lenientJSONUnmarshaler).UnmarshalJSONLenient(data)
+	}
+	if !hasLenientJSON(t) {
+		return unmarshal(data, v.Addr().Interface())
+	}
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map:
+			v.Set(reflect.Zero(t))
+		}
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+		return unmarshalJSONLenient(data, v.Elem(), unmarshal)
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if err := unmarshal(data, &items); err != nil {
+			return err
+		}
+		if t.Kind() == reflect.Slice {
+			v.Set(reflect.MakeSlice(t, len(items), len(items)))
+		}
+		for i := 0; i < v.Len(); i++ {
+			if i >= len(items) {
+				v.Index(i).Set(reflect.Zero(t.Elem()))
+				continue
+			}
+			if err := unmarshalJSONLenient(items[i], v.Index(i), unmarshal); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		var object map[string]json.RawMessage
+		if err := unmarshal(data, &object); err != nil {
+			return err
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(t, len(object)))
+		}
+		for key, raw := range object {
+			elem := reflect.New(t.Elem()).Elem()
+			if err := unmarshalJSONLenient(raw, elem, unmarshal); err != nil {
+				return err
+			}
+			v.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), elem)
+		}
+		return nil
+	}
+
+	// A struct: the properties of fields which hold lenient values are
+	// unmarshaled here, and all others by unmarshal, so that it keeps
+	// handling their tag options.
+	var object map[string]json.RawMessage
+	if err := unmarshal(data, &object); err != nil {
+		return err
+	}
+	fields := lenientJSONFields(t)
+	rest := make(map[string]json.RawMessage, len(object))
+	for name, raw := range object {
+		index, ok := lookupJSONField(fields, name)
+		if !ok {
+			rest[name] = raw
+			continue
+		}
+		if err := unmarshalJSONLenient(raw, fieldByIndexAlloc(v, index), unmarshal); err != nil {
+			return fmt.Errorf("error unmarshaling property '%s': %w", name, err)
+		}
+	}
+	if len(rest) == 0 {
+		return nil
+	}
+	restData, err := jsonMarshal(rest)
+	if err != nil {
+		return err
+	}
+	return unmarshal(restData, v.Addr().Interface())
+}
+
+var hasLenientJSONCache sync.Map // reflect.Type -> bool
+
+// hasLenientJSON reports whether values of type t may hold values which
+// unmarshal leniently, in fields, elements or values of string keyed maps
+// which unmarshal doesn't leave to a json.Unmarshaler of their own.
+func hasLenientJSON(t reflect.Type) bool {
+	if has, ok := hasLenientJSONCache.Load(t); ok {
+		return has.(bool)
+	}
+	has := findLenientJSON(t, map[reflect.Type]bool{})
+	hasLenientJSONCache.Store(t, has)
+	return has
+}
+
+// findLenientJSON is hasLenientJSON for a type found in the types of seen.
+// A recursive type is assumed to hold lenient values where it refers back to
+// itself, which costs walking it, but never misses any.
+func findLenientJSON(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return true
+	}
+	seen[t] = true
+	defer delete(seen, t)
+	switch {
+	case reflect.PtrTo(t).Implements(lenientJSONUnmarshalerType):
+		return true
+	case reflect.PtrTo(t).Implements(jsonUnmarshalerType), reflect.PtrTo(t).Implements(textUnmarshalerType):
+		return false
+	case t.Kind() == reflect.Ptr, t.Kind() == reflect.Slice, t.Kind() == reflect.Array:
+		return findLenientJSON(t.Elem(), seen)
+	case t.Kind() == reflect.Map:
+		return t.Key().Kind() == reflect.String && findLenientJSON(t.Elem(), seen)
+	case t.Kind() == reflect.Struct:
+		return len(collectLenientJSONFields(t, seen)) > 0
+	}
+	return false
+}
+
+// lenientJSONField is a field holding values which unmarshal leniently.
+type lenientJSONField struct {
+	name  string
+	index []int
+}
+
+// lenientJSONFields returns the fields of the struct type t, including
+// those promoted from embedded structs, which hold values unmarshaling
+// leniently.
+func lenientJSONFields(t reflect.Type) []lenientJSONField {
+	return collectLenientJSONFields(t, map[reflect.Type]bool{t: true})
+}
+
+func collectLenientJSONFields(t reflect.Type, seen map[reflect.Type]bool) []lenientJSONField {
+	var fields []lenientJSONField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get(tagName)
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr && field.IsExported() {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for _, f := range collectLenientJSONFields(embedded, seen) {
+					fields = append(fields, lenientJSONField{name: f.name, index: append([]int{i}, f.index...)})
+				}
+				continue
+			}
+		}
+		if !field.IsExported() || strings.Contains(tag, ",string") || !findLenientJSON(field.Type, seen) {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, lenientJSONField{name: name, index: []int{i}})
+	}
+	return fields
+}
+
+// lookupJSONField returns the index of the field of fields named name,
+// preferring an exact match to a case-insensitive one, as encoding/json
+// does.
+func lookupJSONField(fields []lenientJSONField, name string) ([]int, bool) {
+	for _, f := range fields {
+		if f.name == name {
+			return f.index, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.name, name) {
+			return f.index, true
+		}
+	}
+	return nil, false
+}
+
+// fieldByIndexAlloc is v.FieldByIndex, except that it allocates the nil
+// embedded struct pointers on the way.
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
@@ -0,0 +1,137 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/oapi-codegen/runtime/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type warnColor string
+
+func (warnColor) EnumValues() []warnColor {
+	return []warnColor{"red", "green"}
+}
+
+func TestBindJSONBody_Warnings(t *testing.T) {
+	types.SetLenientEnums(true)
+	defer types.SetLenientEnums(false)
+
+	var dest struct {
+		Name   string                            `json:"name"`
+		Colors []types.Enum[warnColor]           `json:"colors"`
+		Extra  map[string]*types.Enum[warnColor] `json:"extra"`
+	}
+	body := `{"name":"a","colors":["red","blue"],"extra":{"k":"pink","n":null},"age":3} {}`
+
+	err := BindJSONBodyWithOptions(strings.NewReader(body), &dest, BindJSONBodyOptions{DisallowUnknownFields: true})
+	assert.Equal(t, &UnknownFieldError{Field: "age"}, err)
+
+	var warnings BindWarnings
+	require.NoError(t, BindJSONBodyWithOptions(strings.NewReader(body), &dest, BindJSONBodyOptions{
+		DisallowUnknownFields: true,
+		Warnings:              &warnings,
+	}))
+	assert.Equal(t, "a", dest.Name)
+	var msgs []string
+	for _, w := range warnings.Warnings() {
+		msgs = append(msgs, w.String())
+	}
+	assert.Equal(t, []string{
+		"body: unknown field 'age'",
+		"body: unexpected data after JSON body",
+		"body: property 'colors[1]': value blue is not one of the allowed values",
+		"body: property 'extra.k': value pink is not one of the allowed values",
+	}, msgs)
+}
+
+func TestBindRequest_Warnings(t *testing.T) {
+	var dest struct {
+		Filter struct {
+			Name string `json:"name"`
+		} `param:"filter,in=query,style=deepObject,explode"`
+	}
+	r := httptest.NewRequest(http.MethodGet, "/?"+url.Values{"filter[name]": {"x"}, "filter[sort]": {"asc"}}.Encode(), nil)
+	assert.Error(t, BindRequest(r, nil, &dest))
+
+	ctx, warnings := WithBindWarnings(context.Background())
+	require.NoError(t, BindRequest(r.WithContext(ctx), nil, &dest))
+	assert.Equal(t, "x", dest.Filter.Name)
+	require.Len(t, warnings.Warnings(), 1)
+	w := warnings.Warnings()[0]
+	assert.Equal(t, "filter", w.ParamName)
+	assert.Equal(t, ParamLocationQuery, w.Location)
+	assert.EqualError(t, w.Err, "field [sort] is not present in destination object")
+	assert.Same(t, warnings, BindWarningsFromContext(ctx))
+	assert.Nil(t, BindWarningsFromContext(context.Background()))
+}
+
+type WarnEmbedded struct {
+	Shade types.Enum[warnColor] `json:"shade"`
+}
+
+func TestBindJSONBody_LenientEnums(t *testing.T) {
+	type dest struct {
+		*WarnEmbedded
+		Name   string                            `json:"name"`
+		Count  int64                             `json:"count,string"`
+		Color  types.Enum[warnColor]             `json:"color"`
+		Colors []types.Enum[warnColor]           `json:"colors"`
+		Extra  map[string]*types.Enum[warnColor] `json:"extra"`
+	}
+	body := `{"Name":"a","count":"7","COLOR":"blue","colors":["red","pink"],"extra":{"k":"grey","n":null},"shade":"teal"}`
+
+	var strict dest
+	assert.ErrorIs(t, BindJSONBody(strings.NewReader(body), &strict), types.ErrInvalidEnumValue)
+
+	// Binding leniently accepts the values without SetLenientEnums.
+	ctx, warnings := WithBindWarnings(context.Background())
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	var lenient struct {
+		Body dest `param:",in=body"`
+	}
+	require.NoError(t, BindRequest(r.WithContext(ctx), nil, &lenient))
+	got := lenient.Body
+	assert.Equal(t, "a", got.Name)
+	assert.Equal(t, int64(7), got.Count)
+	assert.Equal(t, warnColor("blue"), got.Color.Value)
+	assert.Equal(t, warnColor("pink"), got.Colors[1].Value)
+	assert.Equal(t, warnColor("grey"), got.Extra["k"].Value)
+	assert.Nil(t, got.Extra["n"])
+	assert.Equal(t, warnColor("teal"), got.Shade.Value)
+	var msgs []string
+	for _, w := range warnings.Warnings() {
+		msgs = append(msgs, w.String())
+	}
+	assert.ElementsMatch(t, []string{
+		"body: property 'shade': value teal is not one of the allowed values",
+		"body: property 'color': value blue is not one of the allowed values",
+		"body: property 'colors[1]': value pink is not one of the allowed values",
+		"body: property 'extra.k': value grey is not one of the allowed values",
+	}, msgs)
+}
+
+func TestBindQueryParameter_LenientEnums(t *testing.T) {
+	type filter struct {
+		Color types.Enum[warnColor] `json:"color"`
+	}
+	query := url.Values{"filter[color]": {"blue"}}
+	var dest filter
+	assert.Error(t, BindQueryParameterWithOptions("deepObject", "filter", query, &dest, BindQueryParameterOptions{Explode: true}))
+
+	var warnings BindWarnings
+	require.NoError(t, BindQueryParameterWithOptions("deepObject", "filter", query, &dest, BindQueryParameterOptions{
+		Explode:  true,
+		Warnings: &warnings,
+	}))
+	assert.Equal(t, warnColor("blue"), dest.Color.Value)
+	require.Len(t, warnings.Warnings(), 1)
+	assert.Equal(t, "query parameter 'filter': property 'color': value blue is not one of the allowed values", warnings.Warnings()[0].String())
+}
@@ -0,0 +1,89 @@
+package runtime
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"net/http"
+)
+
+// BufferRequestBody reads up to maxBytes of the body of r into memory, so
+// that logging or auditing middleware can inspect the raw payload, and
+// replaces r.Body with a reader which returns the complete body again, so
+// that handlers and binders can still consume it. It reports whether the body
+// was longer than maxBytes, in which case only the first maxBytes are
+// returned, and the remainder is streamed from the original body. When
+// reading fails, r.Body still returns what was read before the error. A
+// negative maxBytes buffers nothing, like 0.
+func BufferRequestBody(r *http.Request, maxBytes int64) ([]byte, bool, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, false, nil
+	}
+	if maxBytes < 0 {
+		maxBytes = 0
+	}
+	// Read one byte more than allowed to find out whether there is more.
+	limit := maxBytes
+	if limit < math.MaxInt64 {
+		limit++
+	}
+	buffered, err := io.ReadAll(io.LimitReader(r.Body, limit))
+	original := r.Body
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(buffered), original), original}
+	if err != nil {
+		return nil, false, err
+	}
+	truncated := int64(len(buffered)) > maxBytes
+	if !truncated {
+		r.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(buffered)), nil
+		}
+		return buffered, false, nil
+	}
+	return buffered[:maxBytes], true, nil
+}
+
+// TeeRequestBody replaces the body of r with one which copies everything
+// read from it to w, as it is read. Unlike BufferRequestBody, it doesn't
+// delay the handler, but w only sees as much of the body as the handler
+// consumed. A CappedBuffer makes a suitable w.
+func TeeRequestBody(r *http.Request, w io.Writer) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return
+	}
+	original := r.Body
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.TeeReader(original, w), original}
+}
+
+// CappedBuffer is an io.Writer which keeps the first Max bytes written to it
+// and silently discards the rest, so that capturing a body can't exhaust
+// memory or fail the request it is captured from.
+type CappedBuffer struct {
+	Max int
+	buf bytes.Buffer
+	// Truncated is set once data has been discarded.
+	Truncated bool
+}
+
+func (b *CappedBuffer) Write(p []byte) (int, error) {
+	if room := b.Max - b.buf.Len(); len(p) > room {
+		b.Truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	b.buf.Write(p)
+	return len(p), nil
+}
+
+// Bytes returns the data kept so far.
+func (b *CappedBuffer) Bytes() []byte {
+	return b.buf.Bytes()
+}
@@ -0,0 +1,84 @@
+package runtime
+
+import (
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferRequestBody(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/pets", strings.NewReader(`{"name":"rex"}`))
+	buffered, truncated, err := BufferRequestBody(r, 1024)
+	require.NoError(t, err)
+	assert.False(t, truncated)
+	assert.Equal(t, `{"name":"rex"}`, string(buffered))
+
+	var pet struct {
+		Name string `json:"name"`
+	}
+	require.NoError(t, BindJSONBody(r.Body, &pet))
+	assert.Equal(t, "rex", pet.Name)
+	require.NotNil(t, r.GetBody)
+
+	r = httptest.NewRequest(http.MethodPost, "/pets", strings.NewReader("0123456789"))
+	buffered, truncated, err = BufferRequestBody(r, 4)
+	require.NoError(t, err)
+	assert.True(t, truncated)
+	assert.Equal(t, "0123", string(buffered))
+	data, err := io.ReadAll(r.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(data))
+
+	r = httptest.NewRequest(http.MethodPost, "/pets", strings.NewReader("0123"))
+	buffered, truncated, err = BufferRequestBody(r, -1)
+	require.NoError(t, err)
+	assert.True(t, truncated)
+	assert.Empty(t, buffered)
+	data, err = io.ReadAll(r.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "0123", string(data))
+
+	r = httptest.NewRequest(http.MethodPost, "/pets", strings.NewReader("0123"))
+	buffered, truncated, err = BufferRequestBody(r, math.MaxInt64)
+	require.NoError(t, err)
+	assert.False(t, truncated)
+	assert.Equal(t, "0123", string(buffered))
+
+	r = httptest.NewRequest(http.MethodGet, "/pets", nil)
+	buffered, truncated, err = BufferRequestBody(r, 4)
+	require.NoError(t, err)
+	assert.False(t, truncated)
+	assert.Nil(t, buffered)
+}
+
+func TestBufferRequestBody_ReadError(t *testing.T) {
+	readErr := errors.New("connection reset")
+	r := httptest.NewRequest(http.MethodPost, "/pets", io.MultiReader(strings.NewReader("01"), iotest.ErrReader(readErr)))
+	_, _, err := BufferRequestBody(r, 1024)
+	require.ErrorIs(t, err, readErr)
+
+	// The part read before the error isn't lost.
+	data, err := io.ReadAll(r.Body)
+	assert.ErrorIs(t, err, readErr)
+	assert.Equal(t, "01", string(data))
+}
+
+func TestTeeRequestBody(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/pets", strings.NewReader("0123456789"))
+	captured := &CappedBuffer{Max: 4}
+	TeeRequestBody(r, captured)
+
+	data, err := io.ReadAll(r.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(data))
+	assert.Equal(t, "0123", string(captured.Bytes()))
+	assert.True(t, captured.Truncated)
+}
@@ -0,0 +1,85 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// BuildRequestURL returns the URL for calling the operation at pathTemplate,
+// such as "/pets/{petId}", on the server at serverURL. Path and query
+// parameters are taken from the fields of params which carry a param struct
+// tag, and styled exactly like generated clients style them; header and
+// cookie parameters and the body are ignored. The path is appended to
+// serverURL with JoinServerURL, so a base path on the server URL is kept.
+// params may be a struct, a pointer to one, or nil for operations without
+// parameters.
+//
+// Optional parameters are pointers in generated params structs, and are left
+// out of the URL when nil. Query parameters are added in the order of the
+// fields, as styled, so that the delimiters of non-exploded arrays aren't
+// escaped.
+func BuildRequestURL(serverURL string, pathTemplate string, params interface{}) (string, error) {
+	var query OrderedQuery
+	operationPath := pathTemplate
+
+	if params != nil {
+		v := reflect.Indirect(reflect.ValueOf(params))
+		if v.Kind() != reflect.Struct {
+			return "", errors.New("params should be a struct")
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			pt, ok, err := parseParamTag(t.Field(i))
+			if err != nil {
+				return "", err
+			}
+			if !ok {
+				continue
+			}
+			field := v.Field(i)
+			if field.Kind() == reflect.Ptr && field.IsNil() {
+				if pt.Required {
+					return "", fmt.Errorf("required parameter '%s' is not set", pt.Name)
+				}
+				continue
+			}
+
+			switch pt.Location {
+			case ParamLocationPath:
+				placeholder := "{" + pt.Name + "}"
+				if !strings.Contains(operationPath, placeholder) {
+					return "", fmt.Errorf("path parameter '%s' does not appear in path template '%s'", pt.Name, pathTemplate)
+				}
+				styled, err := StyleParamWithLocation(pt.Style, pt.Explode, pt.Name, ParamLocationPath, field.Interface())
+				if err != nil {
+					return "", err
+				}
+				operationPath = strings.ReplaceAll(operationPath, placeholder, styled)
+			case ParamLocationQuery:
+				if err := query.AddStyled(pt.Style, pt.Explode, pt.Name, field.Interface()); err != nil {
+					return "", err
+				}
+			}
+		}
+	}
+
+	if strings.Contains(operationPath, "{") {
+		return "", fmt.Errorf("path template '%s' has unfilled parameters: %s", pathTemplate, operationPath)
+	}
+
+	joined, err := JoinServerURL(serverURL, operationPath)
+	if err != nil {
+		return "", err
+	}
+	queryURL, err := url.Parse(joined)
+	if err != nil {
+		return "", err
+	}
+	if len(query) > 0 {
+		queryURL.RawQuery = query.Encode()
+	}
+	return queryURL.String(), nil
+}
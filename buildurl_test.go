@@ -0,0 +1,74 @@
+package runtime
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildRequestURL(t *testing.T) {
+	type Filter struct {
+		Color string `json:"color"`
+		Size  int    `json:"size"`
+	}
+	type Params struct {
+		OwnerID string    `param:"ownerId,in=path"`
+		PetID   int       `param:"petId,in=path"`
+		Tags    *[]string `param:"tags,in=query"`
+		Ids     []int     `param:"ids,in=query,explode=false"`
+		Limit   *int      `param:"limit,in=query"`
+		Filter  *Filter   `param:"filter,in=query,style=deepObject,explode"`
+		TraceID string    `param:"X-Trace-Id,in=header"`
+		Other   string
+	}
+	tags := []string{"good boy", "cute"}
+	params := Params{
+		OwnerID: "jo/ann",
+		PetID:   7,
+		Tags:    &tags,
+		Ids:     []int{1, 2},
+		Filter:  &Filter{Color: "brown", Size: 3},
+		TraceID: "abc",
+	}
+
+	u, err := BuildRequestURL("https://api.example.com/v1", "/owners/{ownerId}/pets/{petId}", &params)
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.example.com/v1/owners/jo%2Fann/pets/7"+
+		"?tags=good+boy&tags=cute&ids=1,2&filter[color]=brown&filter[size]=3", u)
+
+	// The server side binds the URL back.
+	req := httptest.NewRequest(http.MethodGet, u, nil)
+	var ids []int
+	require.NoError(t, BindQueryParameterFromRequest(req, "form", false, true, "ids", &ids))
+	assert.Equal(t, params.Ids, ids)
+	var boundTags []string
+	require.NoError(t, BindQueryParameterFromRequest(req, "form", true, false, "tags", &boundTags))
+	assert.Equal(t, tags, boundTags)
+	var filter Filter
+	require.NoError(t, BindQueryParameterFromRequest(req, "deepObject", true, false, "filter", &filter))
+	assert.Equal(t, *params.Filter, filter)
+}
+
+func TestBuildRequestURL_Errors(t *testing.T) {
+	type Params struct {
+		PetID *int `param:"petId,in=path"`
+	}
+	_, err := BuildRequestURL("https://api.example.com", "/pets/{petId}", Params{})
+	assert.Error(t, err, "missing required path parameter")
+
+	_, err = BuildRequestURL("https://api.example.com", "/pets/{petId}", nil)
+	assert.Error(t, err, "unfilled template")
+
+	type BadParams struct {
+		PetID int `param:"petId,in=form"`
+	}
+	_, err = BuildRequestURL("https://api.example.com", "/pets", BadParams{})
+	assert.Error(t, err, "unknown location")
+
+	u, err := BuildRequestURL("https://api.example.com", "/pets", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.example.com/pets", u)
+}
@@ -0,0 +1,130 @@
+package runtime
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ByteRange is a range of the bytes of a representation, as requested in a
+// Range header and resolved against the representation's size.
+type ByteRange struct {
+	// Start is the offset of the range's first byte.
+	Start int64
+	// Length is the number of bytes in the range.
+	Length int64
+}
+
+// ContentRange returns the value of the Content-Range header describing r
+// within a representation of size bytes, as sent with a 206 Partial Content
+// response.
+func (r ByteRange) ContentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.Start, r.Start+r.Length-1, size)
+}
+
+// InvalidRangeError is returned by ParseRangeHeader for a malformed Range
+// header, which RFC 9110 lets servers ignore, serving the whole
+// representation.
+type InvalidRangeError struct {
+	Header string
+}
+
+func (e *InvalidRangeError) Error() string {
+	return fmt.Sprintf("invalid range '%s'", e.Header)
+}
+
+// RangeNotSatisfiableError is returned by ParseRangeHeader when none of the
+// requested ranges overlaps the representation, which servers answer with
+// 416 Range Not Satisfiable.
+type RangeNotSatisfiableError struct {
+	Size int64
+}
+
+func (e *RangeNotSatisfiableError) Error() string {
+	return fmt.Sprintf("no requested range overlaps the %d bytes of the representation", e.Size)
+}
+
+// ContentRange returns the value of the Content-Range header sent with a
+// 416 Range Not Satisfiable response.
+func (e *RangeNotSatisfiableError) ContentRange() string {
+	return "bytes */" + strconv.FormatInt(e.Size, 10)
+}
+
+// ParseRangeHeader parses the value of a Range header, as sent to resume a
+// download or to fetch part of a large file, into the byte ranges it asks
+// for of a representation of size bytes, in the order they are requested.
+// Ranges reaching past the end are truncated to it, and ranges starting
+// past it are left out.
+//
+// An empty header, or one in a unit other than bytes, asks for the whole
+// representation, for which ParseRangeHeader returns no ranges. A malformed
+// header fails with an *InvalidRangeError, and one of which no range is
+// satisfiable with a *RangeNotSatisfiableError.
+func ParseRangeHeader(header string, size int64) ([]ByteRange, error) {
+	unit, spec, found := strings.Cut(strings.TrimSpace(header), "=")
+	if header == "" || !strings.EqualFold(strings.TrimSpace(unit), "bytes") {
+		return nil, nil
+	}
+	if !found {
+		return nil, &InvalidRangeError{Header: header}
+	}
+	var ranges []ByteRange
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			// Empty list elements are allowed.
+			continue
+		}
+		first, last, found := strings.Cut(item, "-")
+		if !found {
+			return nil, &InvalidRangeError{Header: header}
+		}
+		var r ByteRange
+		if first == "" {
+			// A suffix range, of the last bytes.
+			n, err := parseRangeOffset(last)
+			if err != nil {
+				return nil, &InvalidRangeError{Header: header}
+			}
+			if n == 0 || size == 0 {
+				continue
+			}
+			if n > size {
+				n = size
+			}
+			r = ByteRange{Start: size - n, Length: n}
+		} else {
+			start, err := parseRangeOffset(first)
+			if err != nil {
+				return nil, &InvalidRangeError{Header: header}
+			}
+			end := size - 1
+			if last != "" {
+				if end, err = parseRangeOffset(last); err != nil || end < start {
+					return nil, &InvalidRangeError{Header: header}
+				}
+				if end >= size {
+					end = size - 1
+				}
+			}
+			if start >= size {
+				continue
+			}
+			r = ByteRange{Start: start, Length: end - start + 1}
+		}
+		ranges = append(ranges, r)
+	}
+	if len(ranges) == 0 {
+		return nil, &RangeNotSatisfiableError{Size: size}
+	}
+	return ranges, nil
+}
+
+// parseRangeOffset parses a byte offset of a Range header, which consists
+// of digits only.
+func parseRangeOffset(s string) (int64, error) {
+	if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid offset '%s'", s)
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
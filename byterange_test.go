@@ -0,0 +1,67 @@
+package runtime
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRangeHeader(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []ByteRange
+	}{
+		{"", nil},
+		{"items=0-10", nil},
+		{"bytes=0-499", []ByteRange{{Start: 0, Length: 500}}},
+		{"bytes=500-", []ByteRange{{Start: 500, Length: 500}}},
+		{"bytes=-100", []ByteRange{{Start: 900, Length: 100}}},
+		{"bytes=-5000", []ByteRange{{Start: 0, Length: 1000}}},
+		{"bytes=900-1999", []ByteRange{{Start: 900, Length: 100}}},
+		{"Bytes= 0-0 , , -1", []ByteRange{{Start: 0, Length: 1}, {Start: 999, Length: 1}}},
+		// Unsatisfiable ranges are left out of a satisfiable set.
+		{"bytes=2000-,0-9", []ByteRange{{Start: 0, Length: 10}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			ranges, err := ParseRangeHeader(tt.header, 1000)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ranges)
+		})
+	}
+}
+
+func TestParseRangeHeader_Invalid(t *testing.T) {
+	for _, header := range []string{"bytes", "bytes=10", "bytes=9-1", "bytes=a-b", "bytes=-", "bytes=+1-2", "bytes=1--2"} {
+		t.Run(header, func(t *testing.T) {
+			_, err := ParseRangeHeader(header, 1000)
+			var invalid *InvalidRangeError
+			assert.ErrorAs(t, err, &invalid)
+		})
+	}
+}
+
+func TestParseRangeHeader_NotSatisfiable(t *testing.T) {
+	for _, tt := range []struct {
+		header string
+		size   int64
+	}{
+		{"bytes=1000-", 1000},
+		{"bytes=-0", 1000},
+		{"bytes=0-", 0},
+		{"bytes=-10", 0},
+	} {
+		t.Run(tt.header, func(t *testing.T) {
+			_, err := ParseRangeHeader(tt.header, tt.size)
+			var notSatisfiable *RangeNotSatisfiableError
+			require.ErrorAs(t, err, &notSatisfiable)
+			assert.Equal(t, "bytes */"+strconv.FormatInt(tt.size, 10), notSatisfiable.ContentRange())
+		})
+	}
+}
+
+func TestByteRange_ContentRange(t *testing.T) {
+	assert.Equal(t, "bytes 500-999/1000", ByteRange{Start: 500, Length: 500}.ContentRange(1000))
+}
@@ -0,0 +1,249 @@
+package runtime
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CacheTransport is an http.RoundTripper which revalidates GET requests with
+// the validators of earlier responses. It remembers the ETag and
+// Last-Modified headers of successful responses per operation and URL, sends
+// them as If-None-Match and If-Modified-Since on the next request for the
+// same resource, and when the server answers 304 Not Modified, serves the
+// remembered body as a 200 instead.
+//
+// Requests which already carry conditional headers are passed through
+// untouched, so callers can still make their own conditional requests, as are
+// requests carrying credentials in an Authorization or Cookie header, whose
+// responses are specific to the caller. Responses marked no-store or private
+// in their Cache-Control header aren't remembered, and a remembered response
+// is only revalidated for requests which match it in the headers its Vary
+// header names. Bodies longer than MaxBodyBytes, or of unknown length, are
+// passed through without being remembered.
+type CacheTransport struct {
+	// Base is the transport used to send requests. It defaults to
+	// http.DefaultTransport.
+	Base http.RoundTripper
+	// Cacheable decides, by operation ID from the request context, whether
+	// responses of an operation are cached. It defaults to caching every
+	// operation.
+	Cacheable func(operationID string) bool
+	// MaxEntries bounds the number of remembered responses. Once it is
+	// reached, the least recently used response is forgotten. It defaults to
+	// 1000.
+	MaxEntries int
+	// MaxBodyBytes bounds the size of the bodies of remembered responses. It
+	// defaults to 1MiB.
+	MaxBodyBytes int64
+
+	mu      sync.Mutex
+	entries map[cacheKey]*list.Element
+	// lru orders the entries from the most to the least recently used.
+	lru *list.List
+}
+
+const (
+	defaultCacheMaxEntries   = 1000
+	defaultCacheMaxBodyBytes = 1 << 20
+)
+
+type cacheKey struct {
+	operationID string
+	url         string
+}
+
+// canonicalURL returns u with its query in canonical form, as keyQuery
+// encodes it, so that requests differing only in the order of their
+// parameters, but not of the values of a repeated one, share cache entries.
+func canonicalURL(u *url.URL) string {
+	c := *u
+	c.RawQuery = keyQuery(u.Query())
+	c.ForceQuery = false
+	return c.String()
+}
+
+type cachedResponse struct {
+	key          cacheKey
+	vary         http.Header
+	etag         string
+	lastModified string
+	header       http.Header
+	body         []byte
+}
+
+func (t *CacheTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *CacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	operationID := GetOperationID(req.Context())
+	if req.Method != http.MethodGet ||
+		req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" ||
+		req.Header.Get("Authorization") != "" || req.Header.Get("Cookie") != "" ||
+		hasCacheDirective(req.Header, "no-store") ||
+		(t.Cacheable != nil && !t.Cacheable(operationID)) {
+		return t.base().RoundTrip(req)
+	}
+	key := cacheKey{operationID: operationID, url: canonicalURL(req.URL)}
+
+	cached := t.load(key)
+	if cached != nil && !cached.matches(req) {
+		cached = nil
+	}
+
+	if cached != nil {
+		req = req.Clone(req.Context())
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		_ = resp.Body.Close()
+		header := cached.header.Clone()
+		// A 304 carries the headers which would have been sent with a 200,
+		// so its values are more up to date than ours.
+		for k, v := range resp.Header {
+			header[k] = v
+		}
+		header.Set("Content-Length", strconv.Itoa(len(cached.body)))
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         resp.Proto,
+			ProtoMajor:    resp.ProtoMajor,
+			ProtoMinor:    resp.ProtoMinor,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(cached.body)),
+			ContentLength: int64(len(cached.body)),
+			Request:       req,
+		}, nil
+	case resp.StatusCode == http.StatusOK &&
+		(resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != "") &&
+		!hasCacheDirective(resp.Header, "no-store") && !hasCacheDirective(resp.Header, "private"):
+		maxBodyBytes := t.MaxBodyBytes
+		if maxBodyBytes <= 0 {
+			maxBodyBytes = defaultCacheMaxBodyBytes
+		}
+		if resp.ContentLength < 0 || resp.ContentLength > maxBodyBytes {
+			break
+		}
+		vary, ok := varyHeader(resp.Header, req.Header)
+		if !ok {
+			break
+		}
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		t.store(&cachedResponse{
+			key:          key,
+			vary:         vary,
+			etag:         resp.Header.Get("ETag"),
+			lastModified: resp.Header.Get("Last-Modified"),
+			header:       resp.Header.Clone(),
+			body:         body,
+		})
+	}
+	return resp, nil
+}
+
+func (t *CacheTransport) load(key cacheKey) *cachedResponse {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e, ok := t.entries[key]
+	if !ok {
+		return nil
+	}
+	t.lru.MoveToFront(e)
+	return e.Value.(*cachedResponse)
+}
+
+func (t *CacheTransport) store(entry *cachedResponse) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.entries == nil {
+		t.entries = make(map[cacheKey]*list.Element)
+		t.lru = list.New()
+	}
+	if e, ok := t.entries[entry.key]; ok {
+		e.Value = entry
+		t.lru.MoveToFront(e)
+		return
+	}
+	t.entries[entry.key] = t.lru.PushFront(entry)
+	maxEntries := t.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultCacheMaxEntries
+	}
+	for t.lru.Len() > maxEntries {
+		oldest := t.lru.Back()
+		t.lru.Remove(oldest)
+		delete(t.entries, oldest.Value.(*cachedResponse).key)
+	}
+}
+
+// matches reports whether req has the values of the headers the response
+// varies by which the request it answered had.
+func (c *cachedResponse) matches(req *http.Request) bool {
+	for name, values := range c.vary {
+		if strings.Join(req.Header.Values(name), ",") != strings.Join(values, ",") {
+			return false
+		}
+	}
+	return true
+}
+
+// varyHeader returns the values in reqHeader of the headers which the Vary
+// header of respHeader names. It reports false for "Vary: *", as such a
+// response can't be matched to later requests.
+func varyHeader(respHeader, reqHeader http.Header) (http.Header, bool) {
+	vary := http.Header{}
+	for _, value := range respHeader.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			switch name {
+			case "":
+				continue
+			case "*":
+				return nil, false
+			}
+			vary[http.CanonicalHeaderKey(name)] = reqHeader.Values(name)
+		}
+	}
+	return vary, true
+}
+
+// hasCacheDirective reports whether the Cache-Control header of h holds the
+// given directive, with or without a value.
+func hasCacheDirective(h http.Header, directive string) bool {
+	for _, value := range h.Values("Cache-Control") {
+		for _, d := range strings.Split(value, ",") {
+			name, _, _ := strings.Cut(strings.TrimSpace(d), "=")
+			if strings.EqualFold(name, directive) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+package runtime
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheTransport(t *testing.T) {
+	var hits, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"rex"}`))
+	}))
+	defer srv.Close()
+
+	transport := &CacheTransport{
+		Cacheable: func(operationID string) bool { return operationID != "getSecret" },
+	}
+	client := &http.Client{Transport: transport}
+	get := func(operationID string) (*http.Response, string) {
+		ctx := WithOperationID(context.Background(), operationID)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/pets/1", nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(body)
+	}
+
+	resp, body := get("getPet")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `{"name":"rex"}`, body)
+	assert.Equal(t, 0, notModified)
+
+	resp, body = get("getPet")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `{"name":"rex"}`, body)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.Equal(t, 1, notModified)
+
+	// Operations which are not cacheable never send validators.
+	get("getSecret")
+	get("getSecret")
+	assert.Equal(t, 1, notModified)
+	assert.Equal(t, 4, hits)
+}
+
+func TestCacheTransport_CanonicalQuery(t *testing.T) {
+	var notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &CacheTransport{}}
+	for _, query := range []string{"?b=2&a=1", "?a=1&b=2", "?a=1&b=%32"} {
+		resp, err := client.Get(srv.URL + "/pets" + query)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+	assert.Equal(t, 2, notModified, "reordered and reescaped parameters share an entry")
+}
+
+func TestCacheTransport_NotRemembered(t *testing.T) {
+	var cacheControl, vary string
+	var conditional int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			conditional++
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Cache-Control", cacheControl)
+		w.Header().Set("Vary", vary)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	get := func(client *http.Client, header http.Header) {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/pets/1", nil)
+		require.NoError(t, err)
+		req.Header = header
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+	for _, tt := range []struct {
+		name         string
+		cacheControl string
+		vary         string
+		header       http.Header
+	}{
+		{name: "no-store", cacheControl: "no-store"},
+		{name: "private", cacheControl: "private, max-age=60"},
+		{name: "vary all", vary: "*"},
+		{name: "authorization", header: http.Header{"Authorization": {"Bearer token"}}},
+		{name: "cookie", header: http.Header{"Cookie": {"session=1"}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheControl, vary, conditional = tt.cacheControl, tt.vary, 0
+			client := &http.Client{Transport: &CacheTransport{}}
+			get(client, tt.header)
+			get(client, tt.header)
+			assert.Equal(t, 0, conditional)
+		})
+	}
+
+	t.Run("vary", func(t *testing.T) {
+		cacheControl, vary, conditional = "", "Accept-Language", 0
+		client := &http.Client{Transport: &CacheTransport{}}
+		get(client, http.Header{"Accept-Language": {"en"}})
+		get(client, http.Header{"Accept-Language": {"de"}})
+		assert.Equal(t, 0, conditional, "a response isn't revalidated for other variants")
+		get(client, http.Header{"Accept-Language": {"de"}})
+		assert.Equal(t, 1, conditional)
+	})
+}
+
+func TestCacheTransport_MaxEntries(t *testing.T) {
+	var conditional int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			conditional++
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &CacheTransport{MaxEntries: 2}}
+	for _, path := range []string{"/pets/1", "/pets/2", "/pets/1", "/pets/3", "/pets/2", "/pets/1"} {
+		resp, err := client.Get(srv.URL + path)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+	// /pets/2 is forgotten for /pets/3, and /pets/1 for /pets/2 again.
+	assert.Equal(t, 1, conditional)
+}
+
+func TestCacheTransport_MaxBodyBytes(t *testing.T) {
+	var conditional int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			conditional++
+		}
+		w.Header().Set("ETag", `"v1"`)
+		if r.URL.Path == "/streamed" {
+			// Flushing before writing leaves the length unknown.
+			w.(http.Flusher).Flush()
+		}
+		_, _ = w.Write([]byte(`{"name":"rex"}`))
+	}))
+	defer srv.Close()
+
+	get := func(client *http.Client, path string) string {
+		resp, err := client.Get(srv.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+	for _, tt := range []struct {
+		name         string
+		path         string
+		maxBodyBytes int64
+		conditional  int
+	}{
+		{name: "within bound", path: "/pets/1", maxBodyBytes: 14, conditional: 1},
+		{name: "too long", path: "/pets/1", maxBodyBytes: 13},
+		{name: "unknown length", path: "/streamed"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			conditional = 0
+			client := &http.Client{Transport: &CacheTransport{MaxBodyBytes: tt.maxBodyBytes}}
+			assert.Equal(t, `{"name":"rex"}`, get(client, tt.path))
+			assert.Equal(t, `{"name":"rex"}`, get(client, tt.path))
+			assert.Equal(t, tt.conditional, conditional)
+		})
+	}
+}
+
+func TestCacheTransport_RepeatedParameterOrder(t *testing.T) {
+	var notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"`+r.URL.RawQuery+`"`)
+		if r.Header.Get("If-None-Match") != "" {
+			notModified++
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &CacheTransport{}}
+	for _, query := range []string{"?sort=name&sort=age", "?sort=age&sort=name"} {
+		resp, err := client.Get(srv.URL + "/pets" + query)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+	assert.Equal(t, 0, notModified, "the order of a parameter's values matters")
+}
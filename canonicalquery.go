@@ -0,0 +1,72 @@
+package runtime
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// CanonicalQuery encodes values as a canonical query string, so that
+// request signers and cache keys built on it agree byte for byte on the
+// same parameters however they were ordered or escaped:
+//
+//   - Names and values are percent-encoded as RFC 3986 requires, leaving
+//     only the unreserved characters A-Z, a-z, 0-9, '-', '.', '_' and '~'
+//     as they are, and encoding everything else, spaces as %20 and '+' as
+//     %2B included, with uppercase hex digits.
+//   - Parameters are sorted by encoded name, then by encoded value, both
+//     compared byte-wise, and joined with '&'.
+//   - A parameter with an empty value is encoded as "name=".
+//   - No values give an empty string.
+//
+// The encoding is part of the API: it won't change between releases, since
+// signatures and cache keys computed by different versions must match.
+func CanonicalQuery(values url.Values) string {
+	var pairs [][2]string
+	for name, vs := range values {
+		for _, v := range vs {
+			pairs = append(pairs, [2]string{canonicalEscape(name), canonicalEscape(v)})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+	encoded := make([]string, len(pairs))
+	for i, pair := range pairs {
+		encoded[i] = pair[0] + "=" + pair[1]
+	}
+	return strings.Join(encoded, "&")
+}
+
+func canonicalEscape(s string) string {
+	// QueryEscape escapes everything but the unreserved characters, except
+	// that it turns spaces into '+'. A literal '+' is escaped as %2B.
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// keyQuery encodes values as CanonicalQuery does, but sorts parameters by
+// name only, keeping the values of each name in the order they were given.
+// It suits cache and recording keys, as the order of a repeated parameter's
+// values, such as sort=name&sort=age, may matter to the server, while
+// signatures must sort them since signers disagree on it.
+func keyQuery(values url.Values) string {
+	names := make([]string, 0, len(values))
+	encodedNames := make(map[string]string, len(values))
+	for name := range values {
+		encodedNames[name] = canonicalEscape(name)
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return encodedNames[names[i]] < encodedNames[names[j]]
+	})
+	var encoded []string
+	for _, name := range names {
+		for _, v := range values[name] {
+			encoded = append(encoded, encodedNames[name]+"="+canonicalEscape(v))
+		}
+	}
+	return strings.Join(encoded, "&")
+}
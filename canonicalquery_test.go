@@ -0,0 +1,30 @@
+package runtime
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalQuery(t *testing.T) {
+	values := url.Values{"b": {"2", "1"}, "a": {"x y+z~"}, "é": {"/"}, "e": {""}}
+	assert.Equal(t, "%C3%A9=%2F&a=x%20y%2Bz~&b=1&b=2&e=", CanonicalQuery(values))
+	assert.Equal(t, "", CanonicalQuery(nil))
+	// Names sort before longer names they prefix.
+	assert.Equal(t, "a=2&a-b=1", CanonicalQuery(url.Values{"a-b": {"1"}, "a": {"2"}}))
+
+	// The same parameters, however ordered and escaped, give the same
+	// string.
+	q1, _ := url.ParseQuery("tag=a%20b&id=2&id=10&q=%7e")
+	q2, _ := url.ParseQuery("id=10&q=~&tag=a+b&id=2")
+	assert.Equal(t, CanonicalQuery(q1), CanonicalQuery(q2))
+	assert.Equal(t, "id=10&id=2&q=~&tag=a%20b", CanonicalQuery(q1))
+}
+
+func TestKeyQuery(t *testing.T) {
+	values := url.Values{"b": {"2", "1"}, "a": {"x y+z~"}, "é": {"/"}, "e": {""}}
+	assert.Equal(t, "%C3%A9=%2F&a=x%20y%2Bz~&b=2&b=1&e=", keyQuery(values))
+	assert.Equal(t, "", keyQuery(nil))
+	assert.Equal(t, "a=2&a-b=1", keyQuery(url.Values{"a-b": {"1"}, "a": {"2"}}))
+}
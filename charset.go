@@ -0,0 +1,120 @@
+package runtime
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// ErrInvalidUTF8 is returned when a body which must be UTF-8 isn't.
+var ErrInvalidUTF8 = errors.New("body is not valid UTF-8")
+
+// UnsupportedCharsetError is returned for a Content-Type charset which is
+// unknown, or which is rejected by CharsetOptions.RejectNonUTF8.
+type UnsupportedCharsetError struct {
+	Charset string
+}
+
+func (e *UnsupportedCharsetError) Error() string {
+	return fmt.Sprintf("unsupported charset '%s'", e.Charset)
+}
+
+// CharsetOptions configures how bodies in other character sets than UTF-8
+// are handled.
+type CharsetOptions struct {
+	// RejectNonUTF8 fails bodies declaring a charset other than UTF-8 or
+	// US-ASCII with an *UnsupportedCharsetError, and UTF-8 bodies containing
+	// invalid sequences with ErrInvalidUTF8, instead of transcoding them.
+	RejectNonUTF8 bool
+}
+
+// NewCharsetReader returns a reader which transcodes body to UTF-8 according
+// to the charset parameter of contentType, such as
+// "text/plain; charset=iso-8859-1", so that it can be unmarshaled without
+// producing mojibake. Bodies without a charset are assumed to be UTF-8,
+// which is the default for JSON and the common case for text.
+func NewCharsetReader(body io.Reader, contentType string, opts CharsetOptions) (io.Reader, error) {
+	enc, err := charsetEncoding(contentType, opts)
+	if err != nil {
+		return nil, err
+	}
+	if enc == nil {
+		return body, nil
+	}
+	return enc.NewDecoder().Reader(body), nil
+}
+
+// DecodeCharset is the in-memory counterpart of NewCharsetReader, returning
+// body transcoded to UTF-8.
+func DecodeCharset(body []byte, contentType string, opts CharsetOptions) ([]byte, error) {
+	enc, err := charsetEncoding(contentType, opts)
+	if err != nil {
+		return nil, err
+	}
+	if enc == nil {
+		return body, nil
+	}
+	decoded, err := io.ReadAll(enc.NewDecoder().Reader(bytes.NewReader(body)))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding body: %w", err)
+	}
+	return decoded, nil
+}
+
+// charsetEncoding returns the encoding to decode contentType's charset with,
+// or nil if the body is already UTF-8.
+func charsetEncoding(contentType string, opts CharsetOptions) (encoding.Encoding, error) {
+	if contentType == "" {
+		return nil, nil
+	}
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing content type '%s': %w", contentType, err)
+	}
+	charset := strings.ToLower(strings.TrimSpace(params["charset"]))
+	switch charset {
+	case "", "utf-8", "utf8", "us-ascii", "ascii":
+		if opts.RejectNonUTF8 {
+			return utf8Validator{}, nil
+		}
+		return nil, nil
+	}
+	if opts.RejectNonUTF8 {
+		return nil, &UnsupportedCharsetError{Charset: charset}
+	}
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, &UnsupportedCharsetError{Charset: charset}
+	}
+	return enc, nil
+}
+
+// utf8Validator is an encoding.Encoding whose decoder passes valid UTF-8
+// through unchanged and fails on anything else.
+type utf8Validator struct{}
+
+func (utf8Validator) NewDecoder() *encoding.Decoder {
+	return &encoding.Decoder{Transformer: utf8ValidatingTransformer{}}
+}
+
+func (utf8Validator) NewEncoder() *encoding.Encoder {
+	return &encoding.Encoder{Transformer: encoding.UTF8Validator}
+}
+
+type utf8ValidatingTransformer struct{}
+
+func (utf8ValidatingTransformer) Transform(dst, src []byte, atEOF bool) (int, int, error) {
+	nDst, nSrc, err := encoding.UTF8Validator.Transform(dst, src, atEOF)
+	if err == encoding.ErrInvalidUTF8 {
+		err = ErrInvalidUTF8
+	}
+	return nDst, nSrc, err
+}
+
+func (utf8ValidatingTransformer) Reset() {}
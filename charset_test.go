@@ -0,0 +1,64 @@
+package runtime
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCharset(t *testing.T) {
+	latin1 := []byte("caf\xe9")
+
+	decoded, err := DecodeCharset(latin1, "text/plain; charset=ISO-8859-1", CharsetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "café", string(decoded))
+
+	decoded, err = DecodeCharset([]byte("café"), "application/json", CharsetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "café", string(decoded))
+
+	_, err = DecodeCharset(latin1, "text/plain; charset=klingon", CharsetOptions{})
+	var unsupported *UnsupportedCharsetError
+	require.True(t, errors.As(err, &unsupported))
+	assert.Equal(t, "klingon", unsupported.Charset)
+
+	t.Run("reject non-UTF-8", func(t *testing.T) {
+		opts := CharsetOptions{RejectNonUTF8: true}
+		_, err := DecodeCharset(latin1, "text/plain; charset=iso-8859-1", opts)
+		assert.True(t, errors.As(err, &unsupported))
+
+		_, err = DecodeCharset(latin1, "text/plain; charset=utf-8", opts)
+		assert.ErrorIs(t, err, ErrInvalidUTF8)
+
+		decoded, err := DecodeCharset([]byte("café"), "text/plain", opts)
+		require.NoError(t, err)
+		assert.Equal(t, "café", string(decoded))
+	})
+}
+
+func TestNewCharsetReader(t *testing.T) {
+	r, err := NewCharsetReader(strings.NewReader("{\"name\":\"caf\xe9\"}"), "application/json; charset=windows-1252", CharsetOptions{})
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"café"}`, string(data))
+
+	r, err = NewCharsetReader(strings.NewReader("caf\xe9"), "text/plain", CharsetOptions{RejectNonUTF8: true})
+	require.NoError(t, err)
+	_, err = io.ReadAll(r)
+	assert.ErrorIs(t, err, ErrInvalidUTF8)
+}
+
+func TestBindResponse_Charset(t *testing.T) {
+	var dest struct {
+		Name string `json:"name"`
+	}
+	resp, _ := newTestResponse(http.StatusOK, "application/json; charset=iso-8859-1", "{\"name\":\"caf\xe9\"}")
+	require.NoError(t, BindResponse(resp, &dest))
+	assert.Equal(t, "café", dest.Name)
+}
@@ -0,0 +1,248 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCircuitFailureThreshold = 5
+	defaultCircuitOpenTimeout      = 30 * time.Second
+)
+
+// ErrCircuitOpen is wrapped by the error CircuitBreakerTransport fails
+// requests with while the circuit of their operation is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of the circuit of an operation.
+type CircuitState int
+
+const (
+	// CircuitClosed lets requests through, counting failures.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails requests without sending them.
+	CircuitOpen
+	// CircuitHalfOpen lets a single probe request through, whose outcome
+	// closes or reopens the circuit.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// CircuitBreakerPolicy configures the circuit of an operation.
+type CircuitBreakerPolicy struct {
+	// FailureThreshold is the number of consecutive failures which opens
+	// the circuit. It defaults to 5.
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before a probe is let
+	// through. It defaults to 30s.
+	OpenTimeout time.Duration
+	// IsFailure decides whether an attempt which produced resp or err
+	// counts as a failure. It defaults to DefaultIsCircuitFailure. Attempts
+	// cut short by the request's own context being cancelled are neither
+	// failures nor successes, and don't reach it.
+	IsFailure func(resp *http.Response, err error) bool
+}
+
+// DefaultIsCircuitFailure counts transport errors, other than the request's
+// own context being cancelled, and 5xx responses as failures.
+func DefaultIsCircuitFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return resp.StatusCode >= 500
+}
+
+// CircuitBreakerTransport is an http.RoundTripper which stops sending
+// requests to an operation which keeps failing, so that a struggling
+// upstream gets time to recover and callers fail fast instead of waiting on
+// it. Each operation, identified by the operation ID in the request context
+// as recorded by generated clients through WithOperationID or
+// WithOperationInfo, has its own circuit. Once FailureThreshold consecutive
+// requests fail, the circuit opens and requests fail with an error wrapping
+// ErrCircuitOpen. After OpenTimeout, one probe request is let through: the
+// circuit closes if it succeeds and opens again if it fails.
+type CircuitBreakerTransport struct {
+	// Base is the transport used to send requests. It defaults to
+	// http.DefaultTransport.
+	Base http.RoundTripper
+	// Policy applies to operations without an entry in OperationPolicies.
+	Policy CircuitBreakerPolicy
+	// OperationPolicies holds per-operation overrides, keyed by operation
+	// ID.
+	OperationPolicies map[string]CircuitBreakerPolicy
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+	now      func() time.Time
+}
+
+// circuitOutcome is what the outcome of a request does to its circuit.
+type circuitOutcome int
+
+const (
+	circuitSuccess circuitOutcome = iota
+	circuitFailure
+	// circuitIgnored leaves the failure count alone, but frees the probe
+	// slot of a half-open circuit for another request.
+	circuitIgnored
+)
+
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+	// generation counts the changes of state, so that the outcomes of
+	// requests sent before the last one are ignored: a slow request sent
+	// while the circuit was closed mustn't close it again while it is
+	// half-open, nor its failure reopen it once the probe closed it.
+	generation uint64
+}
+
+func (t *CircuitBreakerTransport) policy(operationID string) CircuitBreakerPolicy {
+	p, ok := t.OperationPolicies[operationID]
+	if !ok {
+		p = t.Policy
+	}
+	if p.FailureThreshold <= 0 {
+		p.FailureThreshold = defaultCircuitFailureThreshold
+	}
+	if p.OpenTimeout <= 0 {
+		p.OpenTimeout = defaultCircuitOpenTimeout
+	}
+	if p.IsFailure == nil {
+		p.IsFailure = DefaultIsCircuitFailure
+	}
+	return p
+}
+
+func (t *CircuitBreakerTransport) clock() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
+
+func (t *CircuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	operationID := GetOperationID(req.Context())
+	policy := t.policy(operationID)
+	generation, err := t.allow(operationID, policy)
+	if err != nil {
+		return nil, err
+	}
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	outcome := circuitSuccess
+	switch {
+	case errors.Is(err, context.Canceled) && req.Context().Err() != nil:
+		// The caller gave up, which says nothing about the upstream.
+		outcome = circuitIgnored
+	case policy.IsFailure(resp, err):
+		outcome = circuitFailure
+	}
+	t.record(operationID, policy, generation, outcome)
+	return resp, err
+}
+
+// State returns the state of the circuit of an operation.
+func (t *CircuitBreakerTransport) State(operationID string) CircuitState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c := t.circuits[operationID]
+	if c == nil {
+		return CircuitClosed
+	}
+	if c.state == CircuitOpen && !t.clock().Before(c.openedAt.Add(t.policy(operationID).OpenTimeout)) {
+		return CircuitHalfOpen
+	}
+	return c.state
+}
+
+// allow reports whether a request of the operation may be sent, moving an
+// open circuit whose timeout expired to half-open. It returns the
+// generation of the circuit the request is sent in, to pass to record.
+func (t *CircuitBreakerTransport) allow(operationID string, policy CircuitBreakerPolicy) (uint64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c := t.circuits[operationID]
+	if c == nil {
+		return 0, nil
+	}
+	switch c.state {
+	case CircuitOpen:
+		if t.clock().Before(c.openedAt.Add(policy.OpenTimeout)) {
+			return 0, fmt.Errorf("operation '%s': %w", operationID, ErrCircuitOpen)
+		}
+		c.state = CircuitHalfOpen
+		c.generation++
+		c.probing = true
+	case CircuitHalfOpen:
+		// Only the probe is let through.
+		if c.probing {
+			return 0, fmt.Errorf("operation '%s': %w", operationID, ErrCircuitOpen)
+		}
+		c.probing = true
+	}
+	return c.generation, nil
+}
+
+// record updates the circuit of the operation with the outcome of a request
+// sent in the given generation of it, unless the circuit changed state since.
+func (t *CircuitBreakerTransport) record(operationID string, policy CircuitBreakerPolicy, generation uint64, outcome circuitOutcome) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c := t.circuits[operationID]
+	if c == nil {
+		if outcome != circuitFailure {
+			return
+		}
+		if t.circuits == nil {
+			t.circuits = make(map[string]*circuit)
+		}
+		// Circuits are kept once created, as their generation must keep
+		// growing for stale outcomes to be told apart.
+		c = &circuit{}
+		t.circuits[operationID] = c
+	}
+	if c.generation != generation {
+		return
+	}
+	switch outcome {
+	case circuitIgnored:
+		c.probing = false
+		return
+	case circuitSuccess:
+		if c.state != CircuitClosed {
+			c.state = CircuitClosed
+			c.generation++
+			c.probing = false
+		}
+		c.failures = 0
+		return
+	}
+	c.failures++
+	if c.state == CircuitHalfOpen || c.failures >= policy.FailureThreshold {
+		c.state = CircuitOpen
+		c.openedAt = t.clock()
+		c.probing = false
+		c.generation++
+	}
+}
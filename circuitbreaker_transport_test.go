@@ -0,0 +1,153 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreakerTransport(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	transport := &CircuitBreakerTransport{
+		Policy: CircuitBreakerPolicy{FailureThreshold: 2, OpenTimeout: time.Minute},
+		OperationPolicies: map[string]CircuitBreakerPolicy{
+			"health": {FailureThreshold: 100},
+		},
+		now: func() time.Time { return now },
+	}
+	client := &http.Client{Transport: transport}
+	get := func(operationID string) error {
+		req, err := http.NewRequestWithContext(WithOperationID(context.Background(), operationID), http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	require.NoError(t, get("listPets"))
+	assert.Equal(t, CircuitClosed, transport.State("listPets"))
+	require.NoError(t, get("listPets"))
+	assert.Equal(t, CircuitOpen, transport.State("listPets"))
+
+	err := get("listPets")
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.EqualValues(t, 2, atomic.LoadInt32(&attempts), "an open circuit doesn't send requests")
+
+	// Other operations have circuits of their own.
+	require.NoError(t, get("health"))
+	require.NoError(t, get("health"))
+	assert.Equal(t, CircuitClosed, transport.State("health"))
+
+	// A failed probe opens the circuit again.
+	now = now.Add(time.Minute)
+	assert.Equal(t, CircuitHalfOpen, transport.State("listPets"))
+	require.NoError(t, get("listPets"))
+	assert.Equal(t, CircuitOpen, transport.State("listPets"))
+	assert.True(t, errors.Is(get("listPets"), ErrCircuitOpen))
+
+	// A successful probe closes it.
+	now = now.Add(time.Minute)
+	failing.Store(false)
+	require.NoError(t, get("listPets"))
+	assert.Equal(t, CircuitClosed, transport.State("listPets"))
+	require.NoError(t, get("listPets"))
+}
+
+func TestCircuitBreakerTransport_SingleProbe(t *testing.T) {
+	transport := &CircuitBreakerTransport{Policy: CircuitBreakerPolicy{FailureThreshold: 1, OpenTimeout: time.Nanosecond}}
+	policy := transport.policy("op")
+	transport.record("op", policy, 0, circuitFailure)
+	time.Sleep(time.Millisecond)
+
+	generation, err := transport.allow("op", policy)
+	require.NoError(t, err)
+	_, err = transport.allow("op", policy)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	transport.record("op", policy, generation, circuitSuccess)
+	_, err = transport.allow("op", policy)
+	assert.NoError(t, err)
+}
+
+func TestCircuitBreakerTransport_Cancelled(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	transport := &CircuitBreakerTransport{
+		Base: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		}),
+		Policy: CircuitBreakerPolicy{FailureThreshold: 1, OpenTimeout: time.Minute},
+		now:    func() time.Time { return now },
+	}
+	policy := transport.policy("op")
+	generation, err := transport.allow("op", policy)
+	require.NoError(t, err)
+	transport.record("op", policy, generation, circuitFailure)
+	now = now.Add(time.Minute)
+
+	// A cancelled probe neither closes nor reopens the circuit, and lets
+	// the next request probe it.
+	ctx, cancel := context.WithCancel(WithOperationID(context.Background(), "op"))
+	cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com", nil)
+	require.NoError(t, err)
+	_, err = transport.RoundTrip(req)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, CircuitHalfOpen, transport.State("op"))
+	_, err = transport.allow("op", policy)
+	assert.NoError(t, err)
+}
+
+func TestCircuitBreakerTransport_StaleOutcomes(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	transport := &CircuitBreakerTransport{
+		Policy: CircuitBreakerPolicy{FailureThreshold: 1, OpenTimeout: time.Minute},
+		now:    func() time.Time { return now },
+	}
+	policy := transport.policy("op")
+
+	// A slow request sent while the circuit was closed succeeds after it
+	// opened and moved to half-open: it doesn't close it.
+	slow, err := transport.allow("op", policy)
+	require.NoError(t, err)
+	failed, err := transport.allow("op", policy)
+	require.NoError(t, err)
+	transport.record("op", policy, failed, circuitFailure)
+	now = now.Add(time.Minute)
+	probe, err := transport.allow("op", policy)
+	require.NoError(t, err)
+	transport.record("op", policy, slow, circuitSuccess)
+	assert.Equal(t, CircuitHalfOpen, transport.State("op"))
+
+	// Nor does its failure reopen it once the probe closed it.
+	transport.record("op", policy, probe, circuitSuccess)
+	assert.Equal(t, CircuitClosed, transport.State("op"))
+	transport.record("op", policy, slow, circuitFailure)
+	assert.Equal(t, CircuitClosed, transport.State("op"))
+}
+
+func TestDefaultIsCircuitFailure(t *testing.T) {
+	assert.True(t, DefaultIsCircuitFailure(nil, errors.New("connection refused")))
+	assert.False(t, DefaultIsCircuitFailure(nil, context.Canceled))
+	assert.True(t, DefaultIsCircuitFailure(&http.Response{StatusCode: 503}, nil))
+	assert.False(t, DefaultIsCircuitFailure(&http.Response{StatusCode: 404}, nil))
+}
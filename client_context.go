@@ -0,0 +1,54 @@
+package runtime
+
+import "context"
+
+// OperationInfo describes the OpenAPI operation which a client request is
+// being made for. Generated clients record it in the request context so that
+// http.RoundTrippers and request editors can make decisions, such as retry
+// policies or metric labels, without parsing URLs.
+type OperationInfo struct {
+	// ID is the operationId from the spec.
+	ID string
+	// Method is the HTTP method, such as "GET".
+	Method string
+	// PathTemplate is the path as declared in the spec, such as
+	// "/pets/{petId}", before any parameters are substituted.
+	PathTemplate string
+	// Tags are the operation's tags from the spec.
+	Tags []string
+}
+
+type clientContextKey int
+
+const (
+	operationInfoContextKey clientContextKey = iota
+	idempotencyKeyContextKey
+	securityRequirementsContextKey
+)
+
+// WithOperationInfo returns a copy of ctx carrying info.
+func WithOperationInfo(ctx context.Context, info OperationInfo) context.Context {
+	return context.WithValue(ctx, operationInfoContextKey, info)
+}
+
+// GetOperationInfo returns the OperationInfo stored in ctx, and whether there
+// was one.
+func GetOperationInfo(ctx context.Context) (OperationInfo, bool) {
+	info, ok := ctx.Value(operationInfoContextKey).(OperationInfo)
+	return info, ok
+}
+
+// WithOperationID returns a copy of ctx carrying the given operation ID. Any
+// other operation metadata already present in ctx is preserved.
+func WithOperationID(ctx context.Context, operationID string) context.Context {
+	info, _ := GetOperationInfo(ctx)
+	info.ID = operationID
+	return WithOperationInfo(ctx, info)
+}
+
+// GetOperationID returns the operation ID stored in ctx, or an empty string
+// if there is none.
+func GetOperationID(ctx context.Context) string {
+	info, _ := GetOperationInfo(ctx)
+	return info.ID
+}
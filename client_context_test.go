@@ -0,0 +1,31 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationInfoContext(t *testing.T) {
+	ctx := context.Background()
+	_, ok := GetOperationInfo(ctx)
+	assert.False(t, ok)
+	assert.Equal(t, "", GetOperationID(ctx))
+
+	ctx = WithOperationInfo(ctx, OperationInfo{
+		ID:           "getPetById",
+		Method:       "GET",
+		PathTemplate: "/pets/{petId}",
+	})
+	info, ok := GetOperationInfo(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "GET", info.Method)
+	assert.Equal(t, "/pets/{petId}", info.PathTemplate)
+	assert.Equal(t, "getPetById", GetOperationID(ctx))
+
+	// Overriding only the ID keeps the rest of the metadata.
+	ctx = WithOperationID(ctx, "getPet")
+	info, _ = GetOperationInfo(ctx)
+	assert.Equal(t, OperationInfo{ID: "getPet", Method: "GET", PathTemplate: "/pets/{petId}"}, info)
+}
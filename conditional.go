@@ -0,0 +1,139 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// Validators are the ETag and Last-Modified validators of a version of a
+// resource, as sent by the server, used to make conditional requests.
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+// ValidatorsFromResponse captures the validators of resp.
+func ValidatorsFromResponse(resp *http.Response) Validators {
+	return Validators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+}
+
+// IsZero reports whether v holds no validator.
+func (v Validators) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// RequestEditor returns a RequestEditorFn which makes a request conditional
+// on the resource still being the version v was captured from, for
+// optimistic concurrency: If-Match is set to the ETag, or, without one,
+// If-Unmodified-Since to the Last-Modified date. When the resource has
+// changed since, the server answers 412 Precondition Failed, which
+// DecodeErrorResponse turns into a *PreconditionFailedError. Headers the
+// request already carries are left alone.
+func (v Validators) RequestEditor() RequestEditorFn {
+	return func(ctx context.Context, req *http.Request) error {
+		setPreconditions(req.Header, v)
+		return nil
+	}
+}
+
+func setPreconditions(h http.Header, v Validators) {
+	if h.Get("If-Match") != "" || h.Get("If-Unmodified-Since") != "" {
+		return
+	}
+	switch {
+	case v.ETag != "":
+		h.Set("If-Match", v.ETag)
+	case v.LastModified != "":
+		h.Set("If-Unmodified-Since", v.LastModified)
+	}
+}
+
+// PreconditionFailedError is returned by DecodeErrorResponse for 412
+// Precondition Failed responses, which report that a conditional request
+// was made against an outdated version of a resource. The caller should
+// fetch the resource again and retry its change against the new version.
+type PreconditionFailedError struct {
+	*ResponseError
+	// Current holds the validators of the current version of the resource,
+	// when the server sent them along.
+	Current Validators
+	// Decoded is the error a decoder registered for the response returned,
+	// such as a typed error of the API's own, or nil.
+	Decoded error
+}
+
+func (e *PreconditionFailedError) Error() string {
+	if e.Decoded != nil {
+		return "precondition failed: the resource was modified: " + e.Decoded.Error()
+	}
+	return "precondition failed: the resource was modified: " + e.ResponseError.Error()
+}
+
+// Unwrap returns the decoded error, if any, and the ResponseError, so that
+// errors.As finds either.
+func (e *PreconditionFailedError) Unwrap() []error {
+	if e.Decoded != nil {
+		return []error{e.Decoded, e.ResponseError}
+	}
+	return []error{e.ResponseError}
+}
+
+// ConditionalTransport is an http.RoundTripper which plumbs validators from
+// reads to writes. It captures the validators of successful GET responses
+// per URL, and sets them, as Validators.RequestEditor does, on later PUT,
+// PATCH and DELETE requests to the same URL, so that a client only updates
+// the version of a resource it last read.
+type ConditionalTransport struct {
+	// Base is the transport used to send requests. It defaults to
+	// http.DefaultTransport.
+	Base http.RoundTripper
+
+	mu         sync.Mutex
+	validators map[string]Validators
+}
+
+func (t *ConditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	key := req.URL.String()
+
+	switch req.Method {
+	case http.MethodPut, http.MethodPatch, http.MethodDelete:
+		t.mu.Lock()
+		v, ok := t.validators[key]
+		t.mu.Unlock()
+		if ok {
+			req = req.Clone(req.Context())
+			setPreconditions(req.Header, v)
+		}
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil || resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch v := ValidatorsFromResponse(resp); {
+	case req.Method == http.MethodDelete:
+		delete(t.validators, key)
+	case req.Method == http.MethodGet || req.Method == http.MethodPut || req.Method == http.MethodPatch:
+		// A write's response carries the validators of the new version, if
+		// any; without them, the ones held are outdated.
+		if v.IsZero() {
+			delete(t.validators, key)
+			break
+		}
+		if t.validators == nil {
+			t.validators = make(map[string]Validators)
+		}
+		t.validators[key] = v
+	}
+	return resp, nil
+}
@@ -0,0 +1,100 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidators_RequestEditor(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("ETag", `"v1"`)
+	resp.Header.Set("Last-Modified", "Mon, 01 Jan 2024 12:00:00 GMT")
+	v := ValidatorsFromResponse(resp)
+	assert.False(t, v.IsZero())
+
+	req := httptest.NewRequest(http.MethodPut, "/pets/1", nil)
+	require.NoError(t, v.RequestEditor()(context.Background(), req))
+	assert.Equal(t, `"v1"`, req.Header.Get("If-Match"))
+	assert.Empty(t, req.Header.Get("If-Unmodified-Since"))
+
+	req = httptest.NewRequest(http.MethodPut, "/pets/1", nil)
+	require.NoError(t, Validators{LastModified: "Mon, 01 Jan 2024 12:00:00 GMT"}.RequestEditor()(context.Background(), req))
+	assert.Empty(t, req.Header.Get("If-Match"))
+	assert.Equal(t, "Mon, 01 Jan 2024 12:00:00 GMT", req.Header.Get("If-Unmodified-Since"))
+
+	req = httptest.NewRequest(http.MethodPut, "/pets/1", nil)
+	req.Header.Set("If-Match", "*")
+	require.NoError(t, v.RequestEditor()(context.Background(), req))
+	assert.Equal(t, "*", req.Header.Get("If-Match"))
+}
+
+func TestDecodeErrorResponse_PreconditionFailed(t *testing.T) {
+	err := DecodeErrorResponse(errorResponse(http.StatusPreconditionFailed, "text/plain", "stale", http.Header{"Etag": {`"v2"`}}), nil)
+	var precondition *PreconditionFailedError
+	require.ErrorAs(t, err, &precondition)
+	assert.Equal(t, `"v2"`, precondition.Current.ETag)
+	var responseErr *ResponseError
+	require.ErrorAs(t, err, &responseErr)
+	assert.Equal(t, []byte("stale"), responseErr.Body)
+	assert.EqualError(t, err, "precondition failed: the resource was modified: unexpected status 412 Precondition Failed")
+
+	// A registered decoder's error is wrapped rather than returned as is.
+	apiErr := errors.New("version conflict")
+	var registry ErrorDecoderRegistry
+	registry.Register(400, 499, "", func(*http.Response, []byte) error { return apiErr })
+	err = DecodeErrorResponse(errorResponse(http.StatusPreconditionFailed, "text/plain", "stale", http.Header{"Etag": {`"v2"`}}), &registry)
+	require.ErrorAs(t, err, &precondition)
+	assert.Equal(t, `"v2"`, precondition.Current.ETag)
+	assert.ErrorIs(t, err, apiErr)
+	assert.ErrorAs(t, err, &responseErr)
+	assert.EqualError(t, err, "precondition failed: the resource was modified: version conflict")
+}
+
+func TestConditionalTransport(t *testing.T) {
+	version := 1
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"v` + strconv.Itoa(version) + `"`
+		if match := r.Header.Get("If-Match"); r.Method == http.MethodPut && match != etag {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		if r.Method == http.MethodPut {
+			version++
+			etag = `"v` + strconv.Itoa(version) + `"`
+		}
+		w.Header().Set("ETag", etag)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &ConditionalTransport{}}
+	put := func() int {
+		req, err := http.NewRequest(http.MethodPut, srv.URL+"/pets/1", strings.NewReader("{}"))
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// Without a prior read, the write isn't conditional.
+	assert.Equal(t, http.StatusPreconditionFailed, put())
+
+	resp, err := client.Get(srv.URL + "/pets/1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, put())
+	// The write's response carries the new ETag, so writing again succeeds.
+	assert.Equal(t, http.StatusOK, put())
+
+	// Another client changing the resource makes the held ETag outdated.
+	version++
+	assert.Equal(t, http.StatusPreconditionFailed, put())
+}
@@ -0,0 +1,283 @@
+package runtime
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// UnsupportedContentEncodingError is returned for a Content-Encoding which
+// can't be decoded. Servers usually answer it with 415 Unsupported Media
+// Type.
+type UnsupportedContentEncodingError struct {
+	Encoding string
+}
+
+func (e *UnsupportedContentEncodingError) Error() string {
+	return fmt.Sprintf("unsupported content encoding '%s'", e.Encoding)
+}
+
+// DecodeContentEncoding returns a reader which decodes body according to a
+// Content-Encoding header value. The gzip, deflate and br codings are
+// supported, as well as lists of them, which are undone in reverse order.
+//
+// When maxBytes is positive, reading more than maxBytes of decoded data
+// fails with a *BodyTooLargeError, which protects against small compressed
+// bodies expanding to exhaust memory. Closing the returned reader closes
+// body.
+func DecodeContentEncoding(body io.ReadCloser, contentEncoding string, maxBytes int64) (io.ReadCloser, error) {
+	codings := strings.Split(contentEncoding, ",")
+	var r io.Reader = body
+	for i := len(codings) - 1; i >= 0; i-- {
+		coding := strings.ToLower(strings.TrimSpace(codings[i]))
+		switch coding {
+		case "", "identity":
+		case "gzip", "x-gzip":
+			zr, err := gzip.NewReader(r)
+			if err == io.EOF {
+				// An empty body, as some servers send with a 200 and a
+				// Content-Encoding they would have used, decodes to nothing.
+				r = bytes.NewReader(nil)
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("error reading gzip body: %w", err)
+			}
+			r = zr
+		case "deflate":
+			r = newDeflateReader(r)
+		case "br":
+			r = brotli.NewReader(r)
+		default:
+			return nil, &UnsupportedContentEncodingError{Encoding: coding}
+		}
+	}
+	decoded := struct {
+		io.Reader
+		io.Closer
+	}{r, body}
+	if maxBytes <= 0 {
+		return decoded, nil
+	}
+	return &limitedBody{ReadCloser: decoded, remaining: maxBytes, limit: maxBytes}, nil
+}
+
+// EncodeContentEncoding compresses data with the gzip, deflate or br coding.
+func EncodeContentEncoding(data []byte, contentEncoding string) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := newContentEncoder(&buf, contentEncoding)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// newContentEncoder returns a writer compressing to w with the gzip, deflate
+// or br coding.
+func newContentEncoder(w io.Writer, contentEncoding string) (io.WriteCloser, error) {
+	switch strings.ToLower(contentEncoding) {
+	case "gzip":
+		return gzip.NewWriter(w), nil
+	case "deflate":
+		return zlib.NewWriter(w), nil
+	case "br":
+		return brotli.NewWriter(w), nil
+	}
+	return nil, &UnsupportedContentEncodingError{Encoding: contentEncoding}
+}
+
+// compressBody returns a reader of body compressed with the coding, which
+// newContentEncoder must support, compressing it as it is read so that
+// large bodies aren't held in memory. body is closed once read, or when
+// the returned reader is closed.
+func compressBody(body io.ReadCloser, contentEncoding string) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		defer func() { _ = body.Close() }()
+		w, err := newContentEncoder(pw, contentEncoding)
+		if err == nil {
+			_, err = io.Copy(w, body)
+			if closeErr := w.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		_ = pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// newDeflateReader reads a deflate coded body, which is meant to be in the
+// zlib format but is sent as a raw deflate stream by some servers.
+func newDeflateReader(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(2)
+	if err == nil && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		zr, err := zlib.NewReader(br)
+		if err == nil {
+			return zr
+		}
+		return errReader{err}
+	}
+	return flate.NewReader(br)
+}
+
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// CompressionTransport is an http.RoundTripper which asks servers for
+// compressed responses and decodes them, and which can compress request
+// bodies as well.
+//
+// Go's default transport only handles gzip, and only when it sets
+// Accept-Encoding itself; CompressionTransport also handles deflate and br,
+// and limits the size of decoded responses.
+type CompressionTransport struct {
+	// Base is the transport used to send requests. It defaults to
+	// http.DefaultTransport.
+	Base http.RoundTripper
+	// RequestEncoding, if set to gzip, deflate or br, compresses request
+	// bodies of at least MinRequestSize bytes with that coding as they are
+	// sent, chunked. Only use it with servers known to accept compressed
+	// requests.
+	RequestEncoding string
+	MinRequestSize  int64
+	// MaxResponseBytes limits the decoded size of compressed responses, as
+	// DecodeContentEncoding does.
+	MaxResponseBytes int64
+}
+
+const defaultAcceptEncoding = "gzip, deflate, br"
+
+func (t *CompressionTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *CompressionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", defaultAcceptEncoding)
+	}
+	// As for http.Client, a zero ContentLength with a body is unknown.
+	size := req.ContentLength
+	if size == 0 {
+		size = -1
+	}
+	if t.RequestEncoding != "" && req.Body != nil && req.Body != http.NoBody &&
+		req.Header.Get("Content-Encoding") == "" &&
+		(size < 0 || size >= t.MinRequestSize) {
+		if _, err := newContentEncoder(io.Discard, t.RequestEncoding); err != nil {
+			_ = req.Body.Close()
+			return nil, err
+		}
+		body := req.Body
+		if size < 0 && t.MinRequestSize > 0 {
+			// Of a body of unknown length, only as much as tells whether
+			// it is large enough to compress is read ahead.
+			head, err := io.ReadAll(io.LimitReader(body, t.MinRequestSize))
+			if err != nil {
+				_ = body.Close()
+				return nil, fmt.Errorf("error reading request body: %w", err)
+			}
+			body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(head), body), body}
+			if int64(len(head)) < t.MinRequestSize {
+				size = int64(len(head))
+				req.ContentLength = size
+			}
+		}
+		if size < 0 || size >= t.MinRequestSize {
+			coding := strings.ToLower(t.RequestEncoding)
+			req.Header.Set("Content-Encoding", coding)
+			req.Header.Del("Content-Length")
+			req.ContentLength = -1
+			body = compressBody(body, coding)
+			if getBody := req.GetBody; getBody != nil {
+				req.GetBody = func() (io.ReadCloser, error) {
+					body, err := getBody()
+					if err != nil {
+						return nil, err
+					}
+					return compressBody(body, coding), nil
+				}
+			}
+		}
+		req.Body = body
+	}
+
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if contentEncoding := resp.Header.Get("Content-Encoding"); contentEncoding != "" && responseHasBody(resp) {
+		body, err := DecodeContentEncoding(resp.Body, contentEncoding, t.MaxResponseBytes)
+		if err != nil {
+			_ = resp.Body.Close()
+			return nil, err
+		}
+		resp.Body = body
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+	}
+	return resp, nil
+}
+
+// ContentEncodingMiddleware returns net/http middleware which transparently
+// decodes compressed request bodies before handlers bind them, limiting the
+// decoded size to maxBytes when it is positive. Requests with an unsupported
+// Content-Encoding are rejected with 415 Unsupported Media Type, and those
+// whose body can't be decoded with 400 Bad Request.
+func ContentEncodingMiddleware(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			contentEncoding := r.Header.Get("Content-Encoding")
+			if contentEncoding == "" || r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+			body, err := DecodeContentEncoding(r.Body, contentEncoding, maxBytes)
+			var unsupported *UnsupportedContentEncodingError
+			if errors.As(err, &unsupported) {
+				w.Header().Set("Accept-Encoding", defaultAcceptEncoding)
+				http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+				return
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			r = r.Clone(r.Context())
+			r.Body = body
+			r.Header.Del("Content-Encoding")
+			r.Header.Del("Content-Length")
+			r.ContentLength = -1
+			next.ServeHTTP(w, r)
+		})
+	}
+}
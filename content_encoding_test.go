@@ -0,0 +1,192 @@
+package runtime
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentEncodingRoundTrip(t *testing.T) {
+	payload := []byte(strings.Repeat(`{"name":"rex"}`, 100))
+	for _, coding := range []string{"gzip", "deflate", "br"} {
+		t.Run(coding, func(t *testing.T) {
+			encoded, err := EncodeContentEncoding(payload, coding)
+			require.NoError(t, err)
+			assert.Less(t, len(encoded), len(payload))
+
+			body, err := DecodeContentEncoding(io.NopCloser(bytes.NewReader(encoded)), coding, 0)
+			require.NoError(t, err)
+			decoded, err := io.ReadAll(body)
+			require.NoError(t, err)
+			assert.Equal(t, payload, decoded)
+		})
+	}
+}
+
+func TestDecodeContentEncoding(t *testing.T) {
+	payload := []byte(strings.Repeat("a", 1000))
+
+	t.Run("stacked codings", func(t *testing.T) {
+		gzipped, err := EncodeContentEncoding(payload, "gzip")
+		require.NoError(t, err)
+		encoded, err := EncodeContentEncoding(gzipped, "br")
+		require.NoError(t, err)
+		body, err := DecodeContentEncoding(io.NopCloser(bytes.NewReader(encoded)), "gzip, br", 0)
+		require.NoError(t, err)
+		decoded, err := io.ReadAll(body)
+		require.NoError(t, err)
+		assert.Equal(t, payload, decoded)
+	})
+
+	t.Run("raw deflate", func(t *testing.T) {
+		var buf bytes.Buffer
+		w, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+		_, _ = w.Write(payload)
+		_ = w.Close()
+		body, err := DecodeContentEncoding(io.NopCloser(&buf), "deflate", 0)
+		require.NoError(t, err)
+		decoded, err := io.ReadAll(body)
+		require.NoError(t, err)
+		assert.Equal(t, payload, decoded)
+	})
+
+	t.Run("size bomb", func(t *testing.T) {
+		encoded, err := EncodeContentEncoding(payload, "gzip")
+		require.NoError(t, err)
+		body, err := DecodeContentEncoding(io.NopCloser(bytes.NewReader(encoded)), "gzip", 100)
+		require.NoError(t, err)
+		_, err = io.ReadAll(body)
+		var tooLarge *BodyTooLargeError
+		assert.True(t, errors.As(err, &tooLarge))
+	})
+
+	t.Run("empty gzip body", func(t *testing.T) {
+		body, err := DecodeContentEncoding(io.NopCloser(bytes.NewReader(nil)), "gzip", 0)
+		require.NoError(t, err)
+		decoded, err := io.ReadAll(body)
+		require.NoError(t, err)
+		assert.Empty(t, decoded)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := DecodeContentEncoding(io.NopCloser(bytes.NewReader(payload)), "compress", 0)
+		var unsupported *UnsupportedContentEncodingError
+		require.True(t, errors.As(err, &unsupported))
+		assert.Equal(t, "compress", unsupported.Encoding)
+	})
+}
+
+func TestCompressionTransport(t *testing.T) {
+	payload := strings.Repeat(`{"name":"rex"}`, 100)
+	srv := httptest.NewServer(ContentEncodingMiddleware(1 << 20)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, payload, string(body))
+		assert.Equal(t, "gzip, deflate, br", r.Header.Get("Accept-Encoding"))
+
+		encoded, err := EncodeContentEncoding(body, "br")
+		require.NoError(t, err)
+		w.Header().Set("Content-Encoding", "br")
+		_, _ = w.Write(encoded)
+	})))
+	defer srv.Close()
+
+	var sentEncoding string
+	client := &http.Client{Transport: &CompressionTransport{
+		Base: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			sentEncoding = req.Header.Get("Content-Encoding")
+			return http.DefaultTransport.RoundTrip(req)
+		}),
+		RequestEncoding: "gzip",
+		MinRequestSize:  100,
+	}}
+	resp, err := client.Post(srv.URL, "application/json", strings.NewReader(payload))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, payload, string(body))
+	assert.Equal(t, "gzip", sentEncoding)
+	assert.Empty(t, resp.Header.Get("Content-Encoding"))
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("x"))
+	require.NoError(t, err)
+	req.Header.Set("Content-Encoding", "compress")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	req, err = http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("not gzip"))
+	require.NoError(t, err)
+	req.Header.Set("Content-Encoding", "gzip")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestCompressionTransport_StreamsRequests(t *testing.T) {
+	payload := strings.Repeat(`{"name":"rex"}`, 100)
+	type sent struct {
+		contentLength int64
+		encoding      string
+		body          string
+	}
+	var got []sent
+	transport := &CompressionTransport{
+		Base: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			s := sent{contentLength: req.ContentLength, encoding: req.Header.Get("Content-Encoding")}
+			body, err := DecodeContentEncoding(req.Body, s.encoding, 0)
+			require.NoError(t, err)
+			data, err := io.ReadAll(body)
+			require.NoError(t, err)
+			require.NoError(t, body.Close())
+			s.body = string(data)
+			got = append(got, s)
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: http.Header{}}, nil
+		}),
+		RequestEncoding: "gzip",
+		MinRequestSize:  100,
+	}
+	post := func(body io.Reader) *http.Request {
+		req, err := http.NewRequest(http.MethodPost, "http://example.com", body)
+		require.NoError(t, err)
+		resp, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return req
+	}
+
+	// Bodies of unknown length are compressed once they reach
+	// MinRequestSize.
+	post(io.MultiReader(strings.NewReader(payload)))
+	post(io.MultiReader(strings.NewReader("small")))
+	req := post(strings.NewReader(payload))
+	assert.Equal(t, []sent{
+		{contentLength: -1, encoding: "gzip", body: payload},
+		{contentLength: 5, body: "small"},
+		{contentLength: -1, encoding: "gzip", body: payload},
+	}, got)
+
+	// The original request is left untouched, and can be sent again.
+	assert.Empty(t, req.Header.Get("Content-Encoding"))
+	got = nil
+	req.Body, _ = req.GetBody()
+	post(req.Body)
+	assert.Equal(t, []sent{{contentLength: -1, encoding: "gzip", body: payload}}, got)
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
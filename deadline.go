@@ -0,0 +1,98 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RequestTimeoutHeader is the default header carrying the time a caller is
+// willing to wait for a response, so that a service can stop working on a
+// request its caller has given up on, and pass the remaining budget on to
+// the services it calls in turn.
+const RequestTimeoutHeader = "X-Request-Timeout"
+
+// ParseTimeout parses a timeout header value, either in the grpc-timeout
+// form, an integer of at most 8 digits followed by one of the units H, M,
+// S, m, u or n, or as a decimal number of seconds, such as "1.5".
+func ParseTimeout(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 {
+		var unit time.Duration
+		switch value[len(value)-1] {
+		case 'H':
+			unit = time.Hour
+		case 'M':
+			unit = time.Minute
+		case 'S':
+			unit = time.Second
+		case 'm':
+			unit = time.Millisecond
+		case 'u':
+			unit = time.Microsecond
+		case 'n':
+			unit = time.Nanosecond
+		}
+		if digits := value[:len(value)-1]; unit != 0 && len(digits) <= 8 {
+			n, err := strconv.ParseUint(digits, 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid timeout '%s': %w", value, err)
+			}
+			// Eight digits of hours or minutes overflow a time.Duration.
+			if n > math.MaxInt64/uint64(unit) {
+				return 0, fmt.Errorf("invalid timeout '%s': out of range", value)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(seconds) || seconds < 0 || seconds > float64(1<<63-1)/float64(time.Second) {
+		return 0, fmt.Errorf("invalid timeout '%s'", value)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// FormatTimeout formats d in the grpc-timeout form ParseTimeout accepts, in
+// milliseconds, rounded up so that a small remaining budget doesn't become
+// zero, or in coarser units when that would exceed 8 digits.
+func FormatTimeout(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	for _, u := range []struct {
+		unit   time.Duration
+		suffix string
+	}{{time.Millisecond, "m"}, {time.Second, "S"}, {time.Minute, "M"}} {
+		if n := (d + u.unit - 1) / u.unit; n <= 99999999 {
+			return strconv.FormatInt(int64(n), 10) + u.suffix
+		}
+	}
+	return strconv.FormatInt(int64((d+time.Hour-1)/time.Hour), 10) + "H"
+}
+
+// DeadlineRequestEditor returns a RequestEditorFn which sends the time left
+// until the deadline of the request context in header, RequestTimeoutHeader
+// when empty, so that the deadline a service received propagates to the
+// services it calls. Requests without a deadline are left alone, and those
+// whose deadline has passed fail with context.DeadlineExceeded.
+func DeadlineRequestEditor(header string) RequestEditorFn {
+	if header == "" {
+		header = RequestTimeoutHeader
+	}
+	return func(ctx context.Context, req *http.Request) error {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			return nil
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return context.DeadlineExceeded
+		}
+		req.Header.Set(header, FormatTimeout(remaining))
+		return nil
+	}
+}
@@ -0,0 +1,62 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimeout(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"2H":      2 * time.Hour,
+		"3M":      3 * time.Minute,
+		"10S":     10 * time.Second,
+		"1500m":   1500 * time.Millisecond,
+		"20u":     20 * time.Microsecond,
+		"7n":      7,
+		"1.5":     1500 * time.Millisecond,
+		"30":      30 * time.Second,
+		" 250m  ": 250 * time.Millisecond,
+	} {
+		d, err := ParseTimeout(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, d, value)
+	}
+	for _, value := range []string{"", "m", "-1", "123456789m", "1.5m", "abc", "1e300", "99999999H", "NaN"} {
+		_, err := ParseTimeout(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestFormatTimeout(t *testing.T) {
+	assert.Equal(t, "1500m", FormatTimeout(1500*time.Millisecond))
+	assert.Equal(t, "1m", FormatTimeout(time.Microsecond))
+	assert.Equal(t, "0m", FormatTimeout(-time.Second))
+	assert.Equal(t, "100000S", FormatTimeout(100000*time.Second))
+	d, err := ParseTimeout(FormatTimeout(42 * time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 42*time.Second, d)
+}
+
+func TestDeadlineRequestEditor(t *testing.T) {
+	editor := DeadlineRequestEditor("")
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	require.NoError(t, editor(context.Background(), req))
+	assert.Empty(t, req.Header.Get(RequestTimeoutHeader))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	require.NoError(t, editor(ctx, req))
+	d, err := ParseTimeout(req.Header.Get(RequestTimeoutHeader))
+	require.NoError(t, err)
+	assert.True(t, d > 59*time.Second && d <= time.Minute, d)
+
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	assert.ErrorIs(t, DeadlineRequestEditor("grpc-timeout")(expired, req), context.DeadlineExceeded)
+}
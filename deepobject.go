@@ -1,7 +1,7 @@
 package runtime
 
 import (
-	"encoding/json"
+	"bytes"
 	"errors"
 	"fmt"
 	"net/url"
@@ -14,20 +14,22 @@ import (
 	"github.com/oapi-codegen/runtime/types"
 )
 
-func marshalDeepObject(in interface{}, path []string) ([]string, error) {
-	var result []string
-
+// marshalDeepObject writes the deepObject style fields of in, found at
+// path below paramName, to buf, separating them with '&'. With escape, the
+// names and values of the fields are query escaped, as in a form body.
+func marshalDeepObject(buf *bytes.Buffer, paramName string, in interface{}, path []string, escape bool) error {
 	switch t := in.(type) {
+	case nil:
+		// Nil pointers, such as unset optional fields, have no value to
+		// write, and are left nil when unmarshaling.
+		return nil
 	case []interface{}:
 		// For the array, we will use numerical subscripts of the form [x],
 		// in the same order as the array.
 		for i, iface := range t {
-			newPath := append(path, strconv.Itoa(i))
-			fields, err := marshalDeepObject(iface, newPath)
-			if err != nil {
-				return nil, fmt.Errorf("error traversing array: %w", err)
+			if err := marshalDeepObject(buf, paramName, iface, append(path, strconv.Itoa(i)), escape); err != nil {
+				return fmt.Errorf("error traversing array: %w", err)
 			}
-			result = append(result, fields...)
 		}
 	case map[string]interface{}:
 		// For a map, each key (field name) becomes a member of the path, and
@@ -42,50 +44,86 @@ func marshalDeepObject(in interface{}, path []string) ([]string, error) {
 
 		// Now, for each key, we recursively marshal it.
 		for _, k := range keys {
-			newPath := append(path, k)
-			fields, err := marshalDeepObject(t[k], newPath)
-			if err != nil {
-				return nil, fmt.Errorf("error traversing map: %w", err)
+			if err := marshalDeepObject(buf, paramName, t[k], append(path, k), escape); err != nil {
+				return fmt.Errorf("error traversing map: %w", err)
 			}
-			result = append(result, fields...)
 		}
 	default:
 		// Now, for a concrete value, we will turn the path elements
 		// into a deepObject style set of subscripts. [a, b, c] turns into
-		// [a][b][c]
-		prefix := "[" + strings.Join(path, "][") + "]"
-		result = []string{
-			prefix + fmt.Sprintf("=%v", t),
+		// p[a][b][c]
+		if buf.Len() > 0 {
+			buf.WriteByte('&')
+		}
+		if escape {
+			name := paramName
+			for _, p := range path {
+				name += "[" + p + "]"
+			}
+			buf.WriteString(url.QueryEscape(name))
+			buf.WriteByte('=')
+			buf.WriteString(url.QueryEscape(fmt.Sprint(t)))
+			return nil
+		}
+		buf.WriteString(paramName)
+		for _, p := range path {
+			buf.WriteByte('[')
+			buf.WriteString(p)
+			buf.WriteByte(']')
+		}
+		buf.WriteByte('=')
+		if s, ok := t.(string); ok {
+			buf.WriteString(s)
+		} else {
+			fmt.Fprintf(buf, "%v", t)
 		}
 	}
-	return result, nil
+	return nil
 }
 
+// MarshalDeepObject styles i as the deepObject query parameter paramName,
+// whose name is query escaped. Errors name the parameter, as rendered by
+// DescribeParam. Object properties and map keys are written sorted by name,
+// and array items in order, so that the output is deterministic.
 func MarshalDeepObject(i interface{}, paramName string) (string, error) {
+	s, err := marshalDeepObjectParam(i, url.QueryEscape(paramName))
+	if err != nil {
+		return "", fmt.Errorf("error styling %s: %w", DescribeParam(paramName, ParamLocationQuery), err)
+	}
+	return s, nil
+}
+
+func marshalDeepObjectParam(i interface{}, paramName string) (string, error) {
+	return styleDeepObject(i, paramName, false)
+}
+
+// styleDeepObject styles i in the deepObject style, as marshalDeepObject
+// does.
+func styleDeepObject(i interface{}, paramName string, escape bool) (string, error) {
 	// We're going to marshal to JSON and unmarshal into an interface{},
 	// which will use the json pkg to deal with all the field annotations. We
 	// can then walk the generic object structure to produce a deepObject. This
 	// isn't efficient and it would be more efficient to reflect on our own,
-	// but it's complicated, error-prone code.
-	buf, err := json.Marshal(i)
+	// but it's complicated, error-prone code. Numbers are kept as
+	// json.Number, so that they are written as marshaled rather than as
+	// float64, which renders a million as 1e+06.
+	jsonBuf, err := jsonMarshal(i)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal input to JSON: %w", err)
 	}
 	var i2 interface{}
-	err = json.Unmarshal(buf, &i2)
+	err = jsonUnmarshalUseNumber(jsonBuf, &i2)
 	if err != nil {
 		return "", fmt.Errorf("failed to unmarshal JSON: %w", err)
 	}
-	fields, err := marshalDeepObject(i2, nil)
-	if err != nil {
-		return "", fmt.Errorf("error traversing JSON structure: %w", err)
-	}
 
-	// Prefix the param name to each subscripted field.
-	for i := range fields {
-		fields[i] = paramName + fields[i]
+	buf := getStyleBuffer()
+	defer putStyleBuffer(buf)
+	var path [8]string
+	if err := marshalDeepObject(buf, paramName, i2, path[:0], escape); err != nil {
+		return "", fmt.Errorf("error traversing JSON structure: %w", err)
 	}
-	return strings.Join(fields, "&"), nil
+	return buf.String(), nil
 }
 
 type fieldOrValue struct {
@@ -123,7 +161,15 @@ func makeFieldOrValue(paths [][]string, values []string) fieldOrValue {
 	return f
 }
 
+// UnmarshalDeepObject binds the deepObject style query parameter paramName
+// to dst, subject to the package-wide limits set with SetLimits.
 func UnmarshalDeepObject(dst interface{}, paramName string, params url.Values) error {
+	return unmarshalDeepObject(dst, paramName, params, GetLimits(), nil)
+}
+
+// unmarshalDeepObject binds a deepObject parameter. When warn isn't nil,
+// keys which dst has no field for are passed to it rather than failing.
+func unmarshalDeepObject(dst interface{}, paramName string, params url.Values, limits Limits, warn func(error)) error {
 	// Params are all the query args, so we need those that look like
 	// "paramName["...
 	var fieldNames []string
@@ -135,11 +181,25 @@ func UnmarshalDeepObject(dst interface{}, paramName string, params url.Values) e
 			pName = pName[len(paramName):]
 			fieldNames = append(fieldNames, pName)
 			if len(pValues) != 1 {
-				return fmt.Errorf("%s has multiple values", pName)
+				return &TooManyValuesError{
+					ParamName: paramName,
+					Location:  ParamLocationQuery,
+					Err:       fmt.Errorf("field %s is specified multiple times", pName),
+				}
+			}
+			if err := limits.checkParamLength(paramName, ParamLocationQuery, pValues[0]); err != nil {
+				return err
 			}
 			fieldValues = append(fieldValues, pValues[0])
 		}
 	}
+	if limits.MaxDeepObjectKeys > 0 && len(fieldNames) > limits.MaxDeepObjectKeys {
+		return &LimitExceededError{
+			ParamName: paramName,
+			Location:  ParamLocationQuery,
+			Err:       fmt.Errorf("more than %d deepObject keys", limits.MaxDeepObjectKeys),
+		}
+	}
 
 	// Now, for each field, reconstruct its subscript path and value
 	paths := make([][]string, len(fieldNames))
@@ -147,12 +207,22 @@ func UnmarshalDeepObject(dst interface{}, paramName string, params url.Values) e
 		path = strings.TrimLeft(path, "[")
 		path = strings.TrimRight(path, "]")
 		paths[i] = strings.Split(path, "][")
+		if limits.MaxDeepObjectDepth > 0 && len(paths[i]) > limits.MaxDeepObjectDepth {
+			return &LimitExceededError{
+				ParamName: paramName,
+				Location:  ParamLocationQuery,
+				Err:       fmt.Errorf("deepObject keys nested deeper than %d levels", limits.MaxDeepObjectDepth),
+			}
+		}
 	}
 
 	fieldPaths := makeFieldOrValue(paths, fieldValues)
-	err := assignPathValues(dst, fieldPaths)
+	err := assignPathValues(dst, fieldPaths, warn)
 	if err != nil {
-		return fmt.Errorf("error assigning value to destination: %w", err)
+		return &UnmarshalingParamError{ParamName: paramName, Location: ParamLocationQuery, Err: err}
+	}
+	if warn != nil {
+		warnInvalidValues(reflect.ValueOf(dst), "", warn)
 	}
 
 	return nil
@@ -192,7 +262,7 @@ func fieldIndicesByJSONTag(i interface{}) (map[string]int, error) {
 	return fieldMap, nil
 }
 
-func assignPathValues(dst interface{}, pathValues fieldOrValue) error {
+func assignPathValues(dst interface{}, pathValues fieldOrValue, warn func(error)) error {
 	//t := reflect.TypeOf(dst)
 	v := reflect.ValueOf(dst)
 
@@ -205,7 +275,7 @@ func assignPathValues(dst interface{}, pathValues fieldOrValue) error {
 		for key, value := range pathValues.fields {
 			dstKey := reflect.ValueOf(key)
 			dstVal := reflect.New(iv.Type().Elem())
-			err := assignPathValues(dstVal.Interface(), value)
+			err := assignPathValues(dstVal.Interface(), value, warn)
 			if err != nil {
 				return fmt.Errorf("error binding map: %w", err)
 			}
@@ -216,7 +286,7 @@ func assignPathValues(dst interface{}, pathValues fieldOrValue) error {
 	case reflect.Slice:
 		sliceLength := len(pathValues.fields)
 		dstSlice := reflect.MakeSlice(it, sliceLength, sliceLength)
-		err := assignSlice(dstSlice, pathValues)
+		err := assignSlice(dstSlice, pathValues, warn)
 		if err != nil {
 			return fmt.Errorf("error assigning slice: %w", err)
 		}
@@ -229,16 +299,17 @@ func assignPathValues(dst interface{}, pathValues fieldOrValue) error {
 		// the pointer, then set the value of the dereference pointer.
 
 		// We check to see if the object implements the Binder interface first.
+		if dst, ok := v.Interface().(lenientBinder); ok && warn != nil {
+			return dst.BindLenient(pathValues.value)
+		}
 		if dst, isBinder := v.Interface().(Binder); isBinder {
 			return dst.Bind(pathValues.value)
 		}
 		// Then check the legacy types
 		if it.ConvertibleTo(reflect.TypeOf(types.Date{})) {
 			var date types.Date
-			var err error
-			date.Time, err = time.Parse(types.DateFormat, pathValues.value)
-			if err != nil {
-				return fmt.Errorf("invalid date format: %w", err)
+			if err := date.UnmarshalText([]byte(pathValues.value)); err != nil {
+				return fmt.Errorf("invalid date: %w", err)
 			}
 			dst := iv
 			if it != reflect.TypeOf(types.Date{}) {
@@ -279,10 +350,15 @@ func assignPathValues(dst interface{}, pathValues fieldOrValue) error {
 			fieldValue := pathValues.fields[fieldName]
 			fieldIndex, found := fieldMap[fieldName]
 			if !found {
-				return fmt.Errorf("field [%s] is not present in destination object", fieldName)
+				err := fmt.Errorf("field [%s] is not present in destination object", fieldName)
+				if warn == nil {
+					return err
+				}
+				warn(err)
+				continue
 			}
 			field := iv.Field(fieldIndex)
-			err = assignPathValues(field.Addr().Interface(), fieldValue)
+			err = assignPathValues(field.Addr().Interface(), fieldValue, warn)
 			if err != nil {
 				return fmt.Errorf("error assigning field [%s]: %w", fieldName, err)
 			}
@@ -295,7 +371,7 @@ func assignPathValues(dst interface{}, pathValues fieldOrValue) error {
 		// interface.
 		dstVal := reflect.New(it.Elem())
 		dstPtr := dstVal.Interface()
-		err := assignPathValues(dstPtr, pathValues)
+		err := assignPathValues(dstPtr, pathValues, warn)
 		iv.Set(dstVal)
 		return err
 	case reflect.Bool:
@@ -329,15 +405,35 @@ func assignPathValues(dst interface{}, pathValues fieldOrValue) error {
 	case reflect.String:
 		iv.SetString(pathValues.value)
 		return nil
+	case reflect.Interface:
+		if it.NumMethod() > 0 {
+			return errors.New("unhandled type: " + it.String())
+		}
+		setInferred(iv, dynamicPathValue(pathValues))
+		return nil
 	default:
 		return errors.New("unhandled type: " + it.String())
 	}
 }
 
-func assignSlice(dst reflect.Value, pathValues fieldOrValue) error {
-	// Gather up the values
+// dynamicPathValue converts pathValues for an interface{} destination, which
+// declares no type: objects become a map[string]interface{}, and values are
+// converted as parameters bound into an interface{} are.
+func dynamicPathValue(pathValues fieldOrValue) interface{} {
+	if pathValues.fields == nil {
+		return inferParam(pathValues.value)
+	}
+	m := make(map[string]interface{}, len(pathValues.fields))
+	for key, value := range pathValues.fields {
+		m[key] = dynamicPathValue(value)
+	}
+	return m
+}
+
+func assignSlice(dst reflect.Value, pathValues fieldOrValue, warn func(error)) error {
+	// Gather up the values, which may be objects or collections themselves.
 	nValues := len(pathValues.fields)
-	values := make([]string, nValues)
+	values := make([]fieldOrValue, nValues)
 	// We expect to have consecutive array indices in the map
 	for i := 0; i < nValues; i++ {
 		indexStr := strconv.Itoa(i)
@@ -345,14 +441,14 @@ func assignSlice(dst reflect.Value, pathValues fieldOrValue) error {
 		if !found {
 			return errors.New("array deepObjects must have consecutive indices")
 		}
-		values[i] = fv.value
+		values[i] = fv
 	}
 
 	// This could be cleaner, but we can call into assignPathValues to
 	// avoid recreating this logic.
 	for i := 0; i < nValues; i++ {
 		dstElem := dst.Index(i).Addr()
-		err := assignPathValues(dstElem.Interface(), fieldOrValue{value: values[i]})
+		err := assignPathValues(dstElem.Interface(), values[i], warn)
 		if err != nil {
 			return fmt.Errorf("error binding array: %w", err)
 		}
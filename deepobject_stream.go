@@ -0,0 +1,227 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// UnmarshalDeepObjectFromRawQuery binds the deepObject style query parameter
+// paramName to dst, like UnmarshalDeepObject, but reads it straight from a
+// raw query string, such as r.URL.RawQuery. The query is scanned once, and
+// each key is assigned to dst as it is found, so that neither a url.Values
+// of the whole query nor a tree of the parameter's keys is built first. It
+// suits endpoints receiving thousands of deepObject keys.
+//
+// Values are merged into dst: maps it already holds are added to rather
+// than replaced. Array indices may come in any order, but must be
+// consecutive once the query is read.
+func UnmarshalDeepObjectFromRawQuery(dst interface{}, paramName string, rawQuery string) error {
+	return unmarshalDeepObjectFromRawQuery(dst, paramName, rawQuery, GetLimits(), nil)
+}
+
+func unmarshalDeepObjectFromRawQuery(dst interface{}, paramName string, rawQuery string, limits Limits, warn func(error)) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("%s: destination should be a non-nil pointer", DescribeParam(paramName, ParamLocationQuery))
+	}
+	d := &deepObjectStream{
+		warn: warn,
+		// An array can't have more items than the query has keys, which
+		// bounds what a large index can make us allocate.
+		maxIndex: strings.Count(rawQuery, "&"),
+		seen:     make(map[string]struct{}),
+		indices:  make(map[string]*deepObjectIndices),
+		fields:   make(map[reflect.Type]map[string]int),
+	}
+	prefix := paramName + "["
+	var keys int
+	for rawQuery != "" {
+		var pair string
+		pair, rawQuery, _ = strings.Cut(rawQuery, "&")
+		rawKey, rawValue, _ := strings.Cut(pair, "=")
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil || !strings.HasPrefix(key, prefix) {
+			// Malformed keys can't be ours, as url.ParseQuery would have
+			// dropped them too.
+			continue
+		}
+		value, err := url.QueryUnescape(rawValue)
+		if err != nil {
+			return &InvalidParamFormatError{ParamName: paramName, Location: ParamLocationQuery, Err: err}
+		}
+		if err := limits.checkParamLength(paramName, ParamLocationQuery, value); err != nil {
+			return err
+		}
+		if _, dup := d.seen[key]; dup {
+			return &TooManyValuesError{
+				ParamName: paramName,
+				Location:  ParamLocationQuery,
+				Err:       fmt.Errorf("field %s is specified multiple times", key[len(paramName):]),
+			}
+		}
+		d.seen[key] = struct{}{}
+
+		keys++
+		if limits.MaxDeepObjectKeys > 0 && keys > limits.MaxDeepObjectKeys {
+			return &LimitExceededError{
+				ParamName: paramName,
+				Location:  ParamLocationQuery,
+				Err:       fmt.Errorf("more than %d deepObject keys", limits.MaxDeepObjectKeys),
+			}
+		}
+		path := strings.Split(strings.TrimRight(strings.TrimLeft(key[len(paramName):], "["), "]"), "][")
+		if limits.MaxDeepObjectDepth > 0 && len(path) > limits.MaxDeepObjectDepth {
+			return &LimitExceededError{
+				ParamName: paramName,
+				Location:  ParamLocationQuery,
+				Err:       fmt.Errorf("deepObject keys nested deeper than %d levels", limits.MaxDeepObjectDepth),
+			}
+		}
+		if err := d.assign(v.Elem(), "", path, value); err != nil {
+			return &UnmarshalingParamError{ParamName: paramName, Location: ParamLocationQuery, Err: err}
+		}
+	}
+	for prefix, indices := range d.indices {
+		if len(indices.seen) != indices.length {
+			return &UnmarshalingParamError{
+				ParamName: paramName,
+				Location:  ParamLocationQuery,
+				Err:       fmt.Errorf("array [%s]: array deepObjects must have consecutive indices", prefix),
+			}
+		}
+	}
+	if warn != nil {
+		warnInvalidValues(v, "", warn)
+	}
+	return nil
+}
+
+// deepObjectStream holds the state of an UnmarshalDeepObjectFromRawQuery
+// call.
+type deepObjectStream struct {
+	warn     func(error)
+	maxIndex int
+	// seen holds the keys assigned so far, to reject repeated ones.
+	seen map[string]struct{}
+	// indices holds the indices assigned so far of each array, by path,
+	// to check that they are consecutive once the query is read.
+	indices map[string]*deepObjectIndices
+	// fields caches the fields of struct types by JSON name.
+	fields map[reflect.Type]map[string]int
+}
+
+type deepObjectIndices struct {
+	seen   map[int]struct{}
+	length int
+}
+
+// assign sets the value at path below v, an addressable value, whose own
+// path is prefix.
+func (d *deepObjectStream) assign(v reflect.Value, prefix string, path []string, value string) error {
+	if len(path) == 0 {
+		return assignPathValues(v.Addr().Interface(), fieldOrValue{value: value}, d.warn)
+	}
+	name, rest := path[0], path[1:]
+	fieldPath := prefix + "[" + name + "]"
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.assign(v.Elem(), prefix, path, value)
+	case reflect.Struct:
+		fields, ok := d.fields[v.Type()]
+		if !ok {
+			var err error
+			if fields, err = fieldIndicesByJSONTag(v.Interface()); err != nil {
+				return fmt.Errorf("failed enumerating fields: %w", err)
+			}
+			d.fields[v.Type()] = fields
+		}
+		i, found := fields[name]
+		if !found || !v.Field(i).CanSet() {
+			err := fmt.Errorf("field %s is not present in destination object", fieldPath)
+			if d.warn == nil {
+				return err
+			}
+			d.warn(err)
+			return nil
+		}
+		return d.assign(v.Field(i), fieldPath, rest, value)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return errors.New("unhandled type: " + v.Type().String())
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		key := reflect.ValueOf(name).Convert(v.Type().Key())
+		// Map elements aren't addressable, so work on a copy.
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(key); existing.IsValid() {
+			elem.Set(existing)
+		}
+		if err := d.assign(elem, fieldPath, rest, value); err != nil {
+			return fmt.Errorf("error binding map: %w", err)
+		}
+		v.SetMapIndex(key, elem)
+		return nil
+	case reflect.Slice:
+		i, err := strconv.Atoi(name)
+		if err != nil || i < 0 {
+			return fmt.Errorf("array index %s is invalid", fieldPath)
+		}
+		if i > d.maxIndex {
+			return fmt.Errorf("array index %s is out of range", fieldPath)
+		}
+		indices := d.indices[prefix]
+		if indices == nil {
+			indices = &deepObjectIndices{seen: make(map[int]struct{})}
+			d.indices[prefix] = indices
+			// The array replaces any dst held.
+			v.SetLen(0)
+		}
+		indices.seen[i] = struct{}{}
+		if i >= indices.length {
+			indices.length = i + 1
+		}
+		if n := v.Len(); i >= n {
+			if i >= v.Cap() {
+				// Grow geometrically, as append does, since indices usually
+				// come in ascending order.
+				c := 2 * v.Cap()
+				if c < i+1 {
+					c = i + 1
+				}
+				grown := reflect.MakeSlice(v.Type(), n, c)
+				reflect.Copy(grown, v)
+				v.Set(grown)
+			}
+			v.SetLen(i + 1)
+			for j := n; j <= i; j++ {
+				v.Index(j).Set(reflect.Zero(v.Type().Elem()))
+			}
+		}
+		if err := d.assign(v.Index(i), fieldPath, rest, value); err != nil {
+			return fmt.Errorf("error binding array: %w", err)
+		}
+		return nil
+	case reflect.Interface:
+		if v.NumMethod() > 0 {
+			return errors.New("unhandled type: " + v.Type().String())
+		}
+		m, ok := v.Interface().(map[string]interface{})
+		if !ok {
+			m = make(map[string]interface{})
+			v.Set(reflect.ValueOf(m))
+		}
+		return d.assign(reflect.ValueOf(&m).Elem(), prefix, path, value)
+	default:
+		return fmt.Errorf("field %s is not present in destination of type %s", fieldPath, v.Type())
+	}
+}
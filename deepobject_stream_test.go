@@ -0,0 +1,124 @@
+package runtime
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalDeepObjectFromRawQuery(t *testing.T) {
+	admins := []InnerObject{{Name: "Alex", ID: 1}}
+	in := NestedCollections{
+		Items:  &[]InnerObject{{Name: "a", ID: 1}, {Name: "b", ID: 2}},
+		Counts: &map[string]int{"x": 3},
+		Groups: &map[string]*[]InnerObject{"admins": &admins},
+		Inner: &struct {
+			IDs *[]int `json:"ids,omitempty"`
+		}{IDs: &[]int{7, 8}},
+	}
+	marshaled, err := MarshalDeepObject(in, "p")
+	require.NoError(t, err)
+
+	var out NestedCollections
+	require.NoError(t, UnmarshalDeepObjectFromRawQuery(&out, "p", "other=1&"+marshaled))
+	assert.Equal(t, in, out)
+}
+
+func TestUnmarshalDeepObjectFromRawQuery_Arrays(t *testing.T) {
+	query := url.Values{}
+	for i := 0; i < 12; i++ {
+		query.Set(fmt.Sprintf("p[ids][%d]", i), fmt.Sprint(i))
+	}
+	// Encode sorts keys, so p[ids][10] comes before p[ids][2].
+	var out struct {
+		IDs []int `json:"ids"`
+	}
+	out.IDs = []int{99, 98, 97}
+	require.NoError(t, UnmarshalDeepObjectFromRawQuery(&out, "p", query.Encode()))
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, out.IDs)
+
+	err := UnmarshalDeepObjectFromRawQuery(&out, "p", "p[ids][0]=1&p[ids][2]=3&q=1")
+	assert.ErrorContains(t, err, "consecutive indices")
+	err = UnmarshalDeepObjectFromRawQuery(&out, "p", "p[ids][100000000]=1")
+	assert.ErrorContains(t, err, "out of range")
+}
+
+func TestUnmarshalDeepObjectFromRawQuery_Errors(t *testing.T) {
+	var obj InnerObject
+	err := UnmarshalDeepObjectFromRawQuery(&obj, "p", "p%5BName%5D=a&p[Name]=b")
+	var tooMany *TooManyValuesError
+	assert.ErrorAs(t, err, &tooMany)
+
+	err = UnmarshalDeepObjectFromRawQuery(&obj, "p", "p[Name]=a&p[Age]=3")
+	var unmarshalErr *UnmarshalingParamError
+	require.ErrorAs(t, err, &unmarshalErr)
+	assert.ErrorContains(t, err, "field [Age] is not present")
+
+	var warnings []error
+	obj = InnerObject{}
+	require.NoError(t, unmarshalDeepObjectFromRawQuery(&obj, "p", "p[Name]=a+b&p[Age]=3&p[ID]=4", GetLimits(), func(err error) {
+		warnings = append(warnings, err)
+	}))
+	assert.Equal(t, InnerObject{Name: "a b", ID: 4}, obj)
+	assert.Len(t, warnings, 1)
+
+	err = unmarshalDeepObjectFromRawQuery(&obj, "p", "p[Name]=a&p[ID]=4", Limits{MaxDeepObjectKeys: 1}, nil)
+	var limitErr *LimitExceededError
+	assert.ErrorAs(t, err, &limitErr)
+}
+
+func TestUnmarshalDeepObjectFromRawQuery_DynamicMap(t *testing.T) {
+	var dst map[string]interface{}
+	require.NoError(t, UnmarshalDeepObjectFromRawQuery(&dst, "filter", "filter[active]=true&filter[owner][id]=7&filter[owner][tag]=x"))
+	assert.Equal(t, map[string]interface{}{
+		"active": true,
+		"owner":  map[string]interface{}{"id": int64(7), "tag": "x"},
+	}, dst)
+}
+
+func largeDeepObjectQuery(n int) string {
+	query := url.Values{"page": {"1"}}
+	for i := 0; i < n; i++ {
+		query.Set(fmt.Sprintf("filter[items][%d][Name]", i), fmt.Sprintf("item-%d", i))
+		query.Set(fmt.Sprintf("filter[labels][key%d]", i), fmt.Sprint(i))
+	}
+	return query.Encode()
+}
+
+type largeDeepObject struct {
+	Items  []InnerObject     `json:"items"`
+	Labels map[string]string `json:"labels"`
+}
+
+func BenchmarkUnmarshalDeepObject_Large(b *testing.B) {
+	defer SetLimits(GetLimits())
+	SetLimits(Limits{})
+	rawQuery := largeDeepObjectQuery(2000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		params, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			b.Fatal(err)
+		}
+		var dst largeDeepObject
+		if err := UnmarshalDeepObject(&dst, "filter", params); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalDeepObjectFromRawQuery_Large(b *testing.B) {
+	defer SetLimits(GetLimits())
+	SetLimits(Limits{})
+	rawQuery := largeDeepObjectQuery(2000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var dst largeDeepObject
+		if err := UnmarshalDeepObjectFromRawQuery(&dst, "filter", rawQuery); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
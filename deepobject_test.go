@@ -1,6 +1,7 @@
 package runtime
 
 import (
+	"encoding/json"
 	"net/url"
 	"strings"
 	"testing"
@@ -84,3 +85,81 @@ func TestDeepObject(t *testing.T) {
 	require.NoError(t, err)
 	assert.EqualValues(t, srcObj, dstObj)
 }
+
+type NestedCollections struct {
+	Items  *[]InnerObject             `json:"items,omitempty"`
+	Tags   *[]string                  `json:"tags"`
+	Counts *map[string]int            `json:"counts,omitempty"`
+	Groups *map[string]*[]InnerObject `json:"groups,omitempty"`
+	Inner  *struct {
+		IDs *[]int `json:"ids,omitempty"`
+	} `json:"inner,omitempty"`
+}
+
+func TestDeepObject_PointerCollections(t *testing.T) {
+	admins := []InnerObject{{Name: "Alex", ID: 1}}
+	in := NestedCollections{
+		Items:  &[]InnerObject{{Name: "a", ID: 1}, {Name: "b", ID: 2}},
+		Counts: &map[string]int{"x": 3},
+		Groups: &map[string]*[]InnerObject{"admins": &admins},
+		Inner: &struct {
+			IDs *[]int `json:"ids,omitempty"`
+		}{IDs: &[]int{7, 8}},
+	}
+	marshaled, err := MarshalDeepObject(in, "p")
+	require.NoError(t, err)
+	assert.NotContains(t, marshaled, "tags", "nil collections are left out")
+
+	params, err := url.ParseQuery(marshaled)
+	require.NoError(t, err)
+	var out NestedCollections
+	require.NoError(t, UnmarshalDeepObject(&out, "p", params))
+	assert.Equal(t, in, out)
+	assert.Nil(t, out.Tags)
+}
+
+func BenchmarkMarshalDeepObject(b *testing.B) {
+	value := map[string]interface{}{
+		"name":  "Alex",
+		"roles": []string{"admin", "user"},
+		"address": map[string]interface{}{
+			"city": "Berlin",
+			"zip":  10115,
+		},
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := MarshalDeepObject(value, "p"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestDeepObject_DynamicMap(t *testing.T) {
+	params := url.Values{
+		"filter[id]":         {"1234567890123456789012"},
+		"filter[price]":      {"0.1"},
+		"filter[active]":     {"true"},
+		"filter[owner][id]":  {"7"},
+		"filter[owner][tag]": {"x"},
+	}
+	var dst map[string]interface{}
+	require.NoError(t, UnmarshalDeepObject(&dst, "filter", params))
+	assert.Equal(t, map[string]interface{}{
+		"id":     1.2345678901234568e+21,
+		"price":  0.1,
+		"active": true,
+		"owner":  map[string]interface{}{"id": int64(7), "tag": "x"},
+	}, dst)
+
+	defer SetUseJSONNumber(false)
+	SetUseJSONNumber(true)
+	dst = nil
+	require.NoError(t, UnmarshalDeepObject(&dst, "filter", params))
+	assert.Equal(t, map[string]interface{}{
+		"id":     json.Number("1234567890123456789012"),
+		"price":  json.Number("0.1"),
+		"active": true,
+		"owner":  map[string]interface{}{"id": json.Number("7"), "tag": "x"},
+	}, dst)
+}
@@ -0,0 +1,83 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+)
+
+// HeaderRegistry maps operation IDs and tags to default headers, such as an
+// API version, tenant, or Accept override, which its RequestEditor applies to
+// every matching request. The zero value is an empty registry ready to use,
+// and a HeaderRegistry is safe for concurrent use: lookups read a snapshot
+// without locking, and changes publish a new one.
+type HeaderRegistry struct {
+	cow copyOnWrite[headerRegistryState]
+}
+
+type headerRegistryState struct {
+	operations map[string]http.Header
+	tags       map[string]http.Header
+}
+
+// SetOperationHeaders sets the default headers for the operation with the
+// given ID, replacing any set before. It panics after Freeze.
+func (r *HeaderRegistry) SetOperationHeaders(operationID string, headers http.Header) {
+	r.cow.update("HeaderRegistry", func(s *headerRegistryState) {
+		s.operations = cloneMap(s.operations)
+		s.operations[operationID] = headers.Clone()
+	})
+}
+
+// SetTagHeaders sets the default headers for all operations with the given
+// tag, replacing any set before. It panics after Freeze.
+func (r *HeaderRegistry) SetTagHeaders(tag string, headers http.Header) {
+	r.cow.update("HeaderRegistry", func(s *headerRegistryState) {
+		s.tags = cloneMap(s.tags)
+		s.tags[tag] = headers.Clone()
+	})
+}
+
+// Freeze makes the registry read-only, so that changes made after startup,
+// which would likely be mistakes, panic rather than take effect.
+func (r *HeaderRegistry) Freeze() {
+	r.cow.freeze()
+}
+
+// Headers returns the default headers for the given operation. Headers for
+// its tags are applied in tag order, and headers registered for the
+// operation ID itself take precedence over all of them.
+func (r *HeaderRegistry) Headers(info OperationInfo) http.Header {
+	result := make(http.Header)
+	s := r.cow.load()
+	if s == nil {
+		return result
+	}
+	for _, tag := range info.Tags {
+		for k, v := range s.tags[tag] {
+			result[k] = append([]string(nil), v...)
+		}
+	}
+	for k, v := range s.operations[info.ID] {
+		result[k] = append([]string(nil), v...)
+	}
+	return result
+}
+
+// RequestEditor returns a RequestEditorFn which adds the default headers for
+// the operation recorded in the request context. Headers already present on
+// the request are left alone, so per-call values always win.
+func (r *HeaderRegistry) RequestEditor() RequestEditorFn {
+	return func(ctx context.Context, req *http.Request) error {
+		info, ok := GetOperationInfo(ctx)
+		if !ok {
+			return nil
+		}
+		for k, v := range r.Headers(info) {
+			if _, present := req.Header[k]; present {
+				continue
+			}
+			req.Header[k] = append([]string(nil), v...)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,50 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderRegistry(t *testing.T) {
+	var registry HeaderRegistry
+	registry.SetTagHeaders("billing", http.Header{
+		"X-Api-Version": []string{"2024-01-01"},
+		"X-Tenant":      []string{"acme"},
+	})
+	registry.SetTagHeaders("beta", http.Header{"X-Api-Version": []string{"2025-01-01"}})
+	registry.SetOperationHeaders("getInvoicePdf", http.Header{"Accept": []string{"application/pdf"}})
+	registry.SetOperationHeaders("createInvoice", http.Header{"X-Tenant": []string{"acme-eu"}})
+
+	editor := registry.RequestEditor()
+	apply := func(info OperationInfo, preset http.Header) http.Header {
+		req := httptest.NewRequest(http.MethodGet, "/invoices", nil)
+		for k, v := range preset {
+			req.Header[k] = v
+		}
+		require.NoError(t, editor(WithOperationInfo(context.Background(), info), req))
+		return req.Header
+	}
+
+	h := apply(OperationInfo{ID: "getInvoicePdf", Tags: []string{"billing"}}, nil)
+	assert.Equal(t, "application/pdf", h.Get("Accept"))
+	assert.Equal(t, "acme", h.Get("X-Tenant"))
+	assert.Equal(t, "2024-01-01", h.Get("X-Api-Version"))
+
+	// Later tags override earlier ones, and the operation overrides tags.
+	h = apply(OperationInfo{ID: "createInvoice", Tags: []string{"billing", "beta"}}, nil)
+	assert.Equal(t, "2025-01-01", h.Get("X-Api-Version"))
+	assert.Equal(t, "acme-eu", h.Get("X-Tenant"))
+
+	// Explicit request headers win.
+	h = apply(OperationInfo{ID: "getInvoicePdf"}, http.Header{"Accept": []string{"application/json"}})
+	assert.Equal(t, "application/json", h.Get("Accept"))
+
+	// Unknown operations get nothing.
+	h = apply(OperationInfo{ID: "listPets"}, nil)
+	assert.Empty(t, h)
+}
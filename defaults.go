@@ -0,0 +1,120 @@
+package runtime
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// defaultTagName is the struct tag holding a field's default value, as
+// declared by its schema.
+const defaultTagName = "default"
+
+// ApplyDefaults sets the fields of the struct dest points to which are unset
+// to the value of their default tag, such as
+//
+//	Limit *int `json:"limit,omitempty" default:"20"`
+//
+// A field is unset when it holds its zero value: a nil pointer, slice or
+// map, or the zero value of any other type. Nullable fields, maps keyed by
+// bool as github.com/oapi-codegen/nullable represents them, are unset only
+// when they weren't specified at all, so an explicit null is kept. Since a
+// false, 0 or "" can't be told apart from an absent value otherwise, fields
+// whose default isn't a zero value should be pointers or Nullable.
+//
+// String fields take the tag as it is. Types implementing
+// encoding.TextUnmarshaler, such as types.Date, unmarshal it as text, and
+// all other types, including slices and structs, as JSON, so a slice
+// default reads default:"[1,2]". ApplyDefaults descends into nested
+// structs, pointers to structs, and slices of structs, so that it can be
+// run on bound parameters and decoded bodies alike.
+func ApplyDefaults(dest any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.New("defaults destination should be a pointer to a struct")
+	}
+	return applyStructDefaults(v.Elem())
+}
+
+func applyStructDefaults(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+		if def, ok := t.Field(i).Tag.Lookup(defaultTagName); ok && field.IsZero() {
+			if err := setDefault(field, def); err != nil {
+				return fmt.Errorf("field %s: invalid default '%s': %w", t.Field(i).Name, def, err)
+			}
+		}
+		if err := applyNestedDefaults(field); err != nil {
+			return fmt.Errorf("field %s: %w", t.Field(i).Name, err)
+		}
+	}
+	return nil
+}
+
+// applyNestedDefaults descends into the structs v holds.
+func applyNestedDefaults(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return applyNestedDefaults(v.Elem())
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := applyNestedDefaults(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		if isTextUnmarshaler(v) {
+			return nil
+		}
+		return applyStructDefaults(v)
+	}
+	return nil
+}
+
+// setDefault parses def into the unset field v.
+func setDefault(v reflect.Value, def string) error {
+	switch {
+	case v.Kind() == reflect.Ptr:
+		elem := reflect.New(v.Type().Elem())
+		if err := setDefault(elem.Elem(), def); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	case isNullable(v.Type()):
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if err := setDefault(elem, def); err != nil {
+			return err
+		}
+		m := reflect.MakeMapWithSize(v.Type(), 1)
+		m.SetMapIndex(reflect.ValueOf(true), elem)
+		v.Set(m)
+		return nil
+	case v.Kind() == reflect.String:
+		v.SetString(def)
+		return nil
+	case isTextUnmarshaler(v):
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(def))
+	default:
+		return jsonUnmarshal([]byte(def), v.Addr().Interface())
+	}
+}
+
+// isNullable reports whether t is shaped like nullable.Nullable, a map
+// keyed by bool.
+func isNullable(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.Bool
+}
+
+func isTextUnmarshaler(v reflect.Value) bool {
+	_, ok := v.Addr().Interface().(encoding.TextUnmarshaler)
+	return ok
+}
@@ -0,0 +1,55 @@
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oapi-codegen/runtime/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nullable mirrors the shape of nullable.Nullable.
+type nullable[T any] map[bool]T
+
+func TestApplyDefaults(t *testing.T) {
+	type Filter struct {
+		Field string  `json:"field" default:"name"`
+		Order *string `json:"order,omitempty" default:"asc"`
+	}
+	type Params struct {
+		Limit    *int             `json:"limit,omitempty" default:"20"`
+		Tags     []string         `json:"tags,omitempty" default:"[\"a\",\"b\"]"`
+		Since    types.Date       `json:"since" default:"2020-01-02"`
+		Note     nullable[string] `json:"note,omitempty" default:"none"`
+		Comment  nullable[string] `json:"comment,omitempty" default:"none"`
+		Verbose  bool             `json:"verbose"`
+		Filter   Filter           `json:"filter"`
+		Filters  []Filter         `json:"filters"`
+		Optional *Filter          `json:"optional,omitempty"`
+	}
+
+	limit := 5
+	p := Params{
+		Limit:   &limit,
+		Comment: nullable[string]{false: ""},
+		Filters: []Filter{{Field: "age"}},
+	}
+	require.NoError(t, ApplyDefaults(&p))
+	assert.Equal(t, 5, *p.Limit)
+	assert.Equal(t, []string{"a", "b"}, p.Tags)
+	assert.Equal(t, types.Date{Time: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)}, p.Since)
+	assert.Equal(t, nullable[string]{true: "none"}, p.Note)
+	assert.Equal(t, nullable[string]{false: ""}, p.Comment)
+	assert.Equal(t, "name", p.Filter.Field)
+	assert.Equal(t, "asc", *p.Filter.Order)
+	assert.Equal(t, "age", p.Filters[0].Field)
+	assert.Equal(t, "asc", *p.Filters[0].Order)
+	assert.Nil(t, p.Optional)
+
+	type Invalid struct {
+		Count int `default:"many"`
+	}
+	assert.Error(t, ApplyDefaults(&Invalid{}))
+	assert.Error(t, ApplyDefaults(Params{}))
+}
@@ -0,0 +1,230 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ResponseError is returned by DecodeErrorResponse for error responses which
+// no registered decoder handles. It keeps everything needed to inspect the
+// failure after the response body has been closed.
+type ResponseError struct {
+	StatusCode  int
+	Status      string
+	ContentType string
+	Header      http.Header
+	Body        []byte
+	// RetryAfter is the delay requested by a Retry-After header, or zero.
+	RetryAfter time.Duration
+}
+
+func (e *ResponseError) Error() string {
+	msg := "unexpected status " + e.Status
+	if e.Status == "" {
+		msg = "unexpected status " + strconv.Itoa(e.StatusCode)
+	}
+	if problem, ok := e.Problem(); ok {
+		for _, s := range []string{problem.Title, problem.Detail} {
+			if s != "" {
+				msg += ": " + s
+			}
+		}
+	}
+	return msg
+}
+
+// Problem parses a JSON body, such as an application/problem+json one, as
+// RFC 9457 problem details. It reports false if the body isn't JSON.
+func (e *ResponseError) Problem() (*ProblemDetails, bool) {
+	mediaType, _, _ := mime.ParseMediaType(e.ContentType)
+	if !isJSONMediaType(mediaType) {
+		return nil, false
+	}
+	var problem ProblemDetails
+	if err := jsonUnmarshal(e.Body, &problem); err != nil {
+		return nil, false
+	}
+	return &problem, true
+}
+
+// ErrorDecoderFunc turns an error response into a typed error. body holds
+// the already read response body. Returning nil makes DecodeErrorResponse
+// fall back to a *ResponseError.
+type ErrorDecoderFunc func(resp *http.Response, body []byte) error
+
+type errorDecoderEntry struct {
+	minStatus, maxStatus int
+	mediaType            string
+	decode               ErrorDecoderFunc
+}
+
+// ErrorDecoderRegistry maps ranges of status codes and content types to
+// ErrorDecoderFuncs. The zero value is an empty registry ready to use, and an
+// ErrorDecoderRegistry is safe for concurrent use: lookups read a snapshot
+// without locking, and registrations publish a new one.
+type ErrorDecoderRegistry struct {
+	cow copyOnWrite[errorDecoderState]
+}
+
+type errorDecoderState struct {
+	entries []errorDecoderEntry
+}
+
+// Register adds a decoder for responses with a status code between minStatus
+// and maxStatus, inclusive, and the given media type. The media type may be
+// empty or "*/*" to match any content type, a range such as "application/*",
+// or a structured syntax suffix such as "*/*+json". When several decoders
+// match, the one with the narrowest status range wins, then the one with the
+// most specific media type, then the one registered last. It panics after
+// Freeze.
+func (r *ErrorDecoderRegistry) Register(minStatus, maxStatus int, mediaType string, decode ErrorDecoderFunc) {
+	if mediaType == "" {
+		mediaType = "*/*"
+	}
+	r.cow.update("ErrorDecoderRegistry", func(s *errorDecoderState) {
+		s.entries = append(s.entries[:len(s.entries):len(s.entries)], errorDecoderEntry{
+			minStatus: minStatus,
+			maxStatus: maxStatus,
+			mediaType: strings.ToLower(mediaType),
+			decode:    decode,
+		})
+	})
+}
+
+// Freeze makes the registry read-only, so that registrations made after
+// startup, which would likely be mistakes, panic rather than take effect.
+func (r *ErrorDecoderRegistry) Freeze() {
+	r.cow.freeze()
+}
+
+func (r *ErrorDecoderRegistry) lookup(statusCode int, mediaType string) ErrorDecoderFunc {
+	s := r.cow.load()
+	if s == nil {
+		return nil
+	}
+	var best *errorDecoderEntry
+	bestSpecificity := -1
+	for i := range s.entries {
+		e := &s.entries[i]
+		if statusCode < e.minStatus || statusCode > e.maxStatus {
+			continue
+		}
+		specificity := mediaTypeSpecificity(e.mediaType, mediaType)
+		if specificity < 0 {
+			continue
+		}
+		if best != nil {
+			width, bestWidth := e.maxStatus-e.minStatus, best.maxStatus-best.minStatus
+			if width > bestWidth || (width == bestWidth && specificity < bestSpecificity) {
+				continue
+			}
+		}
+		best, bestSpecificity = e, specificity
+	}
+	if best == nil {
+		return nil
+	}
+	return best.decode
+}
+
+// mediaTypeSpecificity reports how specifically pattern matches mediaType:
+// 3 for an exact match, 2 for a type range such as application/*, 1 for a
+// suffix such as */*+json, 0 for */* and -1 for no match.
+func mediaTypeSpecificity(pattern, mediaType string) int {
+	switch {
+	case pattern == mediaType:
+		return 3
+	case strings.HasSuffix(pattern, "/*") && pattern != "*/*":
+		if strings.HasPrefix(mediaType, strings.TrimSuffix(pattern, "*")) {
+			return 2
+		}
+	case strings.HasPrefix(pattern, "*/*+"):
+		if strings.HasSuffix(mediaType, strings.TrimPrefix(pattern, "*/*")) {
+			return 1
+		}
+	case pattern == "*/*":
+		return 0
+	}
+	return -1
+}
+
+// DecodeErrorResponse turns an error response, one with a status code of 400
+// or above, into an error, using the best matching decoder from registry,
+// which may be nil. Without a matching decoder, a *ResponseError is
+// returned. 412 responses always give a *PreconditionFailedError, which
+// wraps the error of a matching decoder as well. The response body is read
+// and closed. For successful responses it returns nil
+// without touching the body.
+func DecodeErrorResponse(resp *http.Response, registry *ErrorDecoderRegistry) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading %d response body: %w", resp.StatusCode, err)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	var decoded error
+	if registry != nil {
+		if decode := registry.lookup(resp.StatusCode, mediaType); decode != nil {
+			decoded = decode(resp, body)
+		}
+	}
+	if decoded != nil && resp.StatusCode != http.StatusPreconditionFailed {
+		return decoded
+	}
+
+	retryAfter, _ := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	responseErr := &ResponseError{
+		StatusCode:  resp.StatusCode,
+		Status:      resp.Status,
+		ContentType: contentType,
+		Header:      resp.Header,
+		Body:        body,
+		RetryAfter:  retryAfter,
+	}
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		return &PreconditionFailedError{ResponseError: responseErr, Current: ValidatorsFromResponse(resp), Decoded: decoded}
+	}
+	return responseErr
+}
+
+// parseRetryAfter parses a Retry-After header value, which is either a
+// number of seconds or an HTTP date, into a delay relative to now. Dates in
+// the past yield a zero delay, and delays too long for a time.Duration are
+// clamped to the longest one.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	// ParseInt returns math.MaxInt64 along with ErrRange for numbers too big
+	// for it.
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil || (errors.Is(err, strconv.ErrRange) && seconds > 0) {
+		if seconds < 0 {
+			return 0, false
+		}
+		if seconds > math.MaxInt64/int64(time.Second) {
+			return math.MaxInt64, true
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if d := date.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
@@ -0,0 +1,108 @@
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type conflictError struct {
+	Resource string `json:"resource"`
+}
+
+func (e *conflictError) Error() string {
+	return "conflict on " + e.Resource
+}
+
+func errorResponse(status int, contentType, body string, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Content-Type", contentType)
+	return &http.Response{
+		StatusCode: status,
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestDecodeErrorResponse(t *testing.T) {
+	var registry ErrorDecoderRegistry
+	registry.Register(409, 409, "application/json", func(resp *http.Response, body []byte) error {
+		var e conflictError
+		if err := json.Unmarshal(body, &e); err != nil {
+			return err
+		}
+		return &e
+	})
+	registry.Register(400, 499, "", func(resp *http.Response, body []byte) error {
+		return errors.New("client error: " + string(body))
+	})
+	registry.Register(500, 599, "*/*+json", func(resp *http.Response, body []byte) error {
+		return nil
+	})
+
+	t.Run("exact decoder", func(t *testing.T) {
+		err := DecodeErrorResponse(errorResponse(409, "application/json", `{"resource":"pet"}`, nil), &registry)
+		var conflict *conflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, "pet", conflict.Resource)
+	})
+
+	t.Run("range decoder", func(t *testing.T) {
+		err := DecodeErrorResponse(errorResponse(404, "text/plain", "no such pet", nil), &registry)
+		assert.EqualError(t, err, "client error: no such pet")
+	})
+
+	t.Run("fallback with problem details and retry-after", func(t *testing.T) {
+		resp := errorResponse(503, "application/problem+json", `{"title":"Unavailable","detail":"maintenance"}`,
+			http.Header{"Retry-After": []string{"120"}})
+		err := DecodeErrorResponse(resp, &registry)
+		var responseErr *ResponseError
+		require.ErrorAs(t, err, &responseErr)
+		assert.Equal(t, 503, responseErr.StatusCode)
+		assert.Equal(t, 2*time.Minute, responseErr.RetryAfter)
+		assert.EqualError(t, err, "unexpected status 503 Service Unavailable: Unavailable: maintenance")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		assert.NoError(t, DecodeErrorResponse(errorResponse(200, "application/json", "{}", nil), nil))
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	d, ok := parseRetryAfter("30", now)
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, d)
+
+	d, ok = parseRetryAfter("Mon, 01 Jan 2024 12:01:30 GMT", now)
+	assert.True(t, ok)
+	assert.Equal(t, 90*time.Second, d)
+
+	d, ok = parseRetryAfter("Mon, 01 Jan 2024 11:00:00 GMT", now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), d)
+
+	for _, long := range []string{"9223372037", "99999999999999999999"} {
+		d, ok = parseRetryAfter(long, now)
+		assert.True(t, ok, long)
+		assert.Equal(t, time.Duration(math.MaxInt64), d, long)
+	}
+
+	for _, invalid := range []string{"", "-1", "soon"} {
+		_, ok = parseRetryAfter(invalid, now)
+		assert.False(t, ok, invalid)
+	}
+}
@@ -0,0 +1,62 @@
+package runtime
+
+import (
+	"sync/atomic"
+
+	"golang.org/x/text/language"
+)
+
+// BindingErrorFormatter renders a BindingError as a message in the language
+// with the BCP 47 tag lang, such as "de" or "pt-BR". lang is empty when no
+// language was requested. Returning an empty string falls back to the
+// error's own, English, message.
+type BindingErrorFormatter func(err BindingError, lang string) string
+
+type bindingErrorFormatterHolder struct {
+	format BindingErrorFormatter
+}
+
+var bindingErrorFormatter atomic.Pointer[bindingErrorFormatterHolder]
+
+// SetBindingErrorFormatter installs the formatter consulted whenever the
+// runtime renders a BindingError for a client, as NewProblemFromError does,
+// so that APIs can localize their 400 responses in one place. Passing nil
+// restores the default of using the error's message. It panics after
+// FreezeSettings.
+func SetBindingErrorFormatter(format BindingErrorFormatter) {
+	checkSettingsFrozen("SetBindingErrorFormatter")
+	if format == nil {
+		bindingErrorFormatter.Store(nil)
+		return
+	}
+	bindingErrorFormatter.Store(&bindingErrorFormatterHolder{format: format})
+}
+
+// FormatBindingError renders err in the language lang using the formatter
+// installed with SetBindingErrorFormatter, falling back to err.Error().
+func FormatBindingError(err BindingError, lang string) string {
+	if h := bindingErrorFormatter.Load(); h != nil {
+		if msg := h.format(err, lang); msg != "" {
+			return msg
+		}
+	}
+	return err.Error()
+}
+
+// NegotiateLanguage picks the language from supported which best matches an
+// Accept-Language header, such as "fr-CH, fr;q=0.9, en;q=0.8", following
+// the BCP 47 matching rules of golang.org/x/text/language, so that "de-AT"
+// matches a supported "de". supported should be in the server's order of
+// preference. When nothing matches, or the header is empty, the first
+// supported language is returned, and an empty string if there is none.
+func NegotiateLanguage(acceptLanguage string, supported []string) string {
+	if len(supported) == 0 {
+		return ""
+	}
+	tags := make([]language.Tag, len(supported))
+	for i, s := range supported {
+		tags[i] = language.Make(s)
+	}
+	_, index := language.MatchStrings(language.NewMatcher(tags), acceptLanguage)
+	return supported[index]
+}
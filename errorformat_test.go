@@ -0,0 +1,33 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatBindingError(t *testing.T) {
+	err := &RequiredParamError{ParamName: "id", Location: ParamLocationQuery}
+	assert.Equal(t, "query parameter 'id' is required", FormatBindingError(err, "de"))
+
+	SetBindingErrorFormatter(func(err BindingError, lang string) string {
+		if _, ok := err.(*RequiredParamError); ok && lang == "de" {
+			return "Parameter '" + err.Param() + "' ist erforderlich"
+		}
+		return ""
+	})
+	defer SetBindingErrorFormatter(nil)
+
+	assert.Equal(t, "Parameter 'id' ist erforderlich", FormatBindingError(err, "de"))
+	assert.Equal(t, "query parameter 'id' is required", FormatBindingError(err, "en"))
+	assert.Equal(t, "Parameter 'id' ist erforderlich", NewLocalizedProblemFromError(err, "de").Detail)
+}
+
+func TestNegotiateLanguage(t *testing.T) {
+	supported := []string{"en", "de", "pt-BR"}
+	assert.Equal(t, "de", NegotiateLanguage("de-AT, en;q=0.5", supported))
+	assert.Equal(t, "pt-BR", NegotiateLanguage("pt-BR;q=0.9, fr", supported))
+	assert.Equal(t, "en", NegotiateLanguage("ja", supported))
+	assert.Equal(t, "en", NegotiateLanguage("", supported))
+	assert.Equal(t, "", NegotiateLanguage("de", nil))
+}
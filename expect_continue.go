@@ -0,0 +1,42 @@
+package runtime
+
+import (
+	"net/http"
+)
+
+// ExpectContinueTransport is an http.RoundTripper which sends large request
+// bodies with an "Expect: 100-continue" header. The body is then only
+// uploaded once the server has agreed to receive it, so a request the server
+// rejects up front, for example with 401, 413 or 415, doesn't waste the
+// bandwidth of sending it.
+//
+// The waiting is done by the underlying *http.Transport, which must have a
+// non-zero ExpectContinueTimeout; http.DefaultTransport does. When the
+// server doesn't answer within that timeout, the body is sent anyway, as
+// servers which don't support the mechanism require.
+type ExpectContinueTransport struct {
+	// Base is the transport used to send requests. It defaults to
+	// http.DefaultTransport.
+	Base http.RoundTripper
+	// Threshold is the body size from which the header is sent. Bodies of
+	// unknown length, including those with a zero ContentLength, are
+	// treated as large.
+	Threshold int64
+}
+
+func (t *ExpectContinueTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *ExpectContinueTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil || req.Body == http.NoBody || req.Header.Get("Expect") != "" ||
+		(req.ContentLength > 0 && req.ContentLength < t.Threshold) {
+		return t.base().RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Expect", "100-continue")
+	return t.base().RoundTrip(req)
+}
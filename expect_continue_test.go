@@ -0,0 +1,79 @@
+package runtime
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingReader counts the bytes read from it.
+type countingReader struct {
+	r io.Reader
+	n atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+func TestExpectContinueTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			// Reject before reading the body, which makes the server answer
+			// the expectation with this final status.
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("X-Expect", r.Header.Get("Expect"))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &ExpectContinueTransport{Threshold: 1 << 10}}
+	upload := func(size int, authorized bool) (*http.Response, int64) {
+		body := &countingReader{r: strings.NewReader(strings.Repeat("a", size))}
+		req, err := http.NewRequest(http.MethodPut, srv.URL, body)
+		require.NoError(t, err)
+		req.ContentLength = int64(size)
+		if authorized {
+			req.Header.Set("Authorization", "Bearer token")
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return resp, body.n.Load()
+	}
+
+	resp, sent := upload(1<<20, false)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Zero(t, sent)
+
+	resp, sent = upload(1<<20, true)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "100-continue", resp.Header.Get("X-Expect"))
+	assert.Equal(t, int64(1<<20), sent)
+
+	resp, _ = upload(10, true)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("X-Expect"))
+
+	// A body with a zero ContentLength is of unknown length.
+	req, err := http.NewRequest(http.MethodPut, srv.URL, &countingReader{r: strings.NewReader("a")})
+	require.NoError(t, err)
+	require.Zero(t, req.ContentLength)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "100-continue", resp.Header.Get("X-Expect"))
+}
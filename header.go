@@ -0,0 +1,25 @@
+package runtime
+
+import (
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// HeaderValues returns the values of the header name in h, matching the
+// name case-insensitively. http.Header.Values only finds headers stored
+// under their canonical key, which misses headers set on the map directly,
+// or whose names, such as X_Api_Key, Go doesn't canonicalize, so generated
+// code binding header parameters should look them up with this instead.
+// Values stored under several spellings of the name are all returned,
+// those under the canonical key first.
+func HeaderValues(h http.Header, name string) []string {
+	canonical := textproto.CanonicalMIMEHeaderKey(name)
+	values := h[canonical]
+	for key, vs := range h {
+		if key != canonical && strings.EqualFold(key, name) {
+			values = append(values[:len(values):len(values)], vs...)
+		}
+	}
+	return values
+}
@@ -0,0 +1,20 @@
+package runtime
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderValues(t *testing.T) {
+	h := http.Header{}
+	h.Set("X-Request-Id", "a")
+	h["x-request-id"] = []string{"b"}
+	h["X_api_key"] = []string{"secret"}
+
+	assert.Equal(t, []string{"a", "b"}, HeaderValues(h, "x-request-id"))
+	assert.Equal(t, []string{"secret"}, HeaderValues(h, "X_Api_Key"))
+	assert.Empty(t, HeaderValues(h, "X-Missing"))
+	assert.Equal(t, []string{"a"}, h.Values("X-Request-Id"), "h is left unchanged")
+}
@@ -0,0 +1,50 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKeyHeader is the header carrying the idempotency key of an
+// unsafe request, so that a server can recognize a retried request and avoid
+// applying it twice.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// WithIdempotencyKey returns a copy of ctx carrying the given idempotency
+// key, which IdempotencyKeyEditor will send with the request.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey, key)
+}
+
+// GetIdempotencyKey returns the idempotency key stored in ctx, and whether
+// there was one.
+func GetIdempotencyKey(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyContextKey).(string)
+	return key, ok
+}
+
+// IdempotencyKeyEditor is a RequestEditorFn which sets the Idempotency-Key
+// header. A key stored in ctx with WithIdempotencyKey is always used. Without
+// one, a random UUID is generated for POST and PATCH requests, which are not
+// idempotent by themselves. An Idempotency-Key header which is already
+// present is left alone.
+//
+// Since the header is set once, before the request reaches the transport,
+// every attempt made by RetryTransport carries the same key, and
+// RetryTransport treats such requests as safe to retry.
+func IdempotencyKeyEditor(ctx context.Context, req *http.Request) error {
+	if req.Header.Get(IdempotencyKeyHeader) != "" {
+		return nil
+	}
+	key, ok := GetIdempotencyKey(ctx)
+	if !ok {
+		if req.Method != http.MethodPost && req.Method != http.MethodPatch {
+			return nil
+		}
+		key = uuid.NewString()
+	}
+	req.Header.Set(IdempotencyKeyHeader, key)
+	return nil
+}
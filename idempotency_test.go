@@ -0,0 +1,74 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyKeyEditor(t *testing.T) {
+	t.Run("uses key from context", func(t *testing.T) {
+		ctx := WithIdempotencyKey(context.Background(), "order-42")
+		key, ok := GetIdempotencyKey(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, "order-42", key)
+
+		req := httptest.NewRequest(http.MethodPut, "/orders/42", nil)
+		require.NoError(t, IdempotencyKeyEditor(ctx, req))
+		assert.Equal(t, "order-42", req.Header.Get(IdempotencyKeyHeader))
+	})
+
+	t.Run("generates a key for POST", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		require.NoError(t, IdempotencyKeyEditor(context.Background(), req))
+		_, err := uuid.Parse(req.Header.Get(IdempotencyKeyHeader))
+		assert.NoError(t, err)
+	})
+
+	t.Run("leaves safe methods alone", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+		require.NoError(t, IdempotencyKeyEditor(context.Background(), req))
+		assert.Empty(t, req.Header.Get(IdempotencyKeyHeader))
+	})
+
+	t.Run("keeps an existing header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		req.Header.Set(IdempotencyKeyHeader, "explicit")
+		require.NoError(t, IdempotencyKeyEditor(WithIdempotencyKey(context.Background(), "ctx"), req))
+		assert.Equal(t, "explicit", req.Header.Get(IdempotencyKeyHeader))
+	})
+}
+
+func TestIdempotencyKey_RetriedWithSameKey(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("{}"))
+	require.NoError(t, err)
+	require.NoError(t, IdempotencyKeyEditor(req.Context(), req))
+
+	client := &http.Client{Transport: &RetryTransport{Policy: RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}}}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, keys, 2)
+	assert.Equal(t, keys[0], keys[1])
+}
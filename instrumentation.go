@@ -0,0 +1,102 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// OperationSpanName returns a low-cardinality span name for a client
+// request: its operation ID if known, otherwise the method and path
+// template, and as a last resort only the method. Raw URLs are never used,
+// since they embed IDs and would explode span cardinality.
+//
+// The signature matches otelhttp.WithSpanNameFormatter, so it can be passed
+// to otelhttp.NewTransport as is.
+func OperationSpanName(_ string, r *http.Request) string {
+	info, _ := GetOperationInfo(r.Context())
+	if info.ID != "" {
+		return info.ID
+	}
+	if info.PathTemplate != "" {
+		return r.Method + " " + info.PathTemplate
+	}
+	return r.Method
+}
+
+// OperationLabels returns metric labels describing the operation of a client
+// request, taken from its context rather than its URL. Labels with unknown
+// values are omitted.
+func OperationLabels(r *http.Request) map[string]string {
+	info, _ := GetOperationInfo(r.Context())
+	labels := map[string]string{"method": r.Method}
+	if info.ID != "" {
+		labels["operation"] = info.ID
+	}
+	if info.PathTemplate != "" {
+		labels["path_template"] = info.PathTemplate
+	}
+	return labels
+}
+
+// ClientRequestMetrics describes a completed client request, as reported to
+// InstrumentedTransport.Observe.
+type ClientRequestMetrics struct {
+	// Operation is the operation metadata from the request context, with
+	// Method filled in from the request if it wasn't recorded.
+	Operation OperationInfo
+	// StatusCode is zero when the request failed without a response.
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+}
+
+// InstrumentedTransport is an http.RoundTripper which reports each request to
+// tracing and metrics hooks using the operation metadata from the request
+// context, making it easy to adapt to any telemetry library.
+type InstrumentedTransport struct {
+	// Base is the transport used to send requests. It defaults to
+	// http.DefaultTransport.
+	Base http.RoundTripper
+	// StartSpan, if set, is called before each request with the name from
+	// OperationSpanName. The returned context is used for the outgoing
+	// request, so trace propagation can happen there, and end is called with
+	// the outcome once the response headers, or an error, arrive.
+	StartSpan func(ctx context.Context, name string, req *http.Request) (_ context.Context, end func(resp *http.Response, err error))
+	// Observe, if set, is called after each request.
+	Observe func(ClientRequestMetrics)
+}
+
+func (t *InstrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	var end func(*http.Response, error)
+	if t.StartSpan != nil {
+		var ctx context.Context
+		ctx, end = t.StartSpan(req.Context(), OperationSpanName("", req), req)
+		req = req.WithContext(ctx)
+	}
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	duration := time.Since(start)
+
+	if end != nil {
+		end(resp, err)
+	}
+	if t.Observe != nil {
+		info, _ := GetOperationInfo(req.Context())
+		if info.Method == "" {
+			info.Method = req.Method
+		}
+		metrics := ClientRequestMetrics{Operation: info, Duration: duration, Err: err}
+		if resp != nil {
+			metrics.StatusCode = resp.StatusCode
+		}
+		t.Observe(metrics)
+	}
+	return resp, err
+}
@@ -0,0 +1,66 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationSpanName(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/pets/123", nil)
+	assert.Equal(t, "GET", OperationSpanName("", req))
+
+	ctx := WithOperationInfo(context.Background(), OperationInfo{PathTemplate: "/pets/{petId}"})
+	assert.Equal(t, "GET /pets/{petId}", OperationSpanName("", req.WithContext(ctx)))
+
+	ctx = WithOperationID(ctx, "getPet")
+	assert.Equal(t, "getPet", OperationSpanName("", req.WithContext(ctx)))
+	assert.Equal(t, map[string]string{
+		"method":        "GET",
+		"operation":     "getPet",
+		"path_template": "/pets/{petId}",
+	}, OperationLabels(req.WithContext(ctx)))
+}
+
+type spanKey struct{}
+
+func TestInstrumentedTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	var spans []string
+	var ended bool
+	var observed []ClientRequestMetrics
+	transport := &InstrumentedTransport{
+		StartSpan: func(ctx context.Context, name string, req *http.Request) (context.Context, func(*http.Response, error)) {
+			spans = append(spans, name)
+			return context.WithValue(ctx, spanKey{}, name), func(resp *http.Response, err error) {
+				ended = true
+				assert.Equal(t, "createPet", resp.Request.Context().Value(spanKey{}))
+			}
+		},
+		Observe: func(m ClientRequestMetrics) {
+			observed = append(observed, m)
+		},
+	}
+
+	ctx := WithOperationInfo(context.Background(), OperationInfo{ID: "createPet", PathTemplate: "/pets"})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+"/pets", nil)
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, []string{"createPet"}, spans)
+	assert.True(t, ended)
+	require.Len(t, observed, 1)
+	assert.Equal(t, OperationInfo{ID: "createPet", Method: "POST", PathTemplate: "/pets"}, observed[0].Operation)
+	assert.Equal(t, http.StatusCreated, observed[0].StatusCode)
+	assert.NoError(t, observed[0].Err)
+}
@@ -0,0 +1,111 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync/atomic"
+)
+
+// JSONDecoder is the streaming decoder of a JSONCodec, mirroring the parts
+// of encoding/json.Decoder the runtime relies on.
+type JSONDecoder interface {
+	Decode(v interface{}) error
+	// UseNumber makes Decode unmarshal numbers into an interface{} as an
+	// encoding/json.Number instead of a float64.
+	UseNumber()
+	DisallowUnknownFields()
+}
+
+// JSONCodec is the JSON implementation used for all marshaling done by the
+// runtime: request and response bodies, JSON encoded parameters, and the JSON
+// round trips used to style and bind objects. Implementations must be
+// compatible with encoding/json, honoring its struct tags and the
+// json.Marshaler and json.Unmarshaler interfaces, as drop-in replacements
+// such as github.com/goccy/go-json and github.com/json-iterator/go are.
+type JSONCodec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+	NewDecoder(r io.Reader) JSONDecoder
+}
+
+// StdJSONCodec is the JSONCodec backed by encoding/json, which is the
+// default.
+type StdJSONCodec struct{}
+
+func (StdJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (StdJSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (StdJSONCodec) NewDecoder(r io.Reader) JSONDecoder {
+	return json.NewDecoder(r)
+}
+
+type jsonCodecHolder struct {
+	codec JSONCodec
+}
+
+var jsonCodec atomic.Pointer[jsonCodecHolder]
+
+// SetJSONCodec replaces the JSONCodec used by the runtime. Passing nil
+// restores StdJSONCodec. It is meant to be called once during program
+// initialization, but is safe to call concurrently with marshaling. It
+// panics after FreezeSettings.
+func SetJSONCodec(codec JSONCodec) {
+	checkSettingsFrozen("SetJSONCodec")
+	if codec == nil {
+		jsonCodec.Store(nil)
+		return
+	}
+	jsonCodec.Store(&jsonCodecHolder{codec: codec})
+}
+
+// GetJSONCodec returns the JSONCodec used by the runtime.
+func GetJSONCodec() JSONCodec {
+	if h := jsonCodec.Load(); h != nil {
+		return h.codec
+	}
+	return StdJSONCodec{}
+}
+
+func jsonMarshal(v interface{}) ([]byte, error) {
+	return GetJSONCodec().Marshal(v)
+}
+
+func jsonUnmarshal(data []byte, v interface{}) error {
+	return GetJSONCodec().Unmarshal(data, v)
+}
+
+func newJSONDecoder(r io.Reader) JSONDecoder {
+	return GetJSONCodec().NewDecoder(r)
+}
+
+// jsonUnmarshalDynamic is jsonUnmarshal, except that it decodes numbers into
+// interface{} values as json.Number while SetUseJSONNumber is in effect.
+func jsonUnmarshalDynamic(data []byte, v interface{}) error {
+	if !useJSONNumber.Load() {
+		return jsonUnmarshal(data, v)
+	}
+	return jsonUnmarshalUseNumber(data, v)
+}
+
+// jsonUnmarshalUseNumber is jsonUnmarshal, except that it decodes numbers
+// into interface{} values as json.Number.
+func jsonUnmarshalUseNumber(data []byte, v interface{}) error {
+	dec := newJSONDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	// Reject trailing data, as Unmarshal does.
+	var trailing json.RawMessage
+	if err := dec.Decode(&trailing); !errors.Is(err, io.EOF) {
+		return errors.New("invalid data after top-level value")
+	}
+	return nil
+}
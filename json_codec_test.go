@@ -0,0 +1,66 @@
+package runtime
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingCodec wraps encoding/json, counting how often it is used.
+type countingCodec struct {
+	StdJSONCodec
+	marshals, unmarshals, decoders int
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshals++
+	return json.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v interface{}) error {
+	c.unmarshals++
+	return json.Unmarshal(data, v)
+}
+
+func (c *countingCodec) NewDecoder(r io.Reader) JSONDecoder {
+	c.decoders++
+	return json.NewDecoder(r)
+}
+
+func TestSetJSONCodec(t *testing.T) {
+	codec := &countingCodec{}
+	SetJSONCodec(codec)
+	defer SetJSONCodec(nil)
+	assert.Equal(t, codec, GetJSONCodec())
+
+	var dest struct {
+		Name string `json:"name"`
+	}
+	resp, _ := newTestResponse(http.StatusOK, "application/json", `{"name":"rex"}`)
+	require.NoError(t, BindResponse(resp, &dest))
+	assert.Equal(t, "rex", dest.Name)
+	assert.Equal(t, 1, codec.unmarshals)
+
+	_, err := MarshalDeepObject(dest, "pet")
+	require.NoError(t, err)
+	assert.Equal(t, 1, codec.marshals)
+
+	SetJSONCodec(nil)
+	assert.Equal(t, StdJSONCodec{}, GetJSONCodec())
+}
+
+func TestJSONUnmarshalDynamic_TrailingData(t *testing.T) {
+	SetUseJSONNumber(true)
+	defer SetUseJSONNumber(false)
+
+	var v interface{}
+	require.NoError(t, jsonUnmarshalDynamic([]byte(" 12 "), &v))
+	assert.Equal(t, json.Number("12"), v)
+	for _, data := range []string{"1 2", "1 ]", `{"a":1}}`} {
+		assert.Error(t, jsonUnmarshalDynamic([]byte(data), &v), data)
+	}
+}
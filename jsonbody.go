@@ -0,0 +1,221 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync/atomic"
+)
+
+// UnknownFieldError is returned when a JSON body contains a property which
+// its destination has no field for, and unknown fields are disallowed.
+type UnknownFieldError struct {
+	Field string
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("unknown field '%s'", e.Field)
+}
+
+// BindJSONBodyOptions configures BindJSONBodyWithOptions.
+type BindJSONBodyOptions struct {
+	// DisallowUnknownFields rejects bodies with properties which the
+	// destination has no field for, with an *UnknownFieldError.
+	DisallowUnknownFields bool
+	// UseNumber decodes numbers into interface{} values, such as those of
+	// untyped properties and of map[string]interface{} additional
+	// properties, as json.Number instead of float64, so that 64-bit
+	// integers and decimals keep their precision.
+	UseNumber bool
+	// Warnings, when set, switches to lenient binding, as described for
+	// WithBindWarnings, recording problems in it rather than failing.
+	Warnings *BindWarnings
+}
+
+var (
+	disallowUnknownFields atomic.Bool
+	useJSONNumber         atomic.Bool
+)
+
+// SetDisallowUnknownFields sets whether BindJSONBody rejects unknown
+// properties, for APIs which must reject unrecognized input everywhere. It
+// panics after FreezeSettings.
+func SetDisallowUnknownFields(disallow bool) {
+	checkSettingsFrozen("SetDisallowUnknownFields")
+	disallowUnknownFields.Store(disallow)
+}
+
+// SetUseJSONNumber sets whether numbers bound into interface{} values, such
+// as those of map[string]interface{} destinations, become json.Number
+// instead of float64, as BindJSONBodyOptions.UseNumber does. It applies to
+// BindJSONBody, JSON encoded properties of form bodies,
+// AdditionalPropertiesMap, and, unless SetParamInferrer installed an
+// inferrer of its own, deepObject parameters. It panics after FreezeSettings.
+func SetUseJSONNumber(useNumber bool) {
+	checkSettingsFrozen("SetUseJSONNumber")
+	useJSONNumber.Store(useNumber)
+}
+
+// BindJSONBody decodes a JSON request body into dest. Unknown properties are
+// ignored, unless SetDisallowUnknownFields was used to reject them, and
+// numbers are decoded into interface{} values as float64, unless
+// SetUseJSONNumber was used to preserve them.
+func BindJSONBody(body io.Reader, dest interface{}) error {
+	return BindJSONBodyWithOptions(body, dest, BindJSONBodyOptions{
+		DisallowUnknownFields: disallowUnknownFields.Load(),
+		UseNumber:             useJSONNumber.Load(),
+	})
+}
+
+// BindJSONBodyWithOptions decodes a JSON request body into dest, with the
+// given options taking the place of the package defaults.
+func BindJSONBodyWithOptions(body io.Reader, dest interface{}, opts BindJSONBodyOptions) error {
+	if err := decodeJSONBody(body, dest, opts); err != nil {
+		return err
+	}
+	return validateBound(dest)
+}
+
+// decodeJSONBody is BindJSONBodyWithOptions without the validation, for
+// BindRequest, which validates its whole destination once it is bound.
+func decodeJSONBody(body io.Reader, dest interface{}, opts BindJSONBodyOptions) error {
+	warn := opts.Warnings.paramWarner("", ParamLocationUndefined)
+	strict := opts.DisallowUnknownFields && warn == nil
+	if !strict && warn == nil {
+		dec := newJSONDecoder(body)
+		if opts.UseNumber {
+			dec.UseNumber()
+		}
+		return decodeJSONValue(dec, dest)
+	}
+
+	// The body is looked into before it is unmarshaled, so it is read
+	// whole first.
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("error reading request body: %w", err)
+	}
+	dec := newJSONDecoder(bytes.NewReader(data))
+	var value json.RawMessage
+	if err := decodeJSONValue(dec, &value); err != nil {
+		return err
+	}
+	if opts.DisallowUnknownFields {
+		// Unknown properties are found by comparing the body with the
+		// fields of dest rather than left to the decoder, so that they are
+		// reported the same whatever the JSONCodec, and all of them rather
+		// than the first.
+		for _, field := range unknownJSONFields(value, reflect.TypeOf(dest), "") {
+			if strict {
+				return &UnknownFieldError{Field: field}
+			}
+			warn(&UnknownFieldError{Field: field})
+		}
+	}
+	var trailing json.RawMessage
+	if err := dec.Decode(&trailing); !errors.Is(err, io.EOF) {
+		err := errors.New("unexpected data after JSON body")
+		if strict {
+			return err
+		}
+		warn(err)
+	}
+
+	unmarshal := jsonUnmarshal
+	if opts.UseNumber {
+		unmarshal = jsonUnmarshalUseNumber
+	}
+	if v := reflect.ValueOf(dest); warn != nil && v.Kind() == reflect.Ptr && !v.IsNil() {
+		// Values such as types.Enum accept what they would otherwise
+		// reject, for warnInvalidValues to report.
+		err = unmarshalJSONLenient(value, v.Elem(), unmarshal)
+	} else {
+		err = unmarshal(value, dest)
+	}
+	if err != nil {
+		return fmt.Errorf("error decoding JSON body: %w", err)
+	}
+	if warn != nil {
+		warnInvalidValues(reflect.ValueOf(dest), "", warn)
+	}
+	return nil
+}
+
+// decodeJSONValue decodes the next value of dec into dest.
+func decodeJSONValue(dec JSONDecoder, dest interface{}) error {
+	if err := dec.Decode(dest); err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.New("request body is empty")
+		}
+		return fmt.Errorf("error decoding JSON body: %w", err)
+	}
+	return nil
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// unknownJSONFields returns the paths of the properties in the JSON data
+// which a value of type t has no field for, sorted within each object, at
+// any depth. Values of types unmarshaling themselves are not looked into,
+// and data which doesn't have the shape of t is left to the decoder to
+// report.
+func unknownJSONFields(data []byte, t reflect.Type, path string) []string {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || reflect.PtrTo(t).Implements(jsonUnmarshalerType) || reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return nil
+	}
+	var unknown []string
+	switch t.Kind() {
+	case reflect.Struct:
+		var object map[string]json.RawMessage
+		if jsonUnmarshal(data, &object) != nil {
+			return nil
+		}
+		known := knownJSONProperties(t)
+		for _, name := range sortedPropertyNames(object) {
+			fieldType, ok := lookupJSONProperty(known, name)
+			if !ok {
+				unknown = append(unknown, joinPath(path, name))
+				continue
+			}
+			unknown = append(unknown, unknownJSONFields(object[name], fieldType, joinPath(path, name))...)
+		}
+	case reflect.Map:
+		var object map[string]json.RawMessage
+		if jsonUnmarshal(data, &object) != nil {
+			return nil
+		}
+		for _, name := range sortedPropertyNames(object) {
+			unknown = append(unknown, unknownJSONFields(object[name], t.Elem(), joinPath(path, name))...)
+		}
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if jsonUnmarshal(data, &items) != nil {
+			return nil
+		}
+		for i, item := range items {
+			unknown = append(unknown, unknownJSONFields(item, t.Elem(), path+"["+strconv.Itoa(i)+"]")...)
+		}
+	}
+	return unknown
+}
+
+func sortedPropertyNames(object map[string]json.RawMessage) []string {
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
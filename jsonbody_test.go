@@ -0,0 +1,105 @@
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindJSONBody(t *testing.T) {
+	type Pet struct {
+		Name string `json:"name"`
+	}
+	body := `{"name":"rex","color":"brown"}`
+
+	var pet Pet
+	require.NoError(t, BindJSONBody(strings.NewReader(body), &pet))
+	assert.Equal(t, "rex", pet.Name)
+
+	err := BindJSONBodyWithOptions(strings.NewReader(body), &pet, BindJSONBodyOptions{DisallowUnknownFields: true})
+	var unknown *UnknownFieldError
+	require.True(t, errors.As(err, &unknown))
+	assert.Equal(t, "color", unknown.Field)
+
+	SetDisallowUnknownFields(true)
+	defer SetDisallowUnknownFields(false)
+	err = BindJSONBody(strings.NewReader(body), &pet)
+	assert.True(t, errors.As(err, &unknown))
+	require.NoError(t, BindJSONBodyWithOptions(strings.NewReader(body), &pet, BindJSONBodyOptions{}))
+
+	assert.Error(t, BindJSONBody(strings.NewReader(""), &pet))
+	assert.Error(t, BindJSONBody(strings.NewReader(`{"name":1}`), &pet))
+}
+
+func TestBindJSONBody_UseNumber(t *testing.T) {
+	var dest map[string]interface{}
+	require.NoError(t, BindJSONBodyWithOptions(strings.NewReader(`{"id":1234567890123456789}`), &dest, BindJSONBodyOptions{UseNumber: true}))
+	assert.Equal(t, json.Number("1234567890123456789"), dest["id"])
+
+	defer SetUseJSONNumber(false)
+	SetUseJSONNumber(true)
+	dest = nil
+	require.NoError(t, BindJSONBody(strings.NewReader(`{"id":1234567890123456789}`), &dest))
+	assert.Equal(t, json.Number("1234567890123456789"), dest["id"])
+
+	var m AdditionalPropertiesMap[struct {
+		Name string `json:"name"`
+	}, interface{}]
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"Rex","id":1234567890123456789}`), &m))
+	assert.Equal(t, json.Number("1234567890123456789"), m.AdditionalProperties["id"])
+}
+
+func TestBindJSONBody_UnknownFields(t *testing.T) {
+	type Owner struct {
+		Name string `json:"name"`
+	}
+	var dest struct {
+		Name   string            `json:"name"`
+		Owner  *Owner            `json:"owner"`
+		Owners []Owner           `json:"owners"`
+		Tags   map[string]Owner  `json:"tags"`
+		Raw    json.RawMessage   `json:"raw"`
+		Extra  map[string]string `json:"extra"`
+	}
+	body := `{"NAME":"rex","owner":{"name":"a","age":3},"owners":[{"name":"b"},{"nick":"c"}],` +
+		`"tags":{"t":{"id":1}},"raw":{"anything":1},"extra":{"k":"v"},"color":"brown"}`
+
+	err := BindJSONBodyWithOptions(strings.NewReader(body), &dest, BindJSONBodyOptions{DisallowUnknownFields: true})
+	assert.Equal(t, &UnknownFieldError{Field: "color"}, err)
+
+	var warnings BindWarnings
+	require.NoError(t, BindJSONBodyWithOptions(strings.NewReader(body), &dest, BindJSONBodyOptions{
+		DisallowUnknownFields: true,
+		Warnings:              &warnings,
+	}))
+	var msgs []string
+	for _, w := range warnings.Warnings() {
+		msgs = append(msgs, w.String())
+	}
+	assert.Equal(t, []string{
+		"body: unknown field 'color'",
+		"body: unknown field 'owner.age'",
+		"body: unknown field 'owners[1].nick'",
+		"body: unknown field 'tags.t.id'",
+	}, msgs)
+
+	// Detection doesn't depend on the decoder of the codec in use.
+	SetJSONCodec(&countingCodec{})
+	defer SetJSONCodec(nil)
+	err = BindJSONBodyWithOptions(strings.NewReader(`{"color":"brown"}`), &dest, BindJSONBodyOptions{DisallowUnknownFields: true})
+	assert.Equal(t, &UnknownFieldError{Field: "color"}, err)
+}
+
+func TestBindJSONBody_TrailingData(t *testing.T) {
+	var dest struct {
+		Name string `json:"name"`
+	}
+	body := `{"name":"rex"} {"name":"max"}`
+	require.NoError(t, BindJSONBody(strings.NewReader(body), &dest))
+	err := BindJSONBodyWithOptions(strings.NewReader(body), &dest, BindJSONBodyOptions{DisallowUnknownFields: true})
+	assert.EqualError(t, err, "unexpected data after JSON body")
+}
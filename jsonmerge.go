@@ -1,6 +1,7 @@
 package runtime
 
 import (
+	"bytes"
 	"encoding/json"
 
 	"github.com/apapsch/go-jsonmerge/v2"
@@ -32,3 +33,80 @@ func JSONMerge(data, patch json.RawMessage) (json.RawMessage, error) {
 	}
 	return merged, nil
 }
+
+// ArrayMergeMode selects how JSONDeepMerge combines arrays found at the same
+// location in both documents.
+type ArrayMergeMode int
+
+const (
+	// ArrayMergeReplace replaces the array in data with the one in patch.
+	ArrayMergeReplace ArrayMergeMode = iota
+	// ArrayMergeAppend appends the elements of the array in patch to the
+	// one in data.
+	ArrayMergeAppend
+)
+
+// JSONDeepMergeOptions configures JSONDeepMerge.
+type JSONDeepMergeOptions struct {
+	Arrays ArrayMergeMode
+}
+
+// JSONDeepMerge merges patch into data like JSONMerge, but recursively: an
+// object in patch is merged into the object at the same location in data,
+// property by property, rather than replacing it, so that nested objects
+// marshaled from a typed struct and from its additionalProperties map don't
+// clobber each other. Arrays are combined according to opts.Arrays, and
+// every other value in patch replaces the one in data. Numbers are kept as
+// written.
+func JSONDeepMerge(data, patch json.RawMessage, opts JSONDeepMergeOptions) (json.RawMessage, error) {
+	if data == nil {
+		data = []byte(`{}`)
+	}
+	if patch == nil {
+		patch = []byte(`{}`)
+	}
+	dataDoc, err := decodeMergeDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	patchDoc, err := decodeMergeDocument(patch)
+	if err != nil {
+		return nil, err
+	}
+	return jsonMarshal(deepMerge(dataDoc, patchDoc, opts))
+}
+
+func decodeMergeDocument(data []byte) (interface{}, error) {
+	var doc interface{}
+	dec := newJSONDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func deepMerge(data, patch interface{}, opts JSONDeepMergeOptions) interface{} {
+	switch p := patch.(type) {
+	case map[string]interface{}:
+		d, ok := data.(map[string]interface{})
+		if !ok {
+			return p
+		}
+		for k, v := range p {
+			if existing, found := d[k]; found {
+				d[k] = deepMerge(existing, v, opts)
+			} else {
+				d[k] = v
+			}
+		}
+		return d
+	case []interface{}:
+		if d, ok := data.([]interface{}); ok && opts.Arrays == ArrayMergeAppend {
+			return append(d, p...)
+		}
+		return p
+	default:
+		return patch
+	}
+}
@@ -78,3 +78,39 @@ func TestJSONMerge(t *testing.T) {
 		})
 	})
 }
+
+func TestJSONDeepMerge(t *testing.T) {
+	t.Run("merges nested objects", func(t *testing.T) {
+		data := `{"owner":{"name":"alex","address":{"city":"Berlin"}},"id":1}`
+		patch := `{"owner":{"address":{"zip":"10115"},"age":30}}`
+		expected := `{"id":1,"owner":{"address":{"city":"Berlin","zip":"10115"},"age":30,"name":"alex"}}`
+
+		actual, err := JSONDeepMerge([]byte(data), []byte(patch), JSONDeepMergeOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(actual))
+	})
+
+	t.Run("replaces arrays by default", func(t *testing.T) {
+		actual, err := JSONDeepMerge([]byte(`{"tags":["a"]}`), []byte(`{"tags":["b"]}`), JSONDeepMergeOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"tags":["b"]}`, string(actual))
+	})
+
+	t.Run("appends arrays", func(t *testing.T) {
+		actual, err := JSONDeepMerge([]byte(`{"tags":["a"]}`), []byte(`{"tags":["b"]}`), JSONDeepMergeOptions{Arrays: ArrayMergeAppend})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"tags":["a","b"]}`, string(actual))
+	})
+
+	t.Run("keeps numbers and nulls", func(t *testing.T) {
+		actual, err := JSONDeepMerge([]byte(`{"id":12345678901234567890}`), []byte(`{"name":null}`), JSONDeepMergeOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"id":12345678901234567890,"name":null}`, string(actual))
+	})
+
+	t.Run("handles nil documents", func(t *testing.T) {
+		actual, err := JSONDeepMerge(nil, nil, JSONDeepMergeOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, `{}`, string(actual))
+	})
+}
@@ -0,0 +1,293 @@
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSONPatchOperation is a single operation of an RFC 6902 JSON Patch
+// document, as sent in application/json-patch+json request bodies.
+type JSONPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// JSONPatchTestFailedError is returned when a "test" operation finds a value
+// other than the expected one. APIs usually answer it with 409 Conflict or
+// 412 Precondition Failed.
+type JSONPatchTestFailedError struct {
+	// Index is the position of the operation in the patch.
+	Index int
+	Path  string
+}
+
+func (e *JSONPatchTestFailedError) Error() string {
+	return fmt.Sprintf("json patch operation %d: test failed at path '%s'", e.Index, e.Path)
+}
+
+// JSONPatchOperationError is returned when an operation can't be applied,
+// for example because its path doesn't exist or it is malformed. It usually
+// maps to 422 Unprocessable Entity.
+type JSONPatchOperationError struct {
+	Index int
+	Op    string
+	Path  string
+	Err   error
+}
+
+func (e *JSONPatchOperationError) Error() string {
+	return fmt.Sprintf("json patch operation %d (%s '%s'): %s", e.Index, e.Op, e.Path, e.Err)
+}
+
+func (e *JSONPatchOperationError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyJSONPatch applies the RFC 6902 JSON Patch document patch to target,
+// which must be a pointer. All of the add, remove, replace, move, copy and
+// test operations are supported. The patch is applied to target's JSON
+// representation and target is only updated when every operation succeeds,
+// so a failed patch leaves it untouched.
+func ApplyJSONPatch(target interface{}, patch []byte) error {
+	var ops []JSONPatchOperation
+	if err := jsonUnmarshal(patch, &ops); err != nil {
+		return fmt.Errorf("error unmarshaling json patch: %w", err)
+	}
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("json patch target must be a non-nil pointer, got %T", target)
+	}
+	doc, err := toJSONDocument(target)
+	if err != nil {
+		return err
+	}
+	for i, op := range ops {
+		doc, err = applyJSONPatchOperation(doc, op)
+		if err != nil {
+			var testFailed *JSONPatchTestFailedError
+			if errors.As(err, &testFailed) {
+				testFailed.Index = i
+				return testFailed
+			}
+			return &JSONPatchOperationError{Index: i, Op: op.Op, Path: op.Path, Err: err}
+		}
+	}
+	data, err := jsonMarshal(doc)
+	if err != nil {
+		return fmt.Errorf("error marshaling json patch result: %w", err)
+	}
+	result := reflect.New(v.Elem().Type())
+	if err := jsonUnmarshal(data, result.Interface()); err != nil {
+		return fmt.Errorf("error applying json patch: %w", err)
+	}
+	v.Elem().Set(result.Elem())
+	return nil
+}
+
+func applyJSONPatchOperation(doc interface{}, op JSONPatchOperation) (interface{}, error) {
+	path, err := parseJSONPointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	value := func() (interface{}, error) {
+		if op.Value == nil {
+			return nil, errors.New("missing value")
+		}
+		var v interface{}
+		err := jsonUnmarshal(op.Value, &v)
+		return v, err
+	}
+
+	switch op.Op {
+	case "add":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		return jsonPointerAdd(doc, path, v)
+	case "remove":
+		doc, _, err := jsonPointerRemove(doc, path)
+		return doc, err
+	case "replace":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		if doc, _, err = jsonPointerRemove(doc, path); err != nil {
+			return nil, err
+		}
+		return jsonPointerAdd(doc, path, v)
+	case "move", "copy":
+		from, err := parseJSONPointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		var v interface{}
+		if op.Op == "move" {
+			if op.Path != op.From && strings.HasPrefix(op.Path, op.From+"/") {
+				return nil, errors.New("can't move a value into one of its children")
+			}
+			doc, v, err = jsonPointerRemove(doc, from)
+		} else {
+			v, err = jsonPointerGet(doc, from)
+			if err == nil {
+				// Copy through JSON so the two locations don't share maps
+				// or slices.
+				v, err = toJSONDocument(v)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		return jsonPointerAdd(doc, path, v)
+	case "test":
+		expected, err := value()
+		if err != nil {
+			return nil, err
+		}
+		actual, err := jsonPointerGet(doc, path)
+		if err != nil || !reflect.DeepEqual(expected, actual) {
+			return nil, &JSONPatchTestFailedError{Path: op.Path}
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unknown operation '%s'", op.Op)
+	}
+}
+
+// parseJSONPointer splits an RFC 6901 JSON Pointer into its unescaped
+// reference tokens.
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer '%s'", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func jsonPointerGet(doc interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch d := doc.(type) {
+		case map[string]interface{}:
+			v, ok := d[token]
+			if !ok {
+				return nil, fmt.Errorf("property '%s' not found", token)
+			}
+			doc = v
+		case []interface{}:
+			i, err := jsonPointerIndex(token, len(d)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = d[i]
+		default:
+			return nil, fmt.Errorf("can't look up '%s' in a scalar", token)
+		}
+	}
+	return doc, nil
+}
+
+// jsonPointerUpdate replaces the container at path[:len(path)-1] with the
+// result of update, which receives the container and the last token.
+func jsonPointerUpdate(doc interface{}, path []string, update func(container interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return update(doc, path[0])
+	}
+	child, err := jsonPointerGet(doc, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	child, err = jsonPointerUpdate(child, path[1:], update)
+	if err != nil {
+		return nil, err
+	}
+	switch d := doc.(type) {
+	case map[string]interface{}:
+		d[path[0]] = child
+	case []interface{}:
+		i, _ := jsonPointerIndex(path[0], len(d)-1)
+		d[i] = child
+	}
+	return doc, nil
+}
+
+func jsonPointerAdd(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return jsonPointerUpdate(doc, path, func(container interface{}, token string) (interface{}, error) {
+		switch c := container.(type) {
+		case map[string]interface{}:
+			c[token] = value
+			return c, nil
+		case []interface{}:
+			if token == "-" {
+				return append(c, value), nil
+			}
+			i, err := jsonPointerIndex(token, len(c))
+			if err != nil {
+				return nil, err
+			}
+			c = append(c, nil)
+			copy(c[i+1:], c[i:])
+			c[i] = value
+			return c, nil
+		default:
+			return nil, fmt.Errorf("can't add '%s' to a scalar", token)
+		}
+	})
+}
+
+func jsonPointerRemove(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, doc, nil
+	}
+	var removed interface{}
+	doc, err := jsonPointerUpdate(doc, path, func(container interface{}, token string) (interface{}, error) {
+		switch c := container.(type) {
+		case map[string]interface{}:
+			v, ok := c[token]
+			if !ok {
+				return nil, fmt.Errorf("property '%s' not found", token)
+			}
+			removed = v
+			delete(c, token)
+			return c, nil
+		case []interface{}:
+			i, err := jsonPointerIndex(token, len(c)-1)
+			if err != nil {
+				return nil, err
+			}
+			removed = c[i]
+			return append(c[:i], c[i+1:]...), nil
+		default:
+			return nil, fmt.Errorf("can't remove '%s' from a scalar", token)
+		}
+	})
+	return doc, removed, err
+}
+
+// jsonPointerIndex parses an array index token, which must be between 0 and
+// maxIndex inclusive.
+func jsonPointerIndex(token string, maxIndex int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index '%s'", token)
+	}
+	if i > maxIndex {
+		return 0, fmt.Errorf("array index %d out of bounds", i)
+	}
+	return i, nil
+}
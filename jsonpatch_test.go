@@ -0,0 +1,68 @@
+package runtime
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type jsonPatchPet struct {
+	Name   string            `json:"name"`
+	Tags   []string          `json:"tags"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Owner  string            `json:"owner,omitempty"`
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	pet := jsonPatchPet{Name: "rex", Tags: []string{"a", "c"}, Labels: map[string]string{"a/b": "1", "m~n": "2"}}
+
+	require.NoError(t, ApplyJSONPatch(&pet, []byte(`[
+		{"op":"test","path":"/name","value":"rex"},
+		{"op":"add","path":"/tags/1","value":"b"},
+		{"op":"add","path":"/tags/-","value":"d"},
+		{"op":"remove","path":"/labels/a~1b"},
+		{"op":"replace","path":"/name","value":"max"},
+		{"op":"copy","from":"/name","path":"/owner"},
+		{"op":"move","from":"/labels/m~0n","path":"/labels/mn"}
+	]`)))
+	assert.Equal(t, jsonPatchPet{
+		Name:   "max",
+		Tags:   []string{"a", "b", "c", "d"},
+		Labels: map[string]string{"mn": "2"},
+		Owner:  "max",
+	}, pet)
+}
+
+func TestApplyJSONPatch_Errors(t *testing.T) {
+	pet := jsonPatchPet{Name: "rex", Tags: []string{"a"}}
+
+	err := ApplyJSONPatch(&pet, []byte(`[
+		{"op":"replace","path":"/name","value":"max"},
+		{"op":"test","path":"/tags/0","value":"b"}
+	]`))
+	var testFailed *JSONPatchTestFailedError
+	require.True(t, errors.As(err, &testFailed))
+	assert.Equal(t, 1, testFailed.Index)
+	assert.Equal(t, "/tags/0", testFailed.Path)
+	// A failed patch leaves the target alone.
+	assert.Equal(t, "rex", pet.Name)
+
+	for _, patch := range []string{
+		`[{"op":"remove","path":"/missing"}]`,
+		`[{"op":"add","path":"/tags/5","value":"x"}]`,
+		`[{"op":"add","path":"/tags/01","value":"x"}]`,
+		`[{"op":"replace","path":"/name"}]`,
+		`[{"op":"move","from":"/labels","path":"/labels/x"}]`,
+		`[{"op":"frobnicate","path":"/name"}]`,
+		`[{"op":"add","path":"name","value":"x"}]`,
+	} {
+		err := ApplyJSONPatch(&pet, []byte(patch))
+		var opErr *JSONPatchOperationError
+		assert.True(t, errors.As(err, &opErr), patch)
+	}
+
+	assert.Error(t, ApplyJSONPatch(&pet, []byte(`{}`)))
+	assert.Error(t, ApplyJSONPatch(pet, []byte(`[]`)))
+}
@@ -0,0 +1,146 @@
+package runtime
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// Limits bounds the input the binders accept, to harden servers against
+// abusive requests. A zero field means no limit. Parameters exceeding a
+// limit fail with a *LimitExceededError, and multipart bodies with too many
+// parts with a *TooManyPartsError, or with too large a part with a
+// *PartTooLargeError.
+type Limits struct {
+	// MaxParamLength is the maximum length in bytes of a parameter's
+	// value, or of each value of a repeated query parameter.
+	MaxParamLength int
+	// MaxArrayItems is the maximum number of items bound to an array
+	// parameter.
+	MaxArrayItems int
+	// MaxDeepObjectDepth is the maximum nesting depth of a deepObject
+	// parameter's keys, so that p[a][b] has a depth of 2.
+	MaxDeepObjectDepth int
+	// MaxDeepObjectKeys is the maximum number of keys of a deepObject
+	// parameter.
+	MaxDeepObjectKeys int
+	// MaxMultipartParts is the maximum number of parts of a multipart body.
+	MaxMultipartParts int
+	// MaxMultipartPartBytes is the maximum size in bytes of a part of a
+	// multipart body read by BindMultipartBody, which holds each bound part
+	// in memory.
+	MaxMultipartPartBytes int64
+}
+
+// DefaultLimits returns the limits in effect until SetLimits is called.
+// They are generous enough for any legitimate request.
+func DefaultLimits() Limits {
+	return Limits{
+		MaxParamLength:     64 << 10,
+		MaxArrayItems:      1000,
+		MaxDeepObjectDepth: 32,
+		MaxDeepObjectKeys:  1000,
+		MaxMultipartParts:  1000,
+		// The memory BindMultipart holds a form in before spilling it to
+		// disk.
+		MaxMultipartPartBytes: 32 << 20,
+	}
+}
+
+var packageLimits atomic.Pointer[Limits]
+
+// SetLimits replaces the package-wide limits, which apply to every call that
+// doesn't pass its own Limits in its options. It panics after
+// FreezeSettings.
+func SetLimits(l Limits) {
+	checkSettingsFrozen("SetLimits")
+	packageLimits.Store(&l)
+}
+
+// GetLimits returns the package-wide limits.
+func GetLimits() Limits {
+	if l := packageLimits.Load(); l != nil {
+		return *l
+	}
+	return DefaultLimits()
+}
+
+// resolveLimits returns the per-call limits l, or the package-wide ones
+// when l is nil.
+func resolveLimits(l *Limits) Limits {
+	if l != nil {
+		return *l
+	}
+	return GetLimits()
+}
+
+// TooManyPartsError is returned when a multipart body has more parts than
+// Limits.MaxMultipartParts allows.
+type TooManyPartsError struct {
+	Limit int
+}
+
+func (e *TooManyPartsError) Error() string {
+	return fmt.Sprintf("multipart body has more than %d parts", e.Limit)
+}
+
+// PartTooLargeError is returned when a part of a multipart body is larger
+// than Limits.MaxMultipartPartBytes allows.
+type PartTooLargeError struct {
+	Name  string
+	Limit int64
+}
+
+func (e *PartTooLargeError) Error() string {
+	return fmt.Sprintf("part '%s' exceeds the limit of %d bytes", e.Name, e.Limit)
+}
+
+// checkParamLength fails parameter values longer than l.MaxParamLength.
+func (l Limits) checkParamLength(paramName string, paramLocation ParamLocation, value string) error {
+	if l.MaxParamLength > 0 && len(value) > l.MaxParamLength {
+		return &LimitExceededError{
+			ParamName: paramName,
+			Location:  paramLocation,
+			Err:       fmt.Errorf("value is longer than %d bytes", l.MaxParamLength),
+		}
+	}
+	return nil
+}
+
+// checkArrayItems fails arrays with more than l.MaxArrayItems items.
+func (l Limits) checkArrayItems(paramName string, paramLocation ParamLocation, n int) error {
+	if l.MaxArrayItems > 0 && n > l.MaxArrayItems {
+		return &LimitExceededError{
+			ParamName: paramName,
+			Location:  paramLocation,
+			Err:       fmt.Errorf("more than %d array items", l.MaxArrayItems),
+		}
+	}
+	return nil
+}
+
+// checkMultipartParts fails the n-th part of a multipart body when it is
+// beyond l.MaxMultipartParts.
+func (l Limits) checkMultipartParts(n int) error {
+	if l.MaxMultipartParts > 0 && n > l.MaxMultipartParts {
+		return &TooManyPartsError{Limit: l.MaxMultipartParts}
+	}
+	return nil
+}
+
+// readMultipartPart reads the part named name, failing as soon as it grows
+// beyond l.MaxMultipartPartBytes rather than once it has been read whole.
+func (l Limits) readMultipartPart(name string, part io.Reader) ([]byte, error) {
+	if l.MaxMultipartPartBytes > 0 {
+		part = io.LimitReader(part, l.MaxMultipartPartBytes+1)
+	}
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(part); err != nil {
+		return nil, err
+	}
+	if l.MaxMultipartPartBytes > 0 && int64(buf.Len()) > l.MaxMultipartPartBytes {
+		return nil, &PartTooLargeError{Name: name, Limit: l.MaxMultipartPartBytes}
+	}
+	return buf.Bytes(), nil
+}
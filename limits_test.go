@@ -0,0 +1,135 @@
+package runtime
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oapi-codegen/runtime/types"
+)
+
+func TestLimits(t *testing.T) {
+	assert.Equal(t, DefaultLimits(), GetLimits())
+
+	SetLimits(Limits{MaxParamLength: 4, MaxArrayItems: 2, MaxDeepObjectDepth: 2, MaxDeepObjectKeys: 2, MaxMultipartParts: 1})
+	defer SetLimits(DefaultLimits())
+
+	var limitErr *LimitExceededError
+
+	var id string
+	err := BindStyledParameterWithOptions("simple", "id", "12345", &id, BindStyledParameterOptions{ParamLocation: ParamLocationPath})
+	require.True(t, errors.As(err, &limitErr))
+	assert.Equal(t, "id", limitErr.ParamName)
+	assert.Equal(t, ParamLocationPath, limitErr.Location)
+
+	err = BindStyledParameterWithOptions("simple", "id", "12345", &id, BindStyledParameterOptions{
+		ParamLocation: ParamLocationPath,
+		Limits:        &Limits{},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "12345", id)
+
+	var ids []int
+	err = BindStyledParameterWithOptions("simple", "ids", "1,2,3", &ids, BindStyledParameterOptions{ParamLocation: ParamLocationPath})
+	assert.True(t, errors.As(err, &limitErr))
+
+	err = BindQueryParameter("form", true, true, "ids", url.Values{"ids": {"1", "2", "3"}}, &ids)
+	assert.True(t, errors.As(err, &limitErr))
+	err = BindQueryParameter("form", false, true, "ids", url.Values{"ids": {"1,2,3"}}, &ids)
+	assert.True(t, errors.As(err, &limitErr))
+	require.NoError(t, BindQueryParameter("form", true, true, "ids", url.Values{"ids": {"1", "2"}}, &ids))
+
+	var obj map[string]string
+	err = UnmarshalDeepObject(&obj, "p", url.Values{"p[a][b][c]": {"1"}})
+	assert.True(t, errors.As(err, &limitErr))
+	err = UnmarshalDeepObject(&obj, "p", url.Values{"p[a]": {"1"}, "p[b]": {"2"}, "p[c]": {"3"}})
+	assert.True(t, errors.As(err, &limitErr))
+	err = BindQueryParameterWithOptions("deepObject", "p", url.Values{"p[a]": {"1"}, "p[b]": {"2"}, "p[c]": {"3"}}, &obj, BindQueryParameterOptions{
+		Explode: true,
+		Limits:  &Limits{},
+	})
+	assert.NoError(t, err)
+
+	var tooManyParts *TooManyPartsError
+	body, contentType := newTestMultipartBody(t)
+	it, err := NewMultipartIterator(body, contentType)
+	require.NoError(t, err)
+	assert.True(t, it.Next())
+	assert.False(t, it.Next())
+	assert.True(t, errors.As(it.Err(), &tooManyParts))
+
+	body, contentType = newTestMultipartBody(t)
+	it, err = NewMultipartIterator(body, contentType)
+	require.NoError(t, err)
+	it.SetMaxParts(0)
+	assert.True(t, it.Next())
+	assert.True(t, it.Next())
+	assert.NoError(t, it.Err())
+
+	body, contentType = newTestMultipartBody(t)
+	_, boundary, _ := strings.Cut(contentType, "boundary=")
+	reader := multipart.NewReader(body, boundary)
+	var dest struct {
+		Name string `json:"name"`
+	}
+	err = BindMultipartBody(reader, &dest)
+	assert.True(t, errors.As(err, &tooManyParts))
+}
+
+func TestLimits_MultipartRelatedPartBytes(t *testing.T) {
+	SetLimits(Limits{MaxMultipartPartBytes: 16})
+	defer SetLimits(DefaultLimits())
+
+	var buf bytes.Buffer
+	contentType, err := WriteMultipartRelated(&buf, map[string]string{}, MultipartPart{
+		ContentID:   "photo",
+		ContentType: "image/png",
+		Body:        strings.NewReader(strings.Repeat("a", 17)),
+	})
+	require.NoError(t, err)
+	var root map[string]string
+	_, err = ReadMultipartRelated(&buf, contentType, &root)
+	var tooLarge *PartTooLargeError
+	require.True(t, errors.As(err, &tooLarge))
+	assert.Equal(t, "photo", tooLarge.Name)
+}
+
+func TestLimits_MultipartPartBytes(t *testing.T) {
+	SetLimits(Limits{MaxMultipartPartBytes: 2})
+	defer SetLimits(DefaultLimits())
+
+	bind := func(dest interface{}) error {
+		body, contentType := newTestMultipartBody(t)
+		_, boundary, _ := strings.Cut(contentType, "boundary=")
+		return BindMultipartBody(multipart.NewReader(body, boundary), dest)
+	}
+
+	// Parts without a field aren't read, so their size doesn't matter.
+	var other struct {
+		Other string `json:"other"`
+	}
+	require.NoError(t, bind(&other))
+
+	var dest struct {
+		Photo types.File `json:"photo"`
+	}
+	var tooLarge *PartTooLargeError
+	require.True(t, errors.As(bind(&dest), &tooLarge))
+	assert.Equal(t, "photo", tooLarge.Name)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, NewProblemFromError(tooLarge).Status)
+
+	// Limits passed in the options take the place of the package-wide ones.
+	body, contentType := newTestMultipartBody(t)
+	_, boundary, _ := strings.Cut(contentType, "boundary=")
+	require.NoError(t, BindMultipartBodyWithOptions(multipart.NewReader(body, boundary), &dest, BindMultipartBodyOptions{
+		Limits: &Limits{MaxMultipartPartBytes: 1 << 10},
+	}))
+	assert.NotEmpty(t, dest.Photo.Filename())
+}
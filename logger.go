@@ -0,0 +1,25 @@
+//go:build go1.21
+
+package runtime
+
+import (
+	"context"
+	"log/slog"
+)
+
+type loggerContextKey struct{}
+
+// WithLogger returns a copy of ctx carrying logger, for handlers to log
+// through with the request's attributes already attached.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// LoggerFrom returns the logger stored in ctx by WithLogger, or
+// slog.Default() if there is none, so that callers can always log.
+func LoggerFrom(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerContextKey{}).(*slog.Logger); ok && logger != nil {
+		return logger
+	}
+	return slog.Default()
+}
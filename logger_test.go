@@ -0,0 +1,24 @@
+//go:build go1.21
+
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerFrom(t *testing.T) {
+	assert.Same(t, slog.Default(), LoggerFrom(context.Background()))
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil)).With("operation_id", "getPet")
+	ctx := WithLogger(context.Background(), logger)
+	assert.Same(t, logger, LoggerFrom(ctx))
+
+	LoggerFrom(ctx).Info("found")
+	assert.Contains(t, buf.String(), "operation_id=getPet")
+}
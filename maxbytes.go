@@ -0,0 +1,102 @@
+package runtime
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// MaxBytesBody limits the body of the server request r to maxBytes, using
+// http.MaxBytesReader so that the server closes the connection instead of
+// reading an oversized body to the end. Reading past the limit fails with a
+// *BodyTooLargeError, which error handlers can map to 413 Content Too Large.
+// A maxBytes of zero or less leaves the body unlimited.
+func MaxBytesBody(w http.ResponseWriter, r *http.Request, maxBytes int64) io.ReadCloser {
+	if maxBytes <= 0 {
+		return r.Body
+	}
+	if r.ContentLength > maxBytes {
+		return tooLargeBody{limit: maxBytes, Closer: r.Body}
+	}
+	return &maxBytesBody{ReadCloser: http.MaxBytesReader(w, r.Body, maxBytes)}
+}
+
+type maxBytesBody struct {
+	io.ReadCloser
+}
+
+func (b *maxBytesBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		err = &BodyTooLargeError{Limit: maxBytesErr.Limit}
+	}
+	return n, err
+}
+
+// tooLargeBody fails straight away for bodies whose Content-Length is over
+// the limit.
+type tooLargeBody struct {
+	io.Closer
+	limit int64
+}
+
+func (b tooLargeBody) Read([]byte) (int, error) {
+	return 0, &BodyTooLargeError{Limit: b.limit}
+}
+
+// BodyLimitRegistry holds request body size limits per operation ID, so
+// that uploads can be allowed larger bodies than the rest of an API. The
+// zero value is an empty registry without a default limit, and a
+// BodyLimitRegistry is safe for concurrent use: lookups read a snapshot
+// without locking, and changes publish a new one.
+type BodyLimitRegistry struct {
+	cow copyOnWrite[bodyLimitState]
+}
+
+type bodyLimitState struct {
+	defaultLimit int64
+	operations   map[string]int64
+}
+
+// SetDefaultLimit sets the limit for operations without one of their own.
+// It panics after Freeze.
+func (r *BodyLimitRegistry) SetDefaultLimit(maxBytes int64) {
+	r.cow.update("BodyLimitRegistry", func(s *bodyLimitState) {
+		s.defaultLimit = maxBytes
+	})
+}
+
+// SetOperationLimit sets the limit for the operation with the given ID. A
+// limit of zero or less makes the operation's bodies unlimited. It panics
+// after Freeze.
+func (r *BodyLimitRegistry) SetOperationLimit(operationID string, maxBytes int64) {
+	r.cow.update("BodyLimitRegistry", func(s *bodyLimitState) {
+		s.operations = cloneMap(s.operations)
+		s.operations[operationID] = maxBytes
+	})
+}
+
+// Freeze makes the registry read-only, so that changes made after startup,
+// which would likely be mistakes, panic rather than take effect.
+func (r *BodyLimitRegistry) Freeze() {
+	r.cow.freeze()
+}
+
+// Limit returns the body size limit for the given operation.
+func (r *BodyLimitRegistry) Limit(operationID string) int64 {
+	s := r.cow.load()
+	if s == nil {
+		return 0
+	}
+	if limit, ok := s.operations[operationID]; ok {
+		return limit
+	}
+	return s.defaultLimit
+}
+
+// MaxBytesBody limits the body of r to the limit registered for operationID,
+// as the package level MaxBytesBody does.
+func (r *BodyLimitRegistry) MaxBytesBody(w http.ResponseWriter, req *http.Request, operationID string) io.ReadCloser {
+	return MaxBytesBody(w, req, r.Limit(operationID))
+}
@@ -0,0 +1,58 @@
+package runtime
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxBytesBody(t *testing.T) {
+	newRequest := func(body string, contentLength int64) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/pets", strings.NewReader(body))
+		r.ContentLength = contentLength
+		return r
+	}
+
+	data, err := io.ReadAll(MaxBytesBody(httptest.NewRecorder(), newRequest("12345", 5), 5))
+	require.NoError(t, err)
+	assert.Equal(t, "12345", string(data))
+
+	var tooLarge *BodyTooLargeError
+	_, err = io.ReadAll(MaxBytesBody(httptest.NewRecorder(), newRequest("123456", -1), 5))
+	require.True(t, errors.As(err, &tooLarge))
+	assert.Equal(t, int64(5), tooLarge.Limit)
+
+	_, err = io.ReadAll(MaxBytesBody(httptest.NewRecorder(), newRequest("123456", 6), 5))
+	assert.True(t, errors.As(err, &tooLarge))
+
+	data, err = io.ReadAll(MaxBytesBody(httptest.NewRecorder(), newRequest("123456", 6), 0))
+	require.NoError(t, err)
+	assert.Equal(t, "123456", string(data))
+}
+
+func TestBodyLimitRegistry(t *testing.T) {
+	var registry BodyLimitRegistry
+	assert.Equal(t, int64(0), registry.Limit("createPet"))
+
+	registry.SetDefaultLimit(1 << 10)
+	registry.SetOperationLimit("uploadPhoto", 1<<20)
+	assert.Equal(t, int64(1<<10), registry.Limit("createPet"))
+	assert.Equal(t, int64(1<<20), registry.Limit("uploadPhoto"))
+
+	body := strings.Repeat("a", 2<<10)
+	r := httptest.NewRequest(http.MethodPost, "/pets", strings.NewReader(body))
+	_, err := io.ReadAll(registry.MaxBytesBody(httptest.NewRecorder(), r, "createPet"))
+	var tooLarge *BodyTooLargeError
+	assert.True(t, errors.As(err, &tooLarge))
+
+	r = httptest.NewRequest(http.MethodPost, "/photos", strings.NewReader(body))
+	data, err := io.ReadAll(registry.MaxBytesBody(httptest.NewRecorder(), r, "uploadPhoto"))
+	require.NoError(t, err)
+	assert.Len(t, data, len(body))
+}
@@ -0,0 +1,126 @@
+package runtime
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ApplyMergePatch applies an RFC 7386 JSON merge patch to target, which must
+// be a pointer. Properties set to null in the patch are removed from target,
+// properties absent from the patch are left untouched, and nested objects are
+// merged recursively. Arrays and other values are replaced as a whole.
+//
+// The patch is applied to target's JSON representation, so target's json
+// struct tags and custom (un)marshalers are honored. A field whose property
+// is removed is reset to its zero value.
+func ApplyMergePatch(target interface{}, patch []byte) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("merge patch target must be a non-nil pointer, got %T", target)
+	}
+	data, err := jsonMarshal(target)
+	if err != nil {
+		return fmt.Errorf("error marshaling merge patch target: %w", err)
+	}
+	var doc, patchDoc interface{}
+	if err := jsonUnmarshal(data, &doc); err != nil {
+		return fmt.Errorf("error unmarshaling merge patch target: %w", err)
+	}
+	if err := jsonUnmarshal(patch, &patchDoc); err != nil {
+		return fmt.Errorf("error unmarshaling merge patch: %w", err)
+	}
+	merged, err := jsonMarshal(mergePatch(doc, patchDoc))
+	if err != nil {
+		return fmt.Errorf("error marshaling merge patch result: %w", err)
+	}
+	// Start from the zero value, so that removed properties don't survive
+	// in target.
+	result := reflect.New(v.Elem().Type())
+	if err := jsonUnmarshal(merged, result.Interface()); err != nil {
+		return fmt.Errorf("error applying merge patch: %w", err)
+	}
+	v.Elem().Set(result.Elem())
+	return nil
+}
+
+// mergePatch implements the MergePatch function of RFC 7386, section 2.
+func mergePatch(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = make(map[string]interface{}, len(patchObj))
+	}
+	for k, v := range patchObj {
+		if v == nil {
+			delete(targetObj, k)
+			continue
+		}
+		targetObj[k] = mergePatch(targetObj[k], v)
+	}
+	return targetObj
+}
+
+// BuildMergePatch returns the RFC 7386 JSON merge patch which turns from into
+// to, comparing their JSON representations. Removed properties are set to
+// null, changed ones to their new value, and unchanged ones are left out.
+//
+// Merge patches can't tell a property set to null apart from a removed one,
+// so properties which are null in to are removed when the patch is applied.
+func BuildMergePatch(from, to interface{}) ([]byte, error) {
+	fromDoc, err := toJSONDocument(from)
+	if err != nil {
+		return nil, err
+	}
+	toDoc, err := toJSONDocument(to)
+	if err != nil {
+		return nil, err
+	}
+	return jsonMarshal(diffMergePatch(fromDoc, toDoc))
+}
+
+func diffMergePatch(from, to interface{}) interface{} {
+	fromObj, fromOK := from.(map[string]interface{})
+	toObj, toOK := to.(map[string]interface{})
+	if !fromOK || !toOK {
+		return to
+	}
+	patch := make(map[string]interface{})
+	for k, fromValue := range fromObj {
+		toValue, ok := toObj[k]
+		if !ok {
+			patch[k] = nil
+			continue
+		}
+		if reflect.DeepEqual(fromValue, toValue) {
+			continue
+		}
+		_, fromIsObj := fromValue.(map[string]interface{})
+		_, toIsObj := toValue.(map[string]interface{})
+		if fromIsObj && toIsObj {
+			patch[k] = diffMergePatch(fromValue, toValue)
+		} else {
+			patch[k] = toValue
+		}
+	}
+	for k, toValue := range toObj {
+		if _, ok := fromObj[k]; !ok {
+			patch[k] = toValue
+		}
+	}
+	return patch
+}
+
+func toJSONDocument(v interface{}) (interface{}, error) {
+	data, err := jsonMarshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling %T: %w", v, err)
+	}
+	var doc interface{}
+	if err := jsonUnmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("error unmarshaling %T: %w", v, err)
+	}
+	return doc, nil
+}
@@ -0,0 +1,56 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mergePatchPet struct {
+	Name     string            `json:"name"`
+	Nickname *string           `json:"nickname,omitempty"`
+	Tags     []string          `json:"tags,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	nickname := "fifi"
+	pet := mergePatchPet{
+		Name:     "rex",
+		Nickname: &nickname,
+		Tags:     []string{"a", "b"},
+		Labels:   map[string]string{"app": "pets", "tier": "gold"},
+	}
+
+	require.NoError(t, ApplyMergePatch(&pet, []byte(`{"nickname":null,"tags":["c"],"labels":{"tier":null,"env":"prod"}}`)))
+	assert.Equal(t, mergePatchPet{
+		Name:   "rex",
+		Tags:   []string{"c"},
+		Labels: map[string]string{"app": "pets", "env": "prod"},
+	}, pet)
+
+	assert.Error(t, ApplyMergePatch(pet, []byte(`{}`)))
+	assert.Error(t, ApplyMergePatch(&pet, []byte(`{`)))
+	assert.Error(t, ApplyMergePatch(&pet, []byte(`{"name":1}`)))
+}
+
+func TestMergePatch_RFC7386Examples(t *testing.T) {
+	var target map[string]interface{}
+	require.NoError(t, ApplyMergePatch(&target, []byte(`{"a":"b"}`)))
+	require.NoError(t, ApplyMergePatch(&target, []byte(`{"a":{"b":"c","d":null},"e":[1]}`)))
+	assert.Equal(t, map[string]interface{}{"a": map[string]interface{}{"b": "c"}, "e": []interface{}{float64(1)}}, target)
+}
+
+func TestBuildMergePatch(t *testing.T) {
+	nickname := "fifi"
+	from := mergePatchPet{Name: "rex", Nickname: &nickname, Labels: map[string]string{"app": "pets", "tier": "gold"}}
+	to := mergePatchPet{Name: "rex", Tags: []string{"c"}, Labels: map[string]string{"app": "pets", "env": "prod"}}
+
+	patch, err := BuildMergePatch(from, to)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"nickname":null,"tags":["c"],"labels":{"tier":null,"env":"prod"}}`, string(patch))
+
+	require.NoError(t, ApplyMergePatch(&from, patch))
+	assert.Equal(t, to, from)
+}
@@ -0,0 +1,114 @@
+package runtime
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// ErrNoMockResponse is returned by MockTransport for requests to operations
+// without a registered response.
+var ErrNoMockResponse = errors.New("no mock response registered")
+
+// MockResponse is a canned response which MockTransport serves.
+type MockResponse struct {
+	// StatusCode defaults to 200.
+	StatusCode int
+	Header     http.Header
+	// Body is sent as it is.
+	Body []byte
+	// Value, when Body is nil, is marshaled into a JSON body, which is
+	// sent with a Content-Type of application/json unless Header has one.
+	Value interface{}
+}
+
+// MockTransport is an http.RoundTripper which serves canned responses for
+// testing code built on a generated client without running a server.
+// Responses are registered by operation ID, which is resolved from the
+// request context set by generated clients, so tests needn't match URLs.
+// The zero value is ready to use, and it is safe for concurrent use.
+type MockTransport struct {
+	mu        sync.Mutex
+	responses map[string][]MockResponse
+	calls     map[string][]*http.Request
+}
+
+// Register queues resp as the response to the next request for the
+// operation operationID. When several responses are queued for an
+// operation they are served in order, and the last one keeps being served
+// once the others have been.
+func (t *MockTransport) Register(operationID string, resp MockResponse) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.responses == nil {
+		t.responses = make(map[string][]MockResponse)
+	}
+	t.responses[operationID] = append(t.responses[operationID], resp)
+}
+
+// Calls returns the requests made for the operation operationID so far.
+func (t *MockTransport) Calls(operationID string) []*http.Request {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*http.Request(nil), t.calls[operationID]...)
+}
+
+func (t *MockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	operationID := GetOperationID(req.Context())
+	resp, ok := t.next(operationID, req)
+	if !ok {
+		return nil, fmt.Errorf("operation '%s': %w", operationID, ErrNoMockResponse)
+	}
+
+	header := resp.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	body := resp.Body
+	if body == nil && resp.Value != nil {
+		var err error
+		if body, err = jsonMarshal(resp.Value); err != nil {
+			return nil, fmt.Errorf("error marshaling mock response for operation '%s': %w", operationID, err)
+		}
+		if header.Get("Content-Type") == "" {
+			header.Set("Content-Type", jsonContentType)
+		}
+	}
+	status := resp.StatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// next records req and returns the response to serve it with.
+func (t *MockTransport) next(operationID string, req *http.Request) (MockResponse, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.calls == nil {
+		t.calls = make(map[string][]*http.Request)
+	}
+	t.calls[operationID] = append(t.calls[operationID], req)
+	queue := t.responses[operationID]
+	if len(queue) == 0 {
+		return MockResponse{}, false
+	}
+	resp := queue[0]
+	if len(queue) > 1 {
+		t.responses[operationID] = queue[1:]
+	}
+	return resp, true
+}
@@ -0,0 +1,50 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockTransport(t *testing.T) {
+	type Pet struct {
+		Name string `json:"name"`
+	}
+	var transport MockTransport
+	transport.Register("getPet", MockResponse{Value: Pet{Name: "Rex"}})
+	transport.Register("getPet", MockResponse{StatusCode: http.StatusNotFound, Body: []byte("gone")})
+	client := &http.Client{Transport: &transport}
+
+	get := func(operationID string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(WithOperationID(context.Background(), operationID), http.MethodGet, "http://api.example.com/pets/1", nil)
+		require.NoError(t, err)
+		return client.Do(req)
+	}
+
+	resp, err := get("getPet")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	var pet Pet
+	require.NoError(t, BindResponse(resp, &pet))
+	assert.Equal(t, "Rex", pet.Name)
+
+	// The last response keeps being served.
+	for i := 0; i < 2; i++ {
+		resp, err = get("getPet")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		var body []byte
+		require.NoError(t, BindResponse(resp, &body))
+		assert.Equal(t, "gone", string(body))
+	}
+	assert.Len(t, transport.Calls("getPet"), 3)
+
+	_, err = get("deletePet")
+	assert.True(t, errors.Is(err, ErrNoMockResponse))
+	assert.Len(t, transport.Calls("deletePet"), 1)
+}
@@ -0,0 +1,159 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"reflect"
+	"strings"
+
+	"github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	textPlainContentType   = "text/plain"
+	octetStreamContentType = "application/octet-stream"
+)
+
+// EncodeMultipartBody writes the properties of the struct v to w as the
+// parts of a multipart/form-data body, following the OpenAPI encoding object
+// for each property in encodings, keyed by JSON name.
+//
+// Without an encoding, the part's content type follows the property type, as
+// the specification defines: primitives are sent as text/plain, types.File
+// and []byte as application/octet-stream, and objects as application/json.
+// Arrays are sent as one part per element. An encoding may override the
+// content type, add Headers to the part, or set a Style, which sends a
+// primitive or array property as a single styled text part.
+//
+// Nil pointers and properties tagged omitempty holding their zero value are
+// left out. The caller is responsible for closing w.
+func EncodeMultipartBody(w *multipart.Writer, v interface{}, encodings map[string]RequestBodyEncoding) error {
+	ptrVal := reflect.Indirect(reflect.ValueOf(v))
+	if ptrVal.Kind() != reflect.Struct {
+		return errors.New("multipart body should be a struct")
+	}
+	tValue := ptrVal.Type()
+
+	for i := 0; i < tValue.NumField(); i++ {
+		field := ptrVal.Field(i)
+		tag := tValue.Field(i).Tag.Get(tagName)
+		if !field.CanInterface() || tag == "-" {
+			continue
+		}
+		omitEmpty := strings.HasSuffix(tag, ",omitempty")
+		if (omitEmpty && field.IsZero()) || (field.Kind() == reflect.Ptr && field.IsNil()) {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = tValue.Field(i).Name
+		}
+		if err := encodeMultipartProperty(w, name, reflect.Indirect(field), encodings[name]); err != nil {
+			return fmt.Errorf("error encoding property '%s': %w", name, err)
+		}
+	}
+	return nil
+}
+
+func encodeMultipartProperty(w *multipart.Writer, name string, v reflect.Value, encoding RequestBodyEncoding) error {
+	isArray := v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8
+	mediaType, _, _ := mime.ParseMediaType(encoding.ContentType)
+	isJSON := isJSONMediaType(mediaType)
+	if encoding.Style != "" && !isJSON {
+		explode := encoding.Explode != nil && *encoding.Explode
+		styled, err := StyleParamWithLocation(encoding.Style, explode, name, ParamLocationUndefined, v.Interface())
+		if err != nil {
+			return err
+		}
+		// Form style prefixes the value with the name, which the part's
+		// Content-Disposition already carries.
+		styled = strings.TrimPrefix(styled, name+"=")
+		return writeMultipartPart(w, name, "", contentTypeOr(encoding.ContentType, textPlainContentType), encoding.Headers, []byte(styled))
+	}
+	if isArray && !isJSON {
+		for i := 0; i < v.Len(); i++ {
+			if err := encodeMultipartProperty(w, name, reflect.Indirect(v.Index(i)), encoding); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch {
+	case v.Type() == fileType:
+		file := v.Interface().(types.File)
+		data, err := file.Bytes()
+		if err != nil {
+			return err
+		}
+		filename := file.Filename()
+		if filename == "" {
+			filename = name
+		}
+		return writeMultipartPart(w, name, filename, contentTypeOr(encoding.ContentType, octetStreamContentType), encoding.Headers, data)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 && !isJSON:
+		return writeMultipartPart(w, name, name, contentTypeOr(encoding.ContentType, octetStreamContentType), encoding.Headers, v.Bytes())
+	case isJSON || isMultipartObject(v):
+		data, err := jsonMarshal(v.Interface())
+		if err != nil {
+			return err
+		}
+		return writeMultipartPart(w, name, "", contentTypeOr(encoding.ContentType, jsonContentType), encoding.Headers, data)
+	default:
+		text, err := StyleParamWithLocation("simple", false, name, ParamLocationUndefined, v.Interface())
+		if err != nil {
+			return err
+		}
+		return writeMultipartPart(w, name, "", contentTypeOr(encoding.ContentType, textPlainContentType), encoding.Headers, []byte(text))
+	}
+}
+
+// isMultipartObject reports whether v is sent as JSON by default, which is
+// the case for objects other than the types which format as strings.
+func isMultipartObject(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Map, reflect.Interface:
+		return true
+	case reflect.Struct:
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		binder, _, _ := indirect(ptr.Interface())
+		return binder == nil
+	}
+	return false
+}
+
+func writeMultipartPart(w *multipart.Writer, name, filename, contentType string, headers map[string]string, data []byte) error {
+	header := make(textproto.MIMEHeader)
+	disposition := fmt.Sprintf(`form-data; name="%s"`, escapeQuotes(name))
+	if filename != "" {
+		disposition += fmt.Sprintf(`; filename="%s"`, escapeQuotes(filename))
+	}
+	header.Set("Content-Disposition", disposition)
+	header.Set("Content-Type", contentType)
+	for k, v := range headers {
+		header.Set(k, v)
+	}
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	_, err = part.Write(data)
+	return err
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
+func contentTypeOr(contentType, fallback string) string {
+	if contentType != "" {
+		return contentType
+	}
+	return fallback
+}
@@ -0,0 +1,138 @@
+package runtime
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oapi-codegen/runtime/types"
+)
+
+func TestEncodeMultipartBody(t *testing.T) {
+	type Meta struct {
+		Owner string `json:"owner"`
+	}
+	type Upload struct {
+		Name     string       `json:"name"`
+		Count    *int         `json:"count,omitempty"`
+		Born     types.Date   `json:"born"`
+		Tags     []string     `json:"tags"`
+		Ids      []int        `json:"ids"`
+		Meta     Meta         `json:"meta"`
+		Avatar   types.File   `json:"avatar"`
+		Pictures []types.File `json:"pictures"`
+		Raw      []byte       `json:"raw"`
+		Note     string       `json:"note,omitempty"`
+	}
+	var avatar, picture types.File
+	avatar.InitFromBytes([]byte("png"), "rex.png")
+	picture.InitFromBytes([]byte("jpg"), "rex.jpg")
+	upload := Upload{
+		Name:     "rex",
+		Born:     types.Date{Time: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+		Tags:     []string{"a", "b"},
+		Ids:      []int{1, 2},
+		Meta:     Meta{Owner: "alice"},
+		Avatar:   avatar,
+		Pictures: []types.File{picture},
+		Raw:      []byte{0, 1},
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	require.NoError(t, EncodeMultipartBody(w, &upload, map[string]RequestBodyEncoding{
+		"ids":    {Style: "form"},
+		"avatar": {ContentType: "image/png", Headers: map[string]string{"X-Rate-Limit": "10"}},
+	}))
+	require.NoError(t, w.Close())
+
+	type part struct {
+		name, filename, contentType, body string
+	}
+	var parts []part
+	reader := multipart.NewReader(bytes.NewReader(buf.Bytes()), w.Boundary())
+	for {
+		p, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(p)
+		require.NoError(t, err)
+		if p.FormName() == "avatar" {
+			assert.Equal(t, "10", p.Header.Get("X-Rate-Limit"))
+		}
+		parts = append(parts, part{p.FormName(), p.FileName(), p.Header.Get("Content-Type"), string(data)})
+	}
+	assert.Equal(t, []part{
+		{"name", "", "text/plain", "rex"},
+		{"born", "", "text/plain", "2020-01-02"},
+		{"tags", "", "text/plain", "a"},
+		{"tags", "", "text/plain", "b"},
+		{"ids", "", "text/plain", "1,2"},
+		{"meta", "", "application/json", `{"owner":"alice"}`},
+		{"avatar", "rex.png", "image/png", "png"},
+		{"pictures", "rex.jpg", "application/octet-stream", "jpg"},
+		{"raw", "raw", "application/octet-stream", "\x00\x01"},
+	}, parts)
+
+	t.Run("round trip", func(t *testing.T) {
+		// Styled properties need their encoding to be bound again, so leave
+		// them out.
+		var decoded struct {
+			Name   string     `json:"name"`
+			Born   types.Date `json:"born"`
+			Tags   []string   `json:"tags"`
+			Meta   Meta       `json:"meta"`
+			Avatar types.File `json:"avatar"`
+			Raw    []byte     `json:"raw"`
+		}
+		require.NoError(t, BindMultipartBody(multipart.NewReader(bytes.NewReader(buf.Bytes()), w.Boundary()), &decoded))
+		assert.Equal(t, upload.Name, decoded.Name)
+		assert.Equal(t, upload.Born, decoded.Born)
+		assert.Equal(t, upload.Tags, decoded.Tags)
+		assert.Equal(t, upload.Meta, decoded.Meta)
+		assert.Equal(t, "rex.png", decoded.Avatar.Filename())
+		assert.Equal(t, upload.Raw, decoded.Raw)
+	})
+}
+
+func TestEncodeMultipartBody_JSONMediaTypes(t *testing.T) {
+	body := struct {
+		Tags  []string `json:"tags"`
+		Codes []string `json:"codes"`
+	}{Tags: []string{"a", "b"}, Codes: []string{"c"}}
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	require.NoError(t, EncodeMultipartBody(w, &body, map[string]RequestBodyEncoding{
+		// Only JSON media types, structured suffixes included, are
+		// marshaled whole.
+		"tags":  {ContentType: "application/vnd.tags+json; charset=utf-8"},
+		"codes": {ContentType: "application/jsonx"},
+	}))
+	require.NoError(t, w.Close())
+
+	var parts []string
+	reader := multipart.NewReader(&buf, w.Boundary())
+	for {
+		p, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(p)
+		require.NoError(t, err)
+		parts = append(parts, p.FormName()+": "+string(data))
+	}
+	assert.Equal(t, []string{`tags: ["a","b"]`, "codes: c"}, parts)
+}
+
+func TestEncodeMultipartBody_NotAStruct(t *testing.T) {
+	w := multipart.NewWriter(io.Discard)
+	assert.Error(t, EncodeMultipartBody(w, "body", nil))
+}
@@ -0,0 +1,102 @@
+package runtime
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+)
+
+// PartHeader describes a part of a multipart body.
+type PartHeader struct {
+	// FormName and FileName come from the part's Content-Disposition, and
+	// are empty for parts which don't have them, as in multipart/mixed.
+	FormName    string
+	FileName    string
+	ContentType string
+	Header      textproto.MIMEHeader
+}
+
+// MultipartIterator steps through the parts of a multipart body one at a
+// time, so that each part can be processed as it arrives, with back-pressure
+// on the client, instead of the whole body being bound at once. This is what
+// uploads too large to hold in memory or on disk need. It works like
+// bufio.Scanner:
+//
+//	for it.Next() {
+//		header, body := it.Part()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type MultipartIterator struct {
+	reader   *multipart.Reader
+	part     *multipart.Part
+	err      error
+	parts    int
+	maxParts int
+}
+
+// NewMultipartIterator returns an iterator over the parts of body, which has
+// the given multipart Content-Type.
+func NewMultipartIterator(body io.Reader, contentType string) (*MultipartIterator, error) {
+	reader, err := newMultipartReader(body, contentType)
+	if err != nil {
+		return nil, err
+	}
+	return &MultipartIterator{reader: reader, maxParts: GetLimits().MaxMultipartParts}, nil
+}
+
+// SetMaxParts overrides Limits.MaxMultipartParts for this iterator. Zero
+// means no limit.
+func (it *MultipartIterator) SetMaxParts(n int) {
+	it.maxParts = n
+}
+
+// Next advances to the next part, discarding whatever is left unread of the
+// current one. It returns false at the end of the body or on error.
+func (it *MultipartIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.part != nil {
+		_ = it.part.Close()
+		it.part = nil
+	}
+	part, err := it.reader.NextRawPart()
+	if err == io.EOF {
+		return false
+	}
+	if err != nil {
+		it.err = fmt.Errorf("error reading multipart body: %w", err)
+		return false
+	}
+	it.parts++
+	if err := (Limits{MaxMultipartParts: it.maxParts}).checkMultipartParts(it.parts); err != nil {
+		_ = part.Close()
+		it.err = err
+		return false
+	}
+	it.part = part
+	return true
+}
+
+// Part returns the current part's header and body. The body is only valid
+// until the next call to Next.
+func (it *MultipartIterator) Part() (PartHeader, io.Reader) {
+	if it.part == nil {
+		return PartHeader{}, nil
+	}
+	return PartHeader{
+		FormName:    it.part.FormName(),
+		FileName:    it.part.FileName(),
+		ContentType: it.part.Header.Get("Content-Type"),
+		Header:      it.part.Header,
+	}, it.part
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *MultipartIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,28 @@
+//go:build go1.23
+
+package runtime
+
+import (
+	"context"
+	"io"
+	"iter"
+)
+
+// All returns an iterator over the remaining parts. Each part's body is only
+// valid during its iteration step. Once ctx is done, the iteration stops and
+// Err returns ctx.Err(). Check Err once the loop is done.
+func (it *MultipartIterator) All(ctx context.Context) iter.Seq2[PartHeader, io.Reader] {
+	return func(yield func(PartHeader, io.Reader) bool) {
+		for {
+			if err := ctx.Err(); err != nil {
+				if it.err == nil {
+					it.err = err
+				}
+				return
+			}
+			if !it.Next() || !yield(it.Part()) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+//go:build go1.23
+
+package runtime
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultipartIterator_All(t *testing.T) {
+	body, contentType := newTestMultipartBody(t)
+	it, err := NewMultipartIterator(body, contentType)
+	require.NoError(t, err)
+
+	var parts []string
+	for header, r := range it.All(context.Background()) {
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		parts = append(parts, header.FormName+"="+string(data))
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []string{"name=rex", "photo=png"}, parts)
+}
+
+func TestMultipartIterator_AllCanceled(t *testing.T) {
+	body, contentType := newTestMultipartBody(t)
+	it, err := NewMultipartIterator(body, contentType)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var names []string
+	for header := range it.All(ctx) {
+		names = append(names, header.FormName)
+		cancel()
+	}
+	assert.Equal(t, []string{"name"}, names)
+	assert.ErrorIs(t, it.Err(), context.Canceled)
+}
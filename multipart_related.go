@@ -0,0 +1,192 @@
+package runtime
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+)
+
+// MultipartPart is a single part of a multipart/mixed or multipart/related
+// body.
+type MultipartPart struct {
+	// ContentID identifies the part within a multipart/related body, so
+	// that the root part can refer to it. It is written and read without
+	// the surrounding angle brackets.
+	ContentID   string
+	ContentType string
+	// Header holds any further headers of the part.
+	Header textproto.MIMEHeader
+	Body   io.Reader
+}
+
+// WriteMultipartMixed writes parts to w as a multipart/mixed body, and
+// returns the Content-Type to send it with, including the boundary.
+func WriteMultipartMixed(w io.Writer, parts ...MultipartPart) (string, error) {
+	mw := multipart.NewWriter(w)
+	for i, part := range parts {
+		if err := writeMultipartPartBody(mw, part); err != nil {
+			return "", fmt.Errorf("error writing part %d: %w", i, err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return "", err
+	}
+	return mime.FormatMediaType("multipart/mixed", map[string]string{"boundary": mw.Boundary()}), nil
+}
+
+// WriteMultipartRelated writes a multipart/related body (RFC 2387) to w,
+// consisting of root serialized as JSON, followed by attachments which the
+// root refers to by their ContentID. It returns the Content-Type to send the
+// body with, whose start parameter identifies the root part.
+func WriteMultipartRelated(w io.Writer, root interface{}, attachments ...MultipartPart) (string, error) {
+	data, err := jsonMarshal(root)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling root part: %w", err)
+	}
+	const rootID = "root"
+	for _, a := range attachments {
+		if a.ContentID == "" || a.ContentID == rootID {
+			return "", fmt.Errorf("attachments need a unique Content-ID other than '%s'", rootID)
+		}
+	}
+
+	mw := multipart.NewWriter(w)
+	parts := append([]MultipartPart{{ContentID: rootID, ContentType: jsonContentType, Body: bytes.NewReader(data)}}, attachments...)
+	for _, part := range parts {
+		if err := writeMultipartPartBody(mw, part); err != nil {
+			return "", fmt.Errorf("error writing part '%s': %w", part.ContentID, err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return "", err
+	}
+	return mime.FormatMediaType("multipart/related", map[string]string{
+		"boundary": mw.Boundary(),
+		"type":     jsonContentType,
+		"start":    "<" + rootID + ">",
+	}), nil
+}
+
+func writeMultipartPartBody(mw *multipart.Writer, part MultipartPart) error {
+	header := make(textproto.MIMEHeader, len(part.Header)+2)
+	for k, v := range part.Header {
+		header[k] = v
+	}
+	header.Set("Content-Type", contentTypeOr(part.ContentType, octetStreamContentType))
+	if part.ContentID != "" {
+		header.Set("Content-ID", "<"+part.ContentID+">")
+	}
+	w, err := mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if part.Body == nil {
+		return nil
+	}
+	_, err = io.Copy(w, part.Body)
+	return err
+}
+
+// ForEachMultipartPart calls fn with each part of a multipart body of any
+// subtype, such as multipart/mixed, in order and without buffering. The part
+// is only valid until fn returns. Returning an error from fn stops the
+// iteration and returns that error.
+func ForEachMultipartPart(body io.Reader, contentType string, fn func(part *multipart.Part) error) error {
+	reader, err := newMultipartReader(body, contentType)
+	if err != nil {
+		return err
+	}
+	limits := GetLimits()
+	for n := 1; ; n++ {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading multipart body: %w", err)
+		}
+		if err := limits.checkMultipartParts(n); err != nil {
+			_ = part.Close()
+			return err
+		}
+		err = fn(part)
+		_ = part.Close()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ReadMultipartRelated reads a multipart/related body written as
+// WriteMultipartRelated does. The root part, identified by the start
+// parameter of contentType or else being the first part, is unmarshaled into
+// root as JSON. The other parts are returned keyed by their Content-ID, with
+// their bodies read into memory so they can be looked up in any order, each
+// within the MaxMultipartPartBytes of the package-wide limits.
+func ReadMultipartRelated(body io.Reader, contentType string, root interface{}) (map[string]MultipartPart, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing content type '%s': %w", contentType, err)
+	}
+	if mediaType != "multipart/related" {
+		return nil, fmt.Errorf("expected multipart/related, got '%s'", mediaType)
+	}
+	start := trimContentID(params["start"])
+
+	limits := GetLimits()
+	attachments := make(map[string]MultipartPart)
+	var rootData []byte
+	foundRoot := false
+	err = ForEachMultipartPart(body, contentType, func(part *multipart.Part) error {
+		id := trimContentID(part.Header.Get("Content-ID"))
+		data, err := limits.readMultipartPart(id, part)
+		if err != nil {
+			return err
+		}
+		if !foundRoot && (start == "" || id == start) {
+			rootData, foundRoot = data, true
+			return nil
+		}
+		attachments[id] = MultipartPart{
+			ContentID:   id,
+			ContentType: part.Header.Get("Content-Type"),
+			Header:      part.Header,
+			Body:        bytes.NewReader(data),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !foundRoot {
+		return nil, errors.New("multipart/related body has no root part")
+	}
+	if err := jsonUnmarshal(rootData, root); err != nil {
+		return nil, fmt.Errorf("error unmarshaling root part: %w", err)
+	}
+	return attachments, nil
+}
+
+func newMultipartReader(body io.Reader, contentType string) (*multipart.Reader, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing content type '%s': %w", contentType, err)
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		return nil, fmt.Errorf("expected a multipart content type, got '%s'", mediaType)
+	}
+	boundary := params["boundary"]
+	if boundary == "" {
+		return nil, errors.New("multipart content type has no boundary")
+	}
+	return multipart.NewReader(body, boundary), nil
+}
+
+func trimContentID(id string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(id), "<"), ">")
+}
@@ -0,0 +1,69 @@
+package runtime
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultipartRelated(t *testing.T) {
+	type Document struct {
+		Title string `json:"title"`
+		Image string `json:"image"`
+	}
+
+	var buf bytes.Buffer
+	contentType, err := WriteMultipartRelated(&buf, Document{Title: "rex", Image: "cid:photo"}, MultipartPart{
+		ContentID:   "photo",
+		ContentType: "image/png",
+		Body:        strings.NewReader("png"),
+	})
+	require.NoError(t, err)
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/related", mediaType)
+	assert.Equal(t, "<root>", params["start"])
+	assert.Equal(t, "application/json", params["type"])
+
+	var doc Document
+	attachments, err := ReadMultipartRelated(&buf, contentType, &doc)
+	require.NoError(t, err)
+	assert.Equal(t, Document{Title: "rex", Image: "cid:photo"}, doc)
+	require.Contains(t, attachments, "photo")
+	assert.Equal(t, "image/png", attachments["photo"].ContentType)
+	data, err := io.ReadAll(attachments["photo"].Body)
+	require.NoError(t, err)
+	assert.Equal(t, "png", string(data))
+
+	_, err = WriteMultipartRelated(&buf, doc, MultipartPart{Body: strings.NewReader("x")})
+	assert.Error(t, err)
+	_, err = ReadMultipartRelated(&buf, "multipart/mixed; boundary=x", &doc)
+	assert.Error(t, err)
+}
+
+func TestMultipartMixed(t *testing.T) {
+	var buf bytes.Buffer
+	contentType, err := WriteMultipartMixed(&buf,
+		MultipartPart{ContentType: "application/json", Body: strings.NewReader(`{"a":1}`)},
+		MultipartPart{Body: strings.NewReader("raw"), Header: map[string][]string{"X-Part": {"2"}}},
+	)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(contentType, "multipart/mixed; boundary="))
+
+	var got []string
+	require.NoError(t, ForEachMultipartPart(&buf, contentType, func(part *multipart.Part) error {
+		data, err := io.ReadAll(part)
+		got = append(got, part.Header.Get("Content-Type")+" "+part.Header.Get("X-Part")+" "+string(data))
+		return err
+	}))
+	assert.Equal(t, []string{`application/json  {"a":1}`, "application/octet-stream 2 raw"}, got)
+
+	assert.Error(t, ForEachMultipartPart(&buf, "application/json", nil))
+	assert.Error(t, ForEachMultipartPart(&buf, "multipart/mixed", nil))
+}
@@ -0,0 +1,80 @@
+package runtime
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// NDJSONDecoder reads a newline delimited JSON (application/x-ndjson) stream
+// one record at a time, so that request or response bodies don't need to be
+// buffered in full.
+type NDJSONDecoder[T any] struct {
+	r    *bufio.Reader
+	line int
+}
+
+// NewNDJSONDecoder returns a decoder reading records of type T from r.
+func NewNDJSONDecoder[T any](r io.Reader) *NDJSONDecoder[T] {
+	return &NDJSONDecoder[T]{r: bufio.NewReader(r)}
+}
+
+// Decode returns the next record in the stream. Blank lines are skipped. At
+// the end of the stream it returns io.EOF.
+func (d *NDJSONDecoder[T]) Decode() (T, error) {
+	var record T
+	for {
+		line, err := d.r.ReadBytes('\n')
+		if len(line) == 0 && err != nil {
+			return record, err
+		}
+		d.line++
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			if err != nil {
+				return record, err
+			}
+			continue
+		}
+		if uerr := jsonUnmarshal(line, &record); uerr != nil {
+			return record, fmt.Errorf("error decoding NDJSON record on line %d: %w", d.line, uerr)
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return record, err
+		}
+		return record, nil
+	}
+}
+
+// NDJSONEncoder writes records of type T as newline delimited JSON. When the
+// underlying writer is an http.Flusher, such as an http.ResponseWriter, each
+// record is flushed as soon as it is written so clients receive it without
+// delay.
+type NDJSONEncoder[T any] struct {
+	w       io.Writer
+	flusher http.Flusher
+}
+
+// NewNDJSONEncoder returns an encoder writing records of type T to w.
+func NewNDJSONEncoder[T any](w io.Writer) *NDJSONEncoder[T] {
+	flusher, _ := w.(http.Flusher)
+	return &NDJSONEncoder[T]{w: w, flusher: flusher}
+}
+
+// Encode writes record on its own line.
+func (e *NDJSONEncoder[T]) Encode(record T) error {
+	data, err := jsonMarshal(record)
+	if err != nil {
+		return fmt.Errorf("error encoding NDJSON record: %w", err)
+	}
+	if _, err := e.w.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	if e.flusher != nil {
+		e.flusher.Flush()
+	}
+	return nil
+}
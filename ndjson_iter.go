@@ -0,0 +1,43 @@
+//go:build go1.23
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+)
+
+// All returns an iterator over the remaining records in the stream. It stops
+// at the end of the stream, or after yielding the first error. Once ctx is
+// done, it yields ctx.Err() as its last error.
+func (d *NDJSONDecoder[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			if err := ctx.Err(); err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			record, err := d.Decode()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if !yield(record, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// EncodeAll writes every record produced by seq, stopping at the first
+// error.
+func (e *NDJSONEncoder[T]) EncodeAll(seq iter.Seq[T]) error {
+	for record := range seq {
+		if err := e.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+//go:build go1.23
+
+package runtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNDJSONIterators(t *testing.T) {
+	var buf strings.Builder
+	enc := NewNDJSONEncoder[ndjsonRecord](&buf)
+	require.NoError(t, enc.EncodeAll(func(yield func(ndjsonRecord) bool) {
+		_ = yield(ndjsonRecord{ID: 1}) && yield(ndjsonRecord{ID: 2})
+	}))
+
+	var ids []int
+	for record, err := range NewNDJSONDecoder[ndjsonRecord](strings.NewReader(buf.String())).All(context.Background()) {
+		require.NoError(t, err)
+		ids = append(ids, record.ID)
+	}
+	assert.Equal(t, []int{1, 2}, ids)
+}
+
+func TestNDJSONDecoder_AllCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dec := NewNDJSONDecoder[ndjsonRecord](strings.NewReader("{\"id\":1}\n{\"id\":2}\n"))
+
+	var ids []int
+	var lastErr error
+	for record, err := range dec.All(ctx) {
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		ids = append(ids, record.ID)
+		cancel()
+	}
+	assert.Equal(t, []int{1}, ids)
+	assert.ErrorIs(t, lastErr, context.Canceled)
+}
@@ -0,0 +1,44 @@
+package runtime
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ndjsonRecord struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestNDJSONDecoder(t *testing.T) {
+	dec := NewNDJSONDecoder[ndjsonRecord](strings.NewReader("{\"id\":1,\"name\":\"a\"}\n\n{\"id\":2,\"name\":\"b\"}"))
+
+	record, err := dec.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, ndjsonRecord{ID: 1, Name: "a"}, record)
+	record, err = dec.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, ndjsonRecord{ID: 2, Name: "b"}, record)
+	_, err = dec.Decode()
+	assert.Equal(t, io.EOF, err)
+
+	dec = NewNDJSONDecoder[ndjsonRecord](strings.NewReader("{\"id\":1}\n{\"id\":\n"))
+	_, err = dec.Decode()
+	require.NoError(t, err)
+	_, err = dec.Decode()
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestNDJSONEncoder(t *testing.T) {
+	w := httptest.NewRecorder()
+	enc := NewNDJSONEncoder[ndjsonRecord](w)
+	require.NoError(t, enc.Encode(ndjsonRecord{ID: 1, Name: "a"}))
+	assert.True(t, w.Flushed)
+	require.NoError(t, enc.Encode(ndjsonRecord{ID: 2, Name: "b"}))
+	assert.Equal(t, "{\"id\":1,\"name\":\"a\"}\n{\"id\":2,\"name\":\"b\"}\n", w.Body.String())
+}
@@ -0,0 +1,162 @@
+package runtime
+
+import (
+	"errors"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// ErrNotAcceptable is returned by Negotiate when none of the offered media
+// types is acceptable to the client. Servers usually answer it with 406 Not
+// Acceptable.
+var ErrNotAcceptable = errors.New("none of the offered media types is acceptable")
+
+type acceptRange struct {
+	mediaType string
+	params    map[string]string
+	q         float64
+}
+
+// Negotiate picks the media type from offered which best matches an Accept
+// header, following RFC 9110 section 12.5.1. Each offered type is weighed by
+// the q-value of the most specific range matching it, with exact types
+// taking precedence over type/* and */* wildcards. Ties go to the type
+// offered first, so offered should be in the server's order of preference.
+//
+// An empty Accept header accepts anything, so the first offered type is
+// returned, and so does one without a single parseable range, as if it were
+// */*. If no offered type is acceptable, ErrNotAcceptable is returned.
+func Negotiate(acceptHeader string, offered []string) (string, error) {
+	if len(offered) == 0 {
+		return "", ErrNotAcceptable
+	}
+	if strings.TrimSpace(acceptHeader) == "" {
+		return offered[0], nil
+	}
+	ranges := parseAccept(acceptHeader)
+	if len(ranges) == 0 {
+		ranges = []acceptRange{{mediaType: "*/*", q: 1}}
+	}
+
+	best, bestQ := "", 0.0
+	for _, o := range offered {
+		mediaType, params, err := mime.ParseMediaType(o)
+		if err != nil {
+			continue
+		}
+		if q := acceptQuality(ranges, mediaType, params); q > bestQ {
+			best, bestQ = o, q
+		}
+	}
+	if best == "" {
+		return "", ErrNotAcceptable
+	}
+	return best, nil
+}
+
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		r := acceptRange{mediaType: mediaType, params: params, q: 1}
+		if q, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil && parsed >= 0 && parsed <= 1 {
+				r.q = parsed
+			}
+			delete(params, "q")
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// acceptQuality returns the q-value of the most specific range in ranges
+// matching mediaType, or 0 if none does.
+func acceptQuality(ranges []acceptRange, mediaType string, params map[string]string) float64 {
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	bestSpecificity, q := -1, 0.0
+	for _, r := range ranges {
+		rType, rSubtype, _ := strings.Cut(r.mediaType, "/")
+		var specificity int
+		switch {
+		case r.mediaType == "*/*":
+			specificity = 0
+		case rType == typ && rSubtype == "*":
+			specificity = 1
+		case rType == typ && rSubtype == subtype:
+			specificity = 2
+		default:
+			continue
+		}
+		if len(r.params) > 0 {
+			matched := true
+			for k, v := range r.params {
+				if !strings.EqualFold(params[k], v) {
+					matched = false
+					break
+				}
+			}
+			if !matched {
+				continue
+			}
+			specificity += len(r.params)
+		}
+		if specificity > bestSpecificity {
+			bestSpecificity, q = specificity, r.q
+		}
+	}
+	return q
+}
+
+// MarshalerFunc serializes a response body for a particular media type.
+type MarshalerFunc func(v interface{}) ([]byte, error)
+
+// MarshalerSelector picks how to serialize a response for operations which
+// offer several content types. Marshalers are preferred in the order they
+// are registered. A MarshalerSelector is usable as a zero value and safe for
+// concurrent use: selections read a snapshot without locking, and
+// registrations publish a new one.
+type MarshalerSelector struct {
+	cow copyOnWrite[marshalerSelectorState]
+}
+
+type marshalerSelectorState struct {
+	mediaTypes []string
+	marshalers map[string]MarshalerFunc
+}
+
+// Register adds a marshaler for mediaType, replacing any previous one for
+// the same media type while keeping its preference. It panics after Freeze.
+func (s *MarshalerSelector) Register(mediaType string, marshal MarshalerFunc) {
+	s.cow.update("MarshalerSelector", func(st *marshalerSelectorState) {
+		if _, ok := st.marshalers[mediaType]; !ok {
+			st.mediaTypes = append(st.mediaTypes[:len(st.mediaTypes):len(st.mediaTypes)], mediaType)
+		}
+		st.marshalers = cloneMap(st.marshalers)
+		st.marshalers[mediaType] = marshal
+	})
+}
+
+// Freeze makes the selector read-only, so that registrations made after
+// startup, which would likely be mistakes, panic rather than take effect.
+func (s *MarshalerSelector) Freeze() {
+	s.cow.freeze()
+}
+
+// Select negotiates the media type for acceptHeader among the registered
+// ones, and returns it together with its marshaler.
+func (s *MarshalerSelector) Select(acceptHeader string) (string, MarshalerFunc, error) {
+	var st marshalerSelectorState
+	if current := s.cow.load(); current != nil {
+		st = *current
+	}
+	mediaType, err := Negotiate(acceptHeader, st.mediaTypes)
+	if err != nil {
+		return "", nil, err
+	}
+	return mediaType, st.marshalers[mediaType], nil
+}
@@ -0,0 +1,69 @@
+package runtime
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	offered := []string{"application/json", "application/xml", "text/plain; charset=utf-8"}
+
+	tests := []struct {
+		accept   string
+		expected string
+	}{
+		{"", "application/json"},
+		{"*/*", "application/json"},
+		{"application/xml", "application/xml"},
+		{"application/xml;q=0.5, application/json;q=0.4", "application/xml"},
+		{"text/*", "text/plain; charset=utf-8"},
+		{"text/plain; charset=utf-8, application/json; q=0.9", "text/plain; charset=utf-8"},
+		// The exact range's q-value wins over the wildcard's.
+		{"*/*;q=0.8, application/json;q=0.1", "application/xml"},
+		{"APPLICATION/XML", "application/xml"},
+		{"application/json;q=0, */*", "application/xml"},
+		// Without a parseable range, the header is taken as */*.
+		{"garbage/", "application/json"},
+		{";;, /", "application/json"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.accept, func(t *testing.T) {
+			got, err := Negotiate(tc.accept, offered)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+
+	_, err := Negotiate("image/png", offered)
+	assert.ErrorIs(t, err, ErrNotAcceptable)
+	_, err = Negotiate("text/plain; charset=latin1", offered)
+	assert.ErrorIs(t, err, ErrNotAcceptable)
+	_, err = Negotiate("*/*", nil)
+	assert.ErrorIs(t, err, ErrNotAcceptable)
+}
+
+func TestMarshalerSelector(t *testing.T) {
+	var selector MarshalerSelector
+	selector.Register("application/json", json.Marshal)
+	selector.Register("application/xml", xml.Marshal)
+
+	mediaType, marshal, err := selector.Select("application/*")
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", mediaType)
+	data, err := marshal(struct {
+		Name string `json:"name"`
+	}{"rex"})
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"rex"}`, string(data))
+
+	mediaType, _, err = selector.Select("application/xml")
+	require.NoError(t, err)
+	assert.Equal(t, "application/xml", mediaType)
+
+	_, _, err = selector.Select("text/html")
+	assert.ErrorIs(t, err, ErrNotAcceptable)
+}
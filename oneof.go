@@ -0,0 +1,124 @@
+package runtime
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// UnionMatchError is returned by DecodeOneOf when data matches none or more
+// than one of its candidates, and by DecodeAnyOf when it matches none.
+type UnionMatchError struct {
+	// Matches holds the indexes of the candidates data matched.
+	Matches []int
+	// Errs holds the error decoding data into each candidate, or nil for
+	// the candidates it matched.
+	Errs []error
+}
+
+func (e *UnionMatchError) Error() string {
+	if len(e.Matches) == 0 {
+		msgs := make([]string, len(e.Errs))
+		for i, err := range e.Errs {
+			msgs[i] = fmt.Sprintf("candidate %d: %s", i, err)
+		}
+		return "value matches none of the candidates: " + strings.Join(msgs, "; ")
+	}
+	indexes := make([]string, len(e.Matches))
+	for i, m := range e.Matches {
+		indexes[i] = strconv.Itoa(m)
+	}
+	return "value is ambiguous, it matches candidates " + strings.Join(indexes, ", ")
+}
+
+// Unwrap returns the errors decoding data into the candidates it didn't
+// match, so that errors.Is and errors.As reach each of them.
+func (e *UnionMatchError) Unwrap() []error {
+	var errs []error
+	for _, err := range e.Errs {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// DecodeOneOf decodes the JSON data into the one of candidates, each a
+// pointer to a variant of a oneOf schema, which it strictly matches:
+// without unknown properties, mistyped values or trailing data. It returns
+// the index of the matching candidate, and only that candidate is written
+// to. If data matches none or several candidates, a *UnionMatchError is
+// returned, so that ambiguous schemas fail deterministically rather than
+// silently picking a variant.
+func DecodeOneOf(data []byte, candidates ...any) (int, error) {
+	matches, err := decodeCandidates(data, candidates)
+	if err != nil {
+		return -1, err
+	}
+	if len(matches.Matches) != 1 {
+		return -1, matches
+	}
+	i := matches.Matches[0]
+	setCandidate(candidates[i], data)
+	return i, nil
+}
+
+// DecodeAnyOf is DecodeOneOf for anyOf schemas: it decodes data into every
+// candidate it strictly matches and returns their indexes, failing with a
+// *UnionMatchError only if there are none.
+func DecodeAnyOf(data []byte, candidates ...any) ([]int, error) {
+	matches, err := decodeCandidates(data, candidates)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches.Matches) == 0 {
+		return nil, matches
+	}
+	for _, i := range matches.Matches {
+		setCandidate(candidates[i], data)
+	}
+	return matches.Matches, nil
+}
+
+// decodeCandidates strictly decodes data into a new value of each
+// candidate's type, recording which succeed, without touching the
+// candidates themselves.
+func decodeCandidates(data []byte, candidates []any) (*UnionMatchError, error) {
+	result := &UnionMatchError{Errs: make([]error, len(candidates))}
+	for i, c := range candidates {
+		v := reflect.ValueOf(c)
+		if v.Kind() != reflect.Ptr || v.IsNil() {
+			return nil, fmt.Errorf("candidate %d is not a non-nil pointer", i)
+		}
+		if err := decodeStrict(data, reflect.New(v.Type().Elem()).Interface()); err != nil {
+			result.Errs[i] = err
+			continue
+		}
+		result.Matches = append(result.Matches, i)
+	}
+	return result, nil
+}
+
+// setCandidate decodes data, which is known to match, into candidate.
+func setCandidate(candidate any, data []byte) {
+	v := reflect.ValueOf(candidate).Elem()
+	v.Set(reflect.Zero(v.Type()))
+	_ = decodeStrict(data, candidate)
+}
+
+func decodeStrict(data []byte, dest any) error {
+	dec := newJSONDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dest); err != nil {
+		return err
+	}
+	var trailing interface{}
+	if err := dec.Decode(&trailing); !errors.Is(err, io.EOF) {
+		return errors.New("unexpected data after the JSON value")
+	}
+	return nil
+}
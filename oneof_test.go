@@ -0,0 +1,60 @@
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeOneOf(t *testing.T) {
+	type Cat struct {
+		Name  string `json:"name"`
+		Lives int    `json:"lives"`
+	}
+	type Dog struct {
+		Name string `json:"name"`
+		Bark string `json:"bark"`
+	}
+
+	var cat Cat
+	var dog Dog
+	i, err := DecodeOneOf([]byte(`{"name":"tom","lives":9}`), &cat, &dog)
+	require.NoError(t, err)
+	assert.Equal(t, 0, i)
+	assert.Equal(t, Cat{Name: "tom", Lives: 9}, cat)
+	assert.Equal(t, Dog{}, dog)
+
+	_, err = DecodeOneOf([]byte(`{"name":"rex"}`), &cat, &dog)
+	var matchErr *UnionMatchError
+	require.True(t, errors.As(err, &matchErr))
+	assert.Equal(t, []int{0, 1}, matchErr.Matches)
+
+	_, err = DecodeOneOf([]byte(`{"name":"rex","wings":2}`), &cat, &dog)
+	require.True(t, errors.As(err, &matchErr))
+	assert.Empty(t, matchErr.Matches)
+	assert.Len(t, matchErr.Errs, 2)
+
+	_, err = DecodeOneOf([]byte(`{"name":7}`), &cat, &dog)
+	var typeErr *json.UnmarshalTypeError
+	require.True(t, errors.As(err, &typeErr))
+	assert.Equal(t, "name", typeErr.Field)
+
+	_, err = DecodeOneOf([]byte(`{"name":"rex","bark":"woof"} {}`), &cat, &dog)
+	assert.Error(t, err)
+
+	cat, dog = Cat{}, Dog{}
+	matches, err := DecodeAnyOf([]byte(`{"name":"rex"}`), &cat, &dog)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1}, matches)
+	assert.Equal(t, "rex", cat.Name)
+	assert.Equal(t, "rex", dog.Name)
+
+	_, err = DecodeAnyOf([]byte(`"rex"`), &cat, &dog)
+	assert.True(t, errors.As(err, &matchErr))
+
+	_, err = DecodeOneOf([]byte(`{}`), cat)
+	assert.Error(t, err)
+}
@@ -0,0 +1,177 @@
+package runtime
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// QueryPair is a single name=value parameter of a query string.
+type QueryPair struct {
+	Name  string
+	Value string
+	// raw is the pair as StyleParamWithLocation escaped it, which Encode
+	// writes as is while Name and Value are unchanged, as unescaping it
+	// loses the difference between the delimiters of a styled value and
+	// the escaped delimiters within its elements.
+	raw string
+}
+
+// OrderedQuery is a query string as a list of its parameters, in order. It
+// is the alternative to url.Values, which groups values by name and encodes
+// names sorted, for integrations whose signatures or servers depend on the
+// order parameters are sent in. Names and values are held unescaped, as in
+// url.Values.
+type OrderedQuery []QueryPair
+
+// ParseOrderedQuery parses rawQuery, as url.ParseQuery does, keeping the
+// order of its parameters. Like url.ParseQuery, it skips malformed
+// parameters, including those containing a semicolon, returning the first
+// error found along with the rest.
+func ParseOrderedQuery(rawQuery string) (OrderedQuery, error) {
+	var q OrderedQuery
+	var firstErr error
+	for rawQuery != "" {
+		var pair string
+		pair, rawQuery, _ = strings.Cut(rawQuery, "&")
+		if pair == "" {
+			continue
+		}
+		p, err := parseQueryPair(pair)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		q = append(q, p)
+	}
+	return q, firstErr
+}
+
+// parseQueryPair parses a single name=value parameter of a query string.
+func parseQueryPair(pair string) (QueryPair, error) {
+	if strings.Contains(pair, ";") {
+		return QueryPair{}, errors.New("invalid semicolon separator in query")
+	}
+	rawName, rawValue, _ := strings.Cut(pair, "=")
+	name, err := url.QueryUnescape(rawName)
+	if err != nil {
+		return QueryPair{}, err
+	}
+	value, err := url.QueryUnescape(rawValue)
+	if err != nil {
+		return QueryPair{}, err
+	}
+	return QueryPair{Name: name, Value: value}, nil
+}
+
+// OrderedQueryFromValues converts values, ordering parameters by name, as
+// url.Values.Encode does, and keeping the order of each name's values.
+func OrderedQueryFromValues(values url.Values) OrderedQuery {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var q OrderedQuery
+	for _, name := range names {
+		for _, value := range values[name] {
+			q = append(q, QueryPair{Name: name, Value: value})
+		}
+	}
+	return q
+}
+
+// Values converts q to url.Values.
+func (q OrderedQuery) Values() url.Values {
+	values := make(url.Values)
+	for _, p := range q {
+		values[p.Name] = append(values[p.Name], p.Value)
+	}
+	return values
+}
+
+// Get returns the first value of the parameter name, or an empty string if
+// there is none.
+func (q OrderedQuery) Get(name string) string {
+	for _, p := range q {
+		if p.Name == name {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+// Add appends a parameter.
+func (q *OrderedQuery) Add(name, value string) {
+	*q = append(*q, QueryPair{Name: name, Value: value})
+}
+
+// Del removes every value of the parameter name.
+func (q *OrderedQuery) Del(name string) {
+	kept := (*q)[:0]
+	for _, p := range *q {
+		if p.Name != name {
+			kept = append(kept, p)
+		}
+	}
+	*q = kept
+}
+
+// AddStyled appends the parameters StyleParamWithLocation renders value as,
+// for a query parameter of the given style, in the order it renders them.
+func (q *OrderedQuery) AddStyled(style string, explode bool, paramName string, value interface{}) error {
+	styled, err := StyleParamWithLocation(style, explode, paramName, ParamLocationQuery, value)
+	if err != nil {
+		return err
+	}
+	for _, pair := range strings.Split(styled, "&") {
+		if pair == "" {
+			continue
+		}
+		p, err := parseQueryPair(pair)
+		if err != nil {
+			return err
+		}
+		p.raw = pair
+		*q = append(*q, p)
+	}
+	return nil
+}
+
+// Encode encodes q as a query string, in order. Parameters added with
+// AddStyled are written as the style escaped them, unless changed since.
+func (q OrderedQuery) Encode() string {
+	var buf strings.Builder
+	for i, p := range q {
+		if i > 0 {
+			buf.WriteByte('&')
+		}
+		if p.raw != "" {
+			if parsed, err := parseQueryPair(p.raw); err == nil && parsed.Name == p.Name && parsed.Value == p.Value {
+				buf.WriteString(p.raw)
+				continue
+			}
+		}
+		buf.WriteString(url.QueryEscape(p.Name))
+		buf.WriteByte('=')
+		buf.WriteString(url.QueryEscape(p.Value))
+	}
+	return buf.String()
+}
+
+// BindOrderedQueryParameter is BindQueryParameter for an OrderedQuery.
+func BindOrderedQueryParameter(style string, explode bool, required bool, paramName string, query OrderedQuery, dest interface{}) error {
+	return BindOrderedQueryParameterWithOptions(style, paramName, query, dest, BindQueryParameterOptions{
+		Explode:  explode,
+		Required: required,
+	})
+}
+
+// BindOrderedQueryParameterWithOptions is BindQueryParameterWithOptions for
+// an OrderedQuery.
+func BindOrderedQueryParameterWithOptions(style string, paramName string, query OrderedQuery, dest interface{}, opts BindQueryParameterOptions) error {
+	return BindQueryParameterWithOptions(style, paramName, query.Values(), dest, opts)
+}
@@ -0,0 +1,54 @@
+package runtime
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderedQuery(t *testing.T) {
+	q, err := ParseOrderedQuery("z=1&a=x+y&z=2&&m=%2C")
+	require.NoError(t, err)
+	assert.Equal(t, OrderedQuery{{Name: "z", Value: "1"}, {Name: "a", Value: "x y"}, {Name: "z", Value: "2"}, {Name: "m", Value: ","}}, q)
+	assert.Equal(t, "1", q.Get("z"))
+	assert.Equal(t, url.Values{"z": {"1", "2"}, "a": {"x y"}, "m": {","}}, q.Values())
+	assert.Equal(t, "z=1&a=x+y&z=2&m=%2C", q.Encode())
+
+	q.Del("z")
+	q.Add("b", "&")
+	assert.Equal(t, "a=x+y&m=%2C&b=%26", q.Encode())
+
+	q, err = ParseOrderedQuery("a=1&b=%zz&c=3")
+	assert.Error(t, err)
+	assert.Equal(t, OrderedQuery{{Name: "a", Value: "1"}, {Name: "c", Value: "3"}}, q)
+
+	// Semicolons are rejected, as url.ParseQuery does.
+	q, err = ParseOrderedQuery("a=1;b=2&c=3")
+	assert.Error(t, err)
+	assert.Equal(t, OrderedQuery{{Name: "c", Value: "3"}}, q)
+
+	assert.Equal(t, OrderedQuery{{Name: "a", Value: "1"}, {Name: "b", Value: "2"}, {Name: "b", Value: "3"}},
+		OrderedQueryFromValues(url.Values{"b": {"2", "3"}, "a": {"1"}}))
+}
+
+func TestOrderedQuery_Styled(t *testing.T) {
+	var q OrderedQuery
+	require.NoError(t, q.AddStyled("form", true, "sig", "abc"))
+	require.NoError(t, q.AddStyled("form", true, "id", []int{3, 1}))
+	require.NoError(t, q.AddStyled("form", false, "a", "z"))
+	assert.Equal(t, "sig=abc&id=3&id=1&a=z", q.Encode())
+
+	// The commas delimiting the elements of a styled value stay apart from
+	// those escaped within them.
+	var tags OrderedQuery
+	require.NoError(t, tags.AddStyled("form", false, "tags", []string{"a", "b,c"}))
+	assert.Equal(t, "tags=a,b%2Cc", tags.Encode())
+	tags[0].Value = "d"
+	assert.Equal(t, "tags=d", tags.Encode())
+
+	var ids []int
+	require.NoError(t, BindOrderedQueryParameter("form", true, true, "id", q, &ids))
+	assert.Equal(t, []int{3, 1}, ids)
+}
@@ -0,0 +1,246 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Page is one page of a list operation, as returned by a PageFetcher.
+type Page[T any] struct {
+	Items []T
+	// NextCursor is the cursor to request the following page with, for
+	// APIs paginated by cursor, usually taken from the response body. An
+	// empty cursor marks the last page.
+	NextCursor string
+	// Response is the response the page was read from. Its Link header is
+	// followed when a Pager has neither a PageParam nor a CursorParam.
+	Response *http.Response
+}
+
+// PageFetcher fetches a page by calling a list operation of a generated
+// client, passing it editor, which points the request at the page wanted:
+//
+//	func(ctx context.Context, editor runtime.RequestEditorFn) (runtime.Page[Pet], error) {
+//		resp, err := client.ListPetsWithResponse(ctx, params, api.RequestEditorFn(editor))
+//		if err != nil {
+//			return runtime.Page[Pet]{}, err
+//		}
+//		return runtime.Page[Pet]{Items: resp.JSON200.Items, Response: resp.HTTPResponse}, nil
+//	}
+type PageFetcher[T any] func(ctx context.Context, editor RequestEditorFn) (Page[T], error)
+
+// PagerOptions selects how a Pager moves from one page to the next. When
+// PageParam is set, pages are numbered by that query parameter, counting
+// from 1, or 0 with ZeroBasedPages, until an empty page. Otherwise, when
+// CursorParam is set, each page's NextCursor is sent in that query parameter
+// until a page has none. With neither, the rel="next" URL of each response's
+// Link header is followed, as described in RFC 8288, until a response has
+// none or links to the URL it was requested with. A next link to another origin than the request's fails with a
+// *CrossOriginLinkError.
+type PagerOptions struct {
+	PageParam      string
+	ZeroBasedPages bool
+	CursorParam    string
+}
+
+// CrossOriginLinkError is returned by a Pager for a Link header pointing the
+// next page at another scheme or host than the request for the current one,
+// which it refuses to follow, as the request carries the credentials meant
+// for the API's server.
+type CrossOriginLinkError struct {
+	Link string
+}
+
+func (e *CrossOriginLinkError) Error() string {
+	return fmt.Sprintf("next page link '%s' points to another origin", e.Link)
+}
+
+// Pager steps through the pages of a list operation, so that callers don't
+// need to write a pagination loop for every one of them. It works like
+// bufio.Scanner:
+//
+//	for pager.Next(ctx) {
+//		for _, pet := range pager.Page().Items {
+//			...
+//		}
+//	}
+//	if err := pager.Err(); err != nil {
+//		...
+//	}
+type Pager[T any] struct {
+	fetch PageFetcher[T]
+	opts  PagerOptions
+	page  Page[T]
+	err   error
+	done  bool
+	// number, cursor and link locate the next page to fetch.
+	number int
+	cursor string
+	link   *url.URL
+}
+
+// NewPager returns a Pager fetching pages with fetch.
+func NewPager[T any](fetch PageFetcher[T], opts PagerOptions) *Pager[T] {
+	p := &Pager[T]{fetch: fetch, opts: opts, number: 1}
+	if opts.ZeroBasedPages {
+		p.number = 0
+	}
+	return p
+}
+
+// Next fetches the next page. It returns false after the last page or on
+// error.
+func (p *Pager[T]) Next(ctx context.Context) bool {
+	if p.done || p.err != nil {
+		return false
+	}
+	page, err := p.fetch(ctx, p.editor())
+	if err != nil {
+		p.err = err
+		return false
+	}
+	p.page = page
+
+	switch {
+	case p.opts.PageParam != "":
+		if len(page.Items) == 0 {
+			p.done = true
+			return false
+		}
+		p.number++
+	case p.opts.CursorParam != "":
+		// A cursor which doesn't move would fetch the same page forever.
+		if page.NextCursor == "" || page.NextCursor == p.cursor {
+			p.done = true
+		}
+		p.cursor = page.NextCursor
+	default:
+		p.done = true
+		if page.Response != nil {
+			if next := linkURL(page.Response.Header, "next"); next != "" {
+				base := page.Response.Request
+				if u, err := url.Parse(next); err != nil {
+					p.err = err
+					return false
+				} else if base != nil && base.URL != nil {
+					p.link = base.URL.ResolveReference(u)
+				} else {
+					p.link = u
+				}
+				// A page linking to itself would be fetched forever.
+				p.done = base != nil && base.URL != nil && p.link.String() == base.URL.String()
+			}
+		}
+	}
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *Pager[T]) Page() Page[T] {
+	return p.page
+}
+
+// Err returns the error which stopped the pager, if any.
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// editor returns the RequestEditorFn which points a request at the next
+// page.
+func (p *Pager[T]) editor() RequestEditorFn {
+	switch {
+	case p.opts.PageParam != "":
+		return setQueryParam(p.opts.PageParam, strconv.Itoa(p.number))
+	case p.opts.CursorParam != "":
+		if p.cursor == "" {
+			return noopRequestEditor
+		}
+		return setQueryParam(p.opts.CursorParam, p.cursor)
+	case p.link != nil:
+		link := p.link
+		return func(ctx context.Context, req *http.Request) error {
+			if !strings.EqualFold(link.Scheme, req.URL.Scheme) || !strings.EqualFold(link.Host, req.URL.Host) {
+				return &CrossOriginLinkError{Link: link.String()}
+			}
+			// Each request gets a URL of its own, which other editors may
+			// modify.
+			u := *link
+			req.URL = &u
+			req.Host = ""
+			return nil
+		}
+	}
+	return noopRequestEditor
+}
+
+func noopRequestEditor(ctx context.Context, req *http.Request) error {
+	return nil
+}
+
+// setQueryParam returns the RequestEditorFn which sets the query parameter
+// name to value. It replaces the first value of the parameter in place and
+// drops any others, leaving the rest of the query as the client escaped it,
+// as re-encoding it would escape the delimiters of styled parameters.
+func setQueryParam(name, value string) RequestEditorFn {
+	return func(ctx context.Context, req *http.Request) error {
+		param := url.QueryEscape(name) + "=" + url.QueryEscape(value)
+		var pairs []string
+		found := false
+		for _, pair := range strings.Split(req.URL.RawQuery, "&") {
+			if pair == "" {
+				continue
+			}
+			rawName, _, _ := strings.Cut(pair, "=")
+			if n, err := url.QueryUnescape(rawName); err == nil && n == name {
+				if found {
+					continue
+				}
+				pair, found = param, true
+			}
+			pairs = append(pairs, pair)
+		}
+		if !found {
+			pairs = append(pairs, param)
+		}
+		req.URL.RawQuery = strings.Join(pairs, "&")
+		return nil
+	}
+}
+
+// linkURL returns the target of the first link with the relation type rel
+// in the Link headers of h, or "" when there is none.
+func linkURL(h http.Header, rel string) string {
+	for _, value := range h.Values("Link") {
+		for {
+			start := strings.IndexByte(value, '<')
+			end := strings.IndexByte(value, '>')
+			if start < 0 || end < start {
+				break
+			}
+			target := value[start+1 : end]
+			value = value[end+1:]
+			// The link's parameters run up to the next link, if any.
+			params := value
+			if next := strings.IndexByte(value, '<'); next >= 0 {
+				params = value[:next]
+			}
+			for _, param := range strings.Split(params, ";") {
+				key, val, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if !strings.EqualFold(strings.TrimSpace(key), "rel") {
+					continue
+				}
+				val = strings.TrimRight(strings.TrimSpace(val), ", ")
+				for _, r := range strings.Fields(strings.Trim(val, `"`)) {
+					if strings.EqualFold(r, rel) {
+						return target
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,34 @@
+//go:build go1.23
+
+package runtime
+
+import (
+	"context"
+	"iter"
+)
+
+// All returns an iterator over the items of every remaining page, fetching
+// pages as the loop needs them. Once ctx is done, the iteration stops, even
+// within a page, and Err returns ctx.Err(). Check Err once the loop is done.
+func (p *Pager[T]) All(ctx context.Context) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for p.live(ctx) && p.Next(ctx) {
+			for _, item := range p.Page().Items {
+				if !p.live(ctx) || !yield(item) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// live reports whether ctx isn't done yet, recording its error otherwise.
+func (p *Pager[T]) live(ctx context.Context) bool {
+	if err := ctx.Err(); err != nil {
+		if p.err == nil {
+			p.err = err
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+//go:build go1.23
+
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_All(t *testing.T) {
+	fetches := 0
+	fetch := fetchTestPage("https://example.com/pets", func(req *http.Request) Page[int] {
+		fetches++
+		n, _ := strconv.Atoi(req.URL.Query().Get("page"))
+		return Page[int]{Items: []int{n*10 + 1, n*10 + 2}}
+	})
+	pager := NewPager(fetch, PagerOptions{PageParam: "page", ZeroBasedPages: true})
+
+	var items []int
+	for item := range pager.All(context.Background()) {
+		items = append(items, item)
+		if len(items) == 3 {
+			break
+		}
+	}
+	require.NoError(t, pager.Err())
+	assert.Equal(t, []int{1, 2, 11}, items)
+	assert.Equal(t, 2, fetches)
+}
+
+func TestPager_AllCanceled(t *testing.T) {
+	fetches := 0
+	fetch := fetchTestPage("https://example.com/pets", func(req *http.Request) Page[int] {
+		fetches++
+		n, _ := strconv.Atoi(req.URL.Query().Get("page"))
+		return Page[int]{Items: []int{n*10 + 1, n*10 + 2}}
+	})
+	pager := NewPager(fetch, PagerOptions{PageParam: "page", ZeroBasedPages: true})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var items []int
+	for item := range pager.All(ctx) {
+		items = append(items, item)
+		cancel()
+	}
+	assert.ErrorIs(t, pager.Err(), context.Canceled)
+	assert.Equal(t, []int{1}, items)
+	assert.Equal(t, 1, fetches)
+}
@@ -0,0 +1,179 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fetchTestPage sends a request to url, edited by editor, as a generated
+// client would, and hands the request to page to build the result.
+func fetchTestPage(url string, page func(req *http.Request) Page[int]) PageFetcher[int] {
+	return func(ctx context.Context, editor RequestEditorFn) (Page[int], error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return Page[int]{}, err
+		}
+		if err := editor(ctx, req); err != nil {
+			return Page[int]{}, err
+		}
+		return page(req), nil
+	}
+}
+
+func collectPages(t *testing.T, pager *Pager[int]) []int {
+	t.Helper()
+	var items []int
+	for pager.Next(context.Background()) {
+		items = append(items, pager.Page().Items...)
+	}
+	require.NoError(t, pager.Err())
+	return items
+}
+
+func TestPager_PageParam(t *testing.T) {
+	var requested []string
+	fetch := fetchTestPage("https://example.com/pets?limit=2", func(req *http.Request) Page[int] {
+		requested = append(requested, req.URL.RawQuery)
+		n, _ := strconv.Atoi(req.URL.Query().Get("page"))
+		if n > 2 {
+			return Page[int]{}
+		}
+		return Page[int]{Items: []int{n*2 - 1, n * 2}}
+	})
+
+	items := collectPages(t, NewPager(fetch, PagerOptions{PageParam: "page"}))
+	assert.Equal(t, []int{1, 2, 3, 4}, items)
+	assert.Equal(t, []string{"limit=2&page=1", "limit=2&page=2", "limit=2&page=3"}, requested)
+}
+
+func TestPager_KeepsRawQuery(t *testing.T) {
+	var requested []string
+	fetch := fetchTestPage("https://example.com/pets?ids=1,2&page=9&filter[color]=brown&page=8", func(req *http.Request) Page[int] {
+		requested = append(requested, req.URL.RawQuery)
+		if len(requested) > 1 {
+			return Page[int]{}
+		}
+		return Page[int]{Items: []int{1}}
+	})
+
+	collectPages(t, NewPager(fetch, PagerOptions{PageParam: "page"}))
+	assert.Equal(t, []string{
+		"ids=1,2&page=1&filter[color]=brown",
+		"ids=1,2&page=2&filter[color]=brown",
+	}, requested)
+}
+
+func TestPager_CursorParam(t *testing.T) {
+	pages := map[string]Page[int]{
+		"":  {Items: []int{1}, NextCursor: "b"},
+		"b": {Items: []int{2}, NextCursor: "c"},
+		"c": {Items: []int{3}},
+	}
+	fetch := fetchTestPage("https://example.com/pets", func(req *http.Request) Page[int] {
+		return pages[req.URL.Query().Get("cursor")]
+	})
+
+	items := collectPages(t, NewPager(fetch, PagerOptions{CursorParam: "cursor"}))
+	assert.Equal(t, []int{1, 2, 3}, items)
+}
+
+func TestPager_LinkHeader(t *testing.T) {
+	fetch := fetchTestPage("https://example.com/pets", func(req *http.Request) Page[int] {
+		resp := &http.Response{Header: make(http.Header), Request: req}
+		switch req.URL.Path {
+		case "/pets":
+			resp.Header.Set("Link", `<https://example.com/pets?x=1,2>; rel="first", </pets/2>; rel="next last"`)
+			return Page[int]{Items: []int{1}, Response: resp}
+		case "/pets/2":
+			return Page[int]{Items: []int{2}, Response: resp}
+		}
+		return Page[int]{}
+	})
+
+	items := collectPages(t, NewPager(fetch, PagerOptions{}))
+	assert.Equal(t, []int{1, 2}, items)
+}
+
+func TestPager_SelfLink(t *testing.T) {
+	var requests int
+	fetch := fetchTestPage("https://example.com/pets?page=1", func(req *http.Request) Page[int] {
+		requests++
+		resp := &http.Response{Header: make(http.Header), Request: req}
+		resp.Header.Set("Link", `</pets?page=1>; rel=next`)
+		return Page[int]{Items: []int{1}, Response: resp}
+	})
+
+	items := collectPages(t, NewPager(fetch, PagerOptions{}))
+	assert.Equal(t, []int{1}, items)
+	assert.Equal(t, 1, requests)
+}
+
+func TestPager_CrossOriginLink(t *testing.T) {
+	var requested []string
+	fetch := fetchTestPage("https://example.com/pets", func(req *http.Request) Page[int] {
+		requested = append(requested, req.URL.String())
+		resp := &http.Response{Header: make(http.Header), Request: req}
+		resp.Header.Set("Link", `<https://evil.example.org/pets/2>; rel=next`)
+		return Page[int]{Items: []int{1}, Response: resp}
+	})
+
+	pager := NewPager(fetch, PagerOptions{})
+	require.True(t, pager.Next(context.Background()))
+	assert.False(t, pager.Next(context.Background()))
+	var crossOrigin *CrossOriginLinkError
+	require.ErrorAs(t, pager.Err(), &crossOrigin)
+	assert.Equal(t, "https://evil.example.org/pets/2", crossOrigin.Link)
+	assert.Equal(t, []string{"https://example.com/pets"}, requested)
+}
+
+func TestPager_Error(t *testing.T) {
+	fetchErr := errors.New("boom")
+	pager := NewPager(func(ctx context.Context, editor RequestEditorFn) (Page[int], error) {
+		return Page[int]{}, fetchErr
+	}, PagerOptions{PageParam: "page"})
+	assert.False(t, pager.Next(context.Background()))
+	assert.False(t, pager.Next(context.Background()))
+	assert.ErrorIs(t, pager.Err(), fetchErr)
+}
+
+func TestPager_Server(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", `</items?page=2>; rel=next`)
+			_, _ = w.Write([]byte("1"))
+			return
+		}
+		_, _ = w.Write([]byte(r.URL.Query().Get("page")))
+	}))
+	defer srv.Close()
+
+	fetch := func(ctx context.Context, editor RequestEditorFn) (Page[int], error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/items", nil)
+		if err != nil {
+			return Page[int]{}, err
+		}
+		if err := editor(ctx, req); err != nil {
+			return Page[int]{}, err
+		}
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			return Page[int]{}, err
+		}
+		defer func() { _ = resp.Body.Close() }()
+		var n int
+		if err := BindJSONBody(resp.Body, &n); err != nil {
+			return Page[int]{}, err
+		}
+		return Page[int]{Items: []int{n}, Response: resp}, nil
+	}
+
+	items := collectPages(t, NewPager(fetch, PagerOptions{}))
+	assert.Equal(t, []int{1, 2}, items)
+}
@@ -0,0 +1,103 @@
+package runtime
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+)
+
+// ParamInferrer converts a parameter's value into a Go value for binding
+// into an interface{} destination, which declares no type to parse it as.
+type ParamInferrer func(value string) interface{}
+
+// InferParamValue is the default ParamInferrer. It binds "true" and "false"
+// as a bool, integers which fit an int64 as an int64, other numbers as a
+// float64, and everything else, including "NaN" and "Inf", which aren't
+// finite numbers, as the string it is.
+func InferParamValue(value string) interface{} {
+	switch value {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+		return f
+	}
+	return value
+}
+
+// InferParamJSONNumber is a ParamInferrer which binds "true" and "false" as
+// a bool, numbers as a json.Number, which keeps every digit of large IDs and
+// decimals, and everything else as the string it is.
+func InferParamJSONNumber(value string) interface{} {
+	switch value {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if isJSONNumber(value) {
+		return json.Number(value)
+	}
+	return value
+}
+
+// isJSONNumber reports whether s is a number in JSON syntax.
+func isJSONNumber(s string) bool {
+	var n json.Number
+	return s != "" && json.Unmarshal([]byte(s), &n) == nil && string(n) == s
+}
+
+// InferParamString is a ParamInferrer which binds every value as a string,
+// for handlers which forward parameters as they were sent.
+func InferParamString(value string) interface{} {
+	return value
+}
+
+type paramInferrerHolder struct {
+	infer ParamInferrer
+}
+
+var paramInferrer atomic.Pointer[paramInferrerHolder]
+
+// SetParamInferrer sets how parameters bound into interface{} destinations,
+// such as by proxies capturing parameters they don't know the types of, or
+// the values of map[string]interface{} deepObject parameters, are converted.
+// Passing nil restores the default, which is InferParamJSONNumber while
+// SetUseJSONNumber is in effect, and InferParamValue otherwise. It panics
+// after FreezeSettings.
+func SetParamInferrer(infer ParamInferrer) {
+	checkSettingsFrozen("SetParamInferrer")
+	if infer == nil {
+		paramInferrer.Store(nil)
+		return
+	}
+	paramInferrer.Store(&paramInferrerHolder{infer: infer})
+}
+
+// inferParam converts value with the installed ParamInferrer.
+func inferParam(value string) interface{} {
+	if h := paramInferrer.Load(); h != nil {
+		return h.infer(value)
+	}
+	if useJSONNumber.Load() {
+		return InferParamJSONNumber(value)
+	}
+	return InferParamValue(value)
+}
+
+// setInferred sets v, an interface{} value, to x, a value returned by a
+// ParamInferrer, which may be nil.
+func setInferred(v reflect.Value, x interface{}) {
+	if x == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return
+	}
+	v.Set(reflect.ValueOf(x))
+}
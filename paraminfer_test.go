@@ -0,0 +1,68 @@
+package runtime
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInferParamValue(t *testing.T) {
+	assert.Equal(t, true, InferParamValue("true"))
+	assert.Equal(t, int64(42), InferParamValue("42"))
+	assert.Equal(t, 1.5, InferParamValue("1.5"))
+	assert.Equal(t, "abc", InferParamValue("abc"))
+	assert.Equal(t, "True", InferParamValue("True"))
+	for _, s := range []string{"nan", "NaN", "inf", "-Inf", "Infinity", "1e400"} {
+		assert.Equal(t, s, InferParamValue(s), s)
+	}
+}
+
+func TestBindParamIntoInterface_NilInferred(t *testing.T) {
+	defer SetParamInferrer(nil)
+	SetParamInferrer(func(value string) interface{} { return nil })
+
+	v := interface{}("previous")
+	require.NoError(t, BindStringToObject("x", &v))
+	assert.Nil(t, v)
+
+	var m map[string]interface{}
+	require.NoError(t, UnmarshalDeepObject(&m, "p", url.Values{"p[a]": {"x"}}))
+	assert.Equal(t, map[string]interface{}{"a": nil}, m)
+}
+
+func TestBindParamIntoInterface(t *testing.T) {
+	var v interface{}
+	require.NoError(t, BindQueryParameter("form", true, true, "n", url.Values{"n": {"7"}}, &v))
+	assert.Equal(t, int64(7), v)
+
+	var optional *any
+	require.NoError(t, BindQueryParameter("form", true, false, "b", url.Values{"b": {"false"}}, &optional))
+	require.NotNil(t, optional)
+	assert.Equal(t, false, *optional)
+
+	var items []interface{}
+	require.NoError(t, BindStyledParameterWithOptions("simple", "items", "1,x,2.5", &items, BindStyledParameterOptions{
+		ParamLocation: ParamLocationPath,
+	}))
+	assert.Equal(t, []interface{}{int64(1), "x", 2.5}, items)
+
+	defer SetParamInferrer(nil)
+	SetParamInferrer(InferParamString)
+	require.NoError(t, BindStringToObject("7", &v))
+	assert.Equal(t, "7", v)
+
+	var stringer interface{ String() string }
+	assert.Error(t, BindStringToObject("7", &stringer))
+}
+
+func TestInferParamJSONNumber(t *testing.T) {
+	assert.Equal(t, json.Number("12345678901234567890"), InferParamJSONNumber("12345678901234567890"))
+	assert.Equal(t, json.Number("-1.5e3"), InferParamJSONNumber("-1.5e3"))
+	assert.Equal(t, true, InferParamJSONNumber("true"))
+	for _, s := range []string{"", "01", "1.", "+1", " 1", "NaN", "abc"} {
+		assert.Equal(t, s, InferParamJSONNumber(s), s)
+	}
+}
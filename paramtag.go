@@ -0,0 +1,109 @@
+package runtime
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// paramTagName is the struct tag describing how a field maps to an OpenAPI
+// parameter, for helpers which work on a whole params struct at once. Its
+// format is
+//
+//	param:"name,in=query,style=form,explode,required"
+//
+// where the name is required and defaults to the field name when empty,
+// "in" is one of path, query, header, cookie or body, and style and explode
+// default to the OpenAPI defaults for the location: simple and unexploded
+// for path and header parameters, form and exploded for query and cookie
+// parameters.
+// "explode=false" turns explode off explicitly. A body field only takes the
+// "required" option, and its name is ignored.
+const paramTagName = "param"
+
+// paramTag is a parsed param struct tag.
+type paramTag struct {
+	Name     string
+	Location ParamLocation
+	Style    string
+	Explode  bool
+	Required bool
+	// Body is set for the request body, whose Location is undefined.
+	Body bool
+}
+
+// parseParamTag parses the param tag of field. It reports false if the field
+// has no param tag.
+func parseParamTag(field reflect.StructField) (paramTag, bool, error) {
+	tag, ok := field.Tag.Lookup(paramTagName)
+	if !ok || tag == "-" {
+		return paramTag{}, false, nil
+	}
+	parts := strings.Split(tag, ",")
+	pt := paramTag{Name: parts[0]}
+	if pt.Name == "" {
+		pt.Name = field.Name
+	}
+
+	var explode *bool
+	for _, opt := range parts[1:] {
+		key, value, hasValue := strings.Cut(opt, "=")
+		switch key {
+		case "in":
+			switch value {
+			case "path":
+				pt.Location = ParamLocationPath
+			case "query":
+				pt.Location = ParamLocationQuery
+			case "header":
+				pt.Location = ParamLocationHeader
+			case "cookie":
+				pt.Location = ParamLocationCookie
+			case "body":
+				pt.Body = true
+			default:
+				return paramTag{}, false, fmt.Errorf("field %s: unknown parameter location '%s'", field.Name, value)
+			}
+		case "style":
+			pt.Style = value
+		case "explode":
+			b := true
+			if hasValue {
+				var err error
+				if b, err = strconv.ParseBool(value); err != nil {
+					return paramTag{}, false, fmt.Errorf("field %s: invalid explode value '%s'", field.Name, value)
+				}
+			}
+			explode = &b
+		case "required":
+			pt.Required = true
+		default:
+			return paramTag{}, false, fmt.Errorf("field %s: unknown param tag option '%s'", field.Name, opt)
+		}
+	}
+
+	switch {
+	case pt.Body:
+		if pt.Style != "" || explode != nil {
+			return paramTag{}, false, fmt.Errorf("field %s: the body takes no style", field.Name)
+		}
+		return pt, true, nil
+	case pt.Location == ParamLocationPath, pt.Location == ParamLocationHeader:
+		if pt.Style == "" {
+			pt.Style = "simple"
+		}
+		pt.Required = pt.Required || pt.Location == ParamLocationPath
+	case pt.Location == ParamLocationQuery, pt.Location == ParamLocationCookie:
+		if pt.Style == "" {
+			pt.Style = "form"
+		}
+		pt.Explode = true
+	default:
+		return paramTag{}, false, fmt.Errorf("field %s: param tag is missing its 'in' location", field.Name)
+	}
+	if explode != nil {
+		pt.Explode = *explode
+	}
+	return pt, true, nil
+}
@@ -0,0 +1,58 @@
+package runtime
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseParamTag(t *testing.T) {
+	type Params struct {
+		Path     string `param:"id,in=path"`
+		Query    string `param:"q,in=query"`
+		Header   string `param:"X-Key,in=header,required"`
+		Cookie   string `param:"session,in=cookie,explode=false"`
+		Label    string `param:"label,in=path,style=label,explode"`
+		Unnamed  string `param:",in=query"`
+		Untagged string
+		Skipped  string `param:"-"`
+		Body     []byte `param:"body,in=body,required"`
+	}
+	typ := reflect.TypeOf(Params{})
+	parse := func(name string) (paramTag, bool) {
+		field, _ := typ.FieldByName(name)
+		pt, ok, err := parseParamTag(field)
+		require.NoError(t, err)
+		return pt, ok
+	}
+
+	pt, _ := parse("Path")
+	assert.Equal(t, paramTag{Name: "id", Location: ParamLocationPath, Style: "simple", Required: true}, pt)
+	pt, _ = parse("Query")
+	assert.Equal(t, paramTag{Name: "q", Location: ParamLocationQuery, Style: "form", Explode: true}, pt)
+	pt, _ = parse("Header")
+	assert.Equal(t, paramTag{Name: "X-Key", Location: ParamLocationHeader, Style: "simple", Required: true}, pt)
+	pt, _ = parse("Cookie")
+	assert.Equal(t, paramTag{Name: "session", Location: ParamLocationCookie, Style: "form"}, pt)
+	pt, _ = parse("Label")
+	assert.Equal(t, paramTag{Name: "label", Location: ParamLocationPath, Style: "label", Explode: true, Required: true}, pt)
+	pt, _ = parse("Unnamed")
+	assert.Equal(t, "Unnamed", pt.Name)
+	pt, _ = parse("Body")
+	assert.Equal(t, paramTag{Name: "body", Body: true, Required: true}, pt)
+
+	_, ok := parse("Untagged")
+	assert.False(t, ok)
+	_, ok = parse("Skipped")
+	assert.False(t, ok)
+}
+
+func TestParseParamTag_Invalid(t *testing.T) {
+	for _, tag := range []string{`param:"a"`, `param:"a,in=body,style=form"`, `param:"a,in=query,explode=maybe"`, `param:"a,in=query,unknown"`} {
+		field := reflect.StructField{Name: "A", Tag: reflect.StructTag(tag)}
+		_, _, err := parseParamTag(field)
+		assert.Error(t, err, tag)
+	}
+}
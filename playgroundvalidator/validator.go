@@ -0,0 +1,35 @@
+// Package playgroundvalidator adapts github.com/go-playground/validator to
+// the runtime's validation hook, so that the constraints generated into
+// validate struct tags are enforced on every bound request body.
+package playgroundvalidator
+
+import (
+	"reflect"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/oapi-codegen/runtime"
+)
+
+// New returns a runtime.ValidatorFunc validating structs with v, or with a
+// new validator.Validate when v is nil. Values which aren't structs or
+// pointers to structs, such as the maps and slices some bodies bind to, are
+// left alone. Install it with runtime.SetValidator.
+func New(v *validator.Validate) runtime.ValidatorFunc {
+	if v == nil {
+		v = validator.New()
+	}
+	return func(value interface{}) error {
+		rv := reflect.ValueOf(value)
+		for rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return nil
+			}
+			rv = rv.Elem()
+		}
+		if rv.Kind() != reflect.Struct {
+			return nil
+		}
+		return v.Struct(value)
+	}
+}
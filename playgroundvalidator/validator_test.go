@@ -0,0 +1,36 @@
+package playgroundvalidator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oapi-codegen/runtime"
+)
+
+func TestValidator(t *testing.T) {
+	runtime.SetValidator(New(nil))
+	defer runtime.SetValidator(nil)
+
+	type Pet struct {
+		Name string `json:"name" validate:"required,max=5"`
+		Age  int    `json:"age" validate:"min=0"`
+	}
+
+	var pet Pet
+	require.NoError(t, runtime.BindJSONBody(strings.NewReader(`{"name":"rex","age":3}`), &pet))
+
+	err := runtime.BindJSONBody(strings.NewReader(`{"name":"fluffy","age":-1}`), &pet)
+	var validationErr *runtime.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	var fieldErrs validator.ValidationErrors
+	require.True(t, errors.As(err, &fieldErrs))
+	assert.Len(t, fieldErrs, 2)
+
+	var pets []Pet
+	assert.NoError(t, runtime.BindJSONBody(strings.NewReader(`[{"name":"fluffy"}]`), &pets))
+}
@@ -0,0 +1,205 @@
+package runtime
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// ProblemContentType is the media type of RFC 9457 problem details.
+const ProblemContentType = "application/problem+json"
+
+// ProblemDetails is an RFC 9457 problem details object, the
+// application/problem+json body describing an error response.
+type ProblemDetails struct {
+	// Type is a URI reference identifying the problem type. An empty Type
+	// means "about:blank".
+	Type string
+	// Title is a short, human-readable summary of the problem type.
+	Title string
+	// Status is the HTTP status code of the response.
+	Status int
+	// Detail is a human-readable explanation of this occurrence of the
+	// problem.
+	Detail string
+	// Instance is a URI reference identifying this occurrence of the
+	// problem.
+	Instance string
+	// Extensions holds the problem's extension members. Members with the
+	// name of one of the fields above are ignored when marshaling.
+	Extensions map[string]interface{}
+}
+
+// problemMembers are the members defined by RFC 9457 itself.
+type problemMembers struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+func isProblemMember(name string) bool {
+	switch name {
+	case "type", "title", "status", "detail", "instance":
+		return true
+	}
+	return false
+}
+
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	members := problemMembers{
+		Type:     p.Type,
+		Title:    p.Title,
+		Status:   p.Status,
+		Detail:   p.Detail,
+		Instance: p.Instance,
+	}
+	if len(p.Extensions) == 0 {
+		return jsonMarshal(members)
+	}
+	object := make(map[string]interface{}, len(p.Extensions)+5)
+	for name, value := range p.Extensions {
+		if !isProblemMember(name) {
+			object[name] = value
+		}
+	}
+	// Round trip the standard members through a map, so that they are
+	// omitted when empty just as without extensions.
+	standard, err := jsonMarshal(members)
+	if err != nil {
+		return nil, err
+	}
+	if err := jsonUnmarshal(standard, &object); err != nil {
+		return nil, err
+	}
+	return jsonMarshal(object)
+}
+
+func (p *ProblemDetails) UnmarshalJSON(data []byte) error {
+	var members problemMembers
+	if err := jsonUnmarshal(data, &members); err != nil {
+		return err
+	}
+	var object map[string]interface{}
+	if err := jsonUnmarshal(data, &object); err != nil {
+		return err
+	}
+	*p = ProblemDetails{
+		Type:     members.Type,
+		Title:    members.Title,
+		Status:   members.Status,
+		Detail:   members.Detail,
+		Instance: members.Instance,
+	}
+	for name, value := range object {
+		if isProblemMember(name) {
+			continue
+		}
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]interface{})
+		}
+		p.Extensions[name] = value
+	}
+	return nil
+}
+
+// Error makes a ProblemDetails usable as an error, such as one returned by
+// an ErrorDecoderFunc.
+func (p *ProblemDetails) Error() string {
+	msg := p.Title
+	if msg == "" {
+		msg = http.StatusText(p.Status)
+	}
+	if msg == "" {
+		msg = "status " + strconv.Itoa(p.Status)
+	}
+	if p.Detail != "" {
+		msg += ": " + p.Detail
+	}
+	return msg
+}
+
+// WriteProblem writes problem as an application/problem+json response, with
+// problem.Status as the status code, or 500 when it is unset.
+func WriteProblem(w http.ResponseWriter, problem *ProblemDetails) error {
+	body, err := jsonMarshal(problem)
+	if err != nil {
+		return err
+	}
+	status := problem.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(status)
+	_, err = w.Write(body)
+	return err
+}
+
+// NewProblemFromError converts an error into problem details. A
+// BindingError becomes a 400 problem whose detail is the error message, as
+// rendered by FormatBindingError, and whose "parameter" and "in" extension
+// members identify the parameter which failed to bind. Other errors caused
+// by the request become a problem with the error message as its detail: a
+// 400 for a *ValidationError, an *UnknownFieldError or a
+// *RequiredBodyError, and a 413 for a *BodyTooLargeError or a
+// *PartTooLargeError. Any other error becomes a 500 problem without detail,
+// so that internal error messages aren't disclosed to clients.
+func NewProblemFromError(err error) *ProblemDetails {
+	return NewLocalizedProblemFromError(err, "")
+}
+
+// NewLocalizedProblemFromError is NewProblemFromError with the detail of a
+// BindingError rendered in the language lang by FormatBindingError. lang
+// is typically picked with NegotiateLanguage from the request's
+// Accept-Language header.
+func NewLocalizedProblemFromError(err error, lang string) *ProblemDetails {
+	var bindingErr BindingError
+	if !errors.As(err, &bindingErr) {
+		status := requestErrorStatus(err)
+		if status == 0 {
+			return &ProblemDetails{
+				Title:  http.StatusText(http.StatusInternalServerError),
+				Status: http.StatusInternalServerError,
+			}
+		}
+		return &ProblemDetails{
+			Title:  http.StatusText(status),
+			Status: status,
+			Detail: err.Error(),
+		}
+	}
+	extensions := map[string]interface{}{
+		"parameter": bindingErr.Param(),
+	}
+	if in := bindingErr.In().String(); in != "" {
+		extensions["in"] = in
+	}
+	return &ProblemDetails{
+		Title:      http.StatusText(http.StatusBadRequest),
+		Status:     http.StatusBadRequest,
+		Detail:     FormatBindingError(bindingErr, lang),
+		Extensions: extensions,
+	}
+}
+
+// requestErrorStatus returns the status code answering err, an error of the
+// binders other than a BindingError which is caused by the request, or zero
+// if err isn't one.
+func requestErrorStatus(err error) int {
+	var (
+		validationErr   *ValidationError
+		unknownFieldErr *UnknownFieldError
+		requiredBodyErr *RequiredBodyError
+		tooLargeErr     *BodyTooLargeError
+		partTooLargeErr *PartTooLargeError
+	)
+	switch {
+	case errors.As(err, &tooLargeErr), errors.As(err, &partTooLargeErr):
+		return http.StatusRequestEntityTooLarge
+	case errors.As(err, &validationErr), errors.As(err, &unknownFieldErr), errors.As(err, &requiredBodyErr):
+		return http.StatusBadRequest
+	}
+	return 0
+}
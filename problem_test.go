@@ -0,0 +1,91 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblemDetailsJSON(t *testing.T) {
+	problem := ProblemDetails{
+		Type:   "https://example.com/probs/out-of-credit",
+		Title:  "You do not have enough credit.",
+		Status: http.StatusForbidden,
+		Extensions: map[string]interface{}{
+			"balance": 30,
+			"title":   "ignored",
+		},
+	}
+	data, err := jsonMarshal(problem)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "https://example.com/probs/out-of-credit",
+		"title": "You do not have enough credit.",
+		"status": 403,
+		"balance": 30
+	}`, string(data))
+
+	var decoded ProblemDetails
+	require.NoError(t, jsonUnmarshal(data, &decoded))
+	assert.Equal(t, problem.Type, decoded.Type)
+	assert.Equal(t, problem.Title, decoded.Title)
+	assert.Equal(t, problem.Status, decoded.Status)
+	assert.Equal(t, map[string]interface{}{"balance": float64(30)}, decoded.Extensions)
+	assert.EqualError(t, &decoded, "You do not have enough credit.")
+
+	data, err = jsonMarshal(ProblemDetails{Status: http.StatusNotFound})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"status":404}`, string(data))
+}
+
+func TestWriteProblem(t *testing.T) {
+	var id int
+	err := BindQueryParameter("form", true, true, "id", nil, &id)
+	problem := NewProblemFromError(err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "query parameter 'id' is required", problem.Detail)
+	assert.Equal(t, map[string]interface{}{"parameter": "id", "in": "query"}, problem.Extensions)
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, WriteProblem(rec, problem))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, ProblemContentType, rec.Header().Get("Content-Type"))
+
+	respErr := &ResponseError{
+		StatusCode:  rec.Code,
+		ContentType: rec.Header().Get("Content-Type"),
+		Body:        rec.Body.Bytes(),
+	}
+	decoded, ok := respErr.Problem()
+	require.True(t, ok)
+	assert.Equal(t, problem.Detail, decoded.Detail)
+	assert.Equal(t, "id", decoded.Extensions["parameter"])
+
+	internal := NewProblemFromError(errors.New("database is down"))
+	assert.Equal(t, http.StatusInternalServerError, internal.Status)
+	assert.Empty(t, internal.Detail)
+}
+
+func TestNewProblemFromError_RequestErrors(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		detail string
+	}{
+		{&ValidationError{Err: errors.New("name is empty")}, http.StatusBadRequest, "validation failed: name is empty"},
+		{fmt.Errorf("binding body: %w", &UnknownFieldError{Field: "nmae"}), http.StatusBadRequest, "binding body: unknown field 'nmae'"},
+		{&RequiredBodyError{}, http.StatusBadRequest, "request body is required"},
+		{&BodyTooLargeError{Limit: 10}, http.StatusRequestEntityTooLarge, "body exceeds the limit of 10 bytes"},
+	}
+	for _, tt := range tests {
+		problem := NewProblemFromError(tt.err)
+		assert.Equal(t, tt.status, problem.Status, tt.detail)
+		assert.Equal(t, tt.detail, problem.Detail)
+		assert.Empty(t, problem.Extensions)
+	}
+}
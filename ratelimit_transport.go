@@ -0,0 +1,65 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimitTransport is an http.RoundTripper which enforces client-side rate
+// limits before sending each request, so SDK users can respect upstream
+// quotas per endpoint. The limiter for a request is looked up by the
+// operation ID in its context, falling back to Limiter. Requests for which no
+// limiter applies are sent immediately.
+//
+// Waiting honors the request's context: a request whose context is cancelled
+// or whose deadline would expire before a token becomes available fails
+// without being sent.
+type RateLimitTransport struct {
+	// Base is the transport used to send requests. It defaults to
+	// http.DefaultTransport.
+	Base http.RoundTripper
+	// Limiter applies to operations without an entry in
+	// OperationLimiters. It may be nil.
+	Limiter *rate.Limiter
+	// OperationLimiters holds per-operation token buckets, keyed by
+	// operation ID. Several operations may share a limiter to enforce a
+	// common quota.
+	OperationLimiters map[string]*rate.Limiter
+}
+
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// RequestEditor returns a RequestEditorFn enforcing the same limits, for
+// clients whose transport can't be replaced.
+func (t *RateLimitTransport) RequestEditor() RequestEditorFn {
+	return func(ctx context.Context, req *http.Request) error {
+		return t.wait(ctx)
+	}
+}
+
+func (t *RateLimitTransport) wait(ctx context.Context) error {
+	operationID := GetOperationID(ctx)
+	limiter, ok := t.OperationLimiters[operationID]
+	if !ok {
+		limiter = t.Limiter
+	}
+	if limiter == nil {
+		return nil
+	}
+	if err := limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limit for operation '%s': %w", operationID, err)
+	}
+	return nil
+}
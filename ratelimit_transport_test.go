@@ -0,0 +1,60 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimitTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	transport := &RateLimitTransport{
+		OperationLimiters: map[string]*rate.Limiter{
+			"search": rate.NewLimiter(rate.Every(time.Hour), 1),
+		},
+	}
+	client := &http.Client{Transport: transport}
+
+	get := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+
+	search := WithOperationID(context.Background(), "search")
+	require.NoError(t, get(search))
+
+	// The bucket is empty now, and refilling it takes longer than the
+	// deadline, so the second call fails without reaching the server.
+	ctx, cancel := context.WithTimeout(search, 50*time.Millisecond)
+	defer cancel()
+	assert.Error(t, get(ctx))
+
+	// Other operations are unaffected.
+	for i := 0; i < 3; i++ {
+		require.NoError(t, get(WithOperationID(context.Background(), "listPets")))
+	}
+}
+
+func TestRateLimitTransport_RequestEditor(t *testing.T) {
+	transport := &RateLimitTransport{Limiter: rate.NewLimiter(rate.Every(time.Hour), 1)}
+	editor := transport.RequestEditor()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	require.NoError(t, editor(context.Background(), req))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Error(t, editor(ctx, req))
+}
@@ -0,0 +1,55 @@
+package runtime
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// copyOnWrite holds the state of a registry, such as a HeaderRegistry, as
+// an immutable snapshot. Readers load the current snapshot without locking,
+// so lookups on the hot path of every request never contend, while writers,
+// which usually only run during initialization, are serialized and publish
+// a modified copy. The zero value holds no snapshot.
+type copyOnWrite[S any] struct {
+	mu     sync.Mutex
+	frozen atomic.Bool
+	state  atomic.Pointer[S]
+}
+
+// load returns the current snapshot, or nil if nothing was ever written.
+// The snapshot must not be modified.
+func (c *copyOnWrite[S]) load() *S {
+	return c.state.Load()
+}
+
+// update lets modify change a shallow copy of the current snapshot, or of
+// the zero value, and publishes it. Maps and slices the copy shares with
+// the current snapshot must be cloned before being changed. registry names
+// the registry in the panic of an update after freeze.
+func (c *copyOnWrite[S]) update(registry string, modify func(next *S)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.frozen.Load() {
+		panic("runtime: " + registry + " modified after Freeze")
+	}
+	var next S
+	if current := c.state.Load(); current != nil {
+		next = *current
+	}
+	modify(&next)
+	c.state.Store(&next)
+}
+
+func (c *copyOnWrite[S]) freeze() {
+	c.frozen.Store(true)
+}
+
+// cloneMap returns a copy of m, which modifying doesn't affect m, allocated
+// even when m is nil.
+func cloneMap[K comparable, V any](m map[K]V) map[K]V {
+	c := make(map[K]V, len(m)+1)
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
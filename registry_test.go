@@ -0,0 +1,62 @@
+package runtime
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryFreeze(t *testing.T) {
+	var headers HeaderRegistry
+	headers.SetOperationHeaders("getPet", http.Header{"X-Version": {"2"}})
+	headers.Freeze()
+	assert.PanicsWithValue(t, "runtime: HeaderRegistry modified after Freeze", func() {
+		headers.SetTagHeaders("pets", http.Header{"X-Tenant": {"a"}})
+	})
+	assert.Equal(t, http.Header{"X-Version": {"2"}}, headers.Headers(OperationInfo{ID: "getPet", Tags: []string{"pets"}}))
+
+	var limits BodyLimitRegistry
+	limits.SetDefaultLimit(10)
+	limits.Freeze()
+	assert.Panics(t, func() { limits.SetOperationLimit("upload", 100) })
+	assert.Equal(t, int64(10), limits.Limit("upload"))
+
+	var decoders ErrorDecoderRegistry
+	decoders.Freeze()
+	assert.Panics(t, func() { decoders.Register(400, 499, "", nil) })
+
+	var marshalers MarshalerSelector
+	marshalers.Freeze()
+	assert.Panics(t, func() { marshalers.Register("application/json", nil) })
+}
+
+func TestRegistrySnapshots(t *testing.T) {
+	var registry HeaderRegistry
+	registry.SetOperationHeaders("getPet", http.Header{"X-Version": {"1"}})
+
+	// Returned headers are the caller's own.
+	h := registry.Headers(OperationInfo{ID: "getPet"})
+	h["X-Version"][0] = "changed"
+	assert.Equal(t, "1", registry.Headers(OperationInfo{ID: "getPet"}).Get("X-Version"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				registry.SetTagHeaders("pets", http.Header{"X-Tenant": {"a"}})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = registry.Headers(OperationInfo{ID: "getPet", Tags: []string{"pets"}})
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, "a", registry.Headers(OperationInfo{Tags: []string{"pets"}}).Get("X-Tenant"))
+}
@@ -0,0 +1,44 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+)
+
+// RequestEditorFn is the signature of the request editor callbacks accepted
+// by generated clients. The RequestEditorFn type emitted into generated code
+// has the same underlying type, so values convert freely in both directions.
+type RequestEditorFn func(ctx context.Context, req *http.Request) error
+
+// ComposeRequestEditors returns a RequestEditorFn which calls editors in the
+// order given. It stops at, and returns, the first error, so later editors
+// never see a request which an earlier one rejected. Nil editors are skipped.
+func ComposeRequestEditors(editors ...RequestEditorFn) RequestEditorFn {
+	return func(ctx context.Context, req *http.Request) error {
+		for _, editor := range editors {
+			if editor == nil {
+				continue
+			}
+			if err := editor(ctx, req); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// RequestEditorForOperations returns a RequestEditorFn which calls editor
+// only when the operation ID in ctx, as set by WithOperationID or
+// WithOperationInfo, is one of operationIDs.
+func RequestEditorForOperations(editor RequestEditorFn, operationIDs ...string) RequestEditorFn {
+	set := make(map[string]struct{}, len(operationIDs))
+	for _, id := range operationIDs {
+		set[id] = struct{}{}
+	}
+	return func(ctx context.Context, req *http.Request) error {
+		if _, ok := set[GetOperationID(ctx)]; !ok {
+			return nil
+		}
+		return editor(ctx, req)
+	}
+}
@@ -0,0 +1,54 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComposeRequestEditors(t *testing.T) {
+	var calls []string
+	editor := func(name string, err error) RequestEditorFn {
+		return func(ctx context.Context, req *http.Request) error {
+			calls = append(calls, name)
+			req.Header.Add("X-Editors", name)
+			return err
+		}
+	}
+
+	t.Run("runs in order", func(t *testing.T) {
+		calls = nil
+		req := httptest.NewRequest(http.MethodGet, "/pets", nil)
+		err := ComposeRequestEditors(editor("a", nil), nil, editor("b", nil))(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, calls)
+		assert.Equal(t, []string{"a", "b"}, req.Header.Values("X-Editors"))
+	})
+
+	t.Run("stops at first error", func(t *testing.T) {
+		calls = nil
+		failure := errors.New("no credentials")
+		req := httptest.NewRequest(http.MethodGet, "/pets", nil)
+		err := ComposeRequestEditors(editor("a", failure), editor("b", nil))(context.Background(), req)
+		assert.ErrorIs(t, err, failure)
+		assert.Equal(t, []string{"a"}, calls)
+	})
+}
+
+func TestRequestEditorForOperations(t *testing.T) {
+	var applied []string
+	editor := RequestEditorForOperations(func(ctx context.Context, req *http.Request) error {
+		applied = append(applied, GetOperationID(ctx))
+		return nil
+	}, "createPet", "deletePet")
+
+	req := httptest.NewRequest(http.MethodGet, "/pets", nil)
+	for _, op := range []string{"createPet", "listPets", "deletePet", ""} {
+		assert.NoError(t, editor(WithOperationID(context.Background(), op), req))
+	}
+	assert.Equal(t, []string{"createPet", "deletePet"}, applied)
+}
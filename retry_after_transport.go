@@ -0,0 +1,137 @@
+package runtime
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRetryAfterMaxRetries = 1
+	defaultRetryAfterMaxDelay   = time.Minute
+)
+
+// RetryAfterTransport is an http.RoundTripper which honors the Retry-After
+// header of 429 Too Many Requests and 503 Service Unavailable responses, in
+// both its seconds and HTTP-date forms. It waits for as long as the server
+// asks before retrying, and holds back every other request sent through it
+// until then, so that a client doesn't keep hitting an API which has told it
+// to slow down.
+//
+// It complements RetryTransport, whose backoff doesn't look at responses:
+// wrap a RetryTransport in a RetryAfterTransport to use both. Responses
+// without a usable Retry-After are returned as they are.
+type RetryAfterTransport struct {
+	// Base is the transport used to send requests. It defaults to
+	// http.DefaultTransport.
+	Base http.RoundTripper
+	// MaxRetries is the number of times a request is retried after a
+	// Retry-After. It defaults to 1.
+	MaxRetries int
+	// MaxDelay is the longest delay honored. Responses asking for longer
+	// are returned to the caller. It defaults to one minute.
+	MaxDelay time.Duration
+	// ExcludedOperations lists operation IDs, as recorded in the request
+	// context through WithOperationID or WithOperationInfo, whose requests
+	// are never delayed or retried.
+	ExcludedOperations map[string]bool
+
+	mu    sync.Mutex
+	until time.Time
+}
+
+func (t *RetryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if t.ExcludedOperations[GetOperationID(req.Context())] {
+		return base.RoundTrip(req)
+	}
+	maxRetries := t.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultRetryAfterMaxRetries
+	}
+	maxDelay := t.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultRetryAfterMaxDelay
+	}
+
+	ctx := req.Context()
+	for retry := 0; ; retry++ {
+		if err := t.wait(ctx); err != nil {
+			return nil, err
+		}
+		attemptReq := req
+		if retry > 0 {
+			attemptReq = req.Clone(ctx)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				attemptReq.Body = body
+			}
+		}
+		resp, err := base.RoundTrip(attemptReq)
+		if err != nil {
+			return resp, err
+		}
+		delay, ok := retryAfter(resp)
+		if !ok || delay > maxDelay {
+			return resp, nil
+		}
+		t.block(delay)
+		if retry >= maxRetries || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+			return resp, nil
+		}
+		// Drain the body so the connection can be reused.
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+}
+
+// RequestEditor returns a RequestEditorFn which holds requests back while a
+// Retry-After seen by the transport is in effect, for requests sent by
+// clients which don't use the transport.
+func (t *RetryAfterTransport) RequestEditor() RequestEditorFn {
+	return func(ctx context.Context, req *http.Request) error {
+		if t.ExcludedOperations[GetOperationID(ctx)] {
+			return nil
+		}
+		return t.wait(ctx)
+	}
+}
+
+// block holds requests back for d, unless they already are for longer.
+func (t *RetryAfterTransport) block(d time.Duration) {
+	until := time.Now().Add(d)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if until.After(t.until) {
+		t.until = until
+	}
+}
+
+// wait returns once requests are no longer held back, or ctx is done.
+func (t *RetryAfterTransport) wait(ctx context.Context) error {
+	t.mu.Lock()
+	d := time.Until(t.until)
+	t.mu.Unlock()
+	if d <= 0 {
+		return nil
+	}
+	return sleepContext(ctx, d)
+}
+
+// retryAfter returns the delay a 429 or 503 response asks for in its
+// Retry-After header.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	}
+	return 0, false
+}
@@ -0,0 +1,86 @@
+package runtime
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// retryAfterServer answers the first request with 429 and the given
+// Retry-After, and echoes the body of later ones.
+func retryAfterServer(t *testing.T, retryAfter string) (*httptest.Server, *int32) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &attempts
+}
+
+func TestRetryAfterTransport(t *testing.T) {
+	t.Run("retries after the delay", func(t *testing.T) {
+		srv, attempts := retryAfterServer(t, "0")
+		client := &http.Client{Transport: &RetryAfterTransport{}}
+		resp, err := client.Post(srv.URL, "text/plain", strings.NewReader("hello"))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "hello", string(body))
+		assert.EqualValues(t, 2, atomic.LoadInt32(attempts))
+	})
+
+	t.Run("returns delays beyond MaxDelay", func(t *testing.T) {
+		srv, attempts := retryAfterServer(t, "3600")
+		client := &http.Client{Transport: &RetryAfterTransport{MaxDelay: time.Second}}
+		resp, err := client.Get(srv.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.EqualValues(t, 1, atomic.LoadInt32(attempts))
+	})
+
+	t.Run("skips excluded operations", func(t *testing.T) {
+		srv, attempts := retryAfterServer(t, "0")
+		client := &http.Client{Transport: &RetryAfterTransport{
+			ExcludedOperations: map[string]bool{"listPets": true},
+		}}
+		req, err := http.NewRequestWithContext(WithOperationID(context.Background(), "listPets"), http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.EqualValues(t, 1, atomic.LoadInt32(attempts))
+	})
+}
+
+func TestRetryAfterTransport_RequestEditor(t *testing.T) {
+	transport := &RetryAfterTransport{}
+	editor := transport.RequestEditor()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	require.NoError(t, editor(context.Background(), req))
+
+	transport.block(time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, editor(ctx, req), context.DeadlineExceeded)
+
+	excluded := WithOperationID(context.Background(), "health")
+	transport.ExcludedOperations = map[string]bool{"health": true}
+	assert.NoError(t, editor(excluded, req))
+}
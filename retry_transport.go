@@ -0,0 +1,174 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultRetryInitialBackoff = 100 * time.Millisecond
+	defaultRetryMaxBackoff     = 10 * time.Second
+)
+
+// RetryPolicy configures how RetryTransport retries requests.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry, doubling for each
+	// retry after that. It defaults to 100ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts. It defaults to 10s.
+	MaxBackoff time.Duration
+	// Jitter is the fraction, between 0 and 1, of each delay which is
+	// randomized, so that many clients failing at once don't retry in
+	// lockstep.
+	Jitter float64
+	// RetryNonIdempotent allows retrying methods such as POST and PATCH,
+	// which are otherwise only attempted once unless they carry an
+	// Idempotency-Key header. Only enable it for operations which are safe
+	// to repeat.
+	RetryNonIdempotent bool
+	// ShouldRetry decides whether an attempt which produced resp or err is
+	// retried. It defaults to DefaultShouldRetry.
+	ShouldRetry func(resp *http.Response, err error) bool
+}
+
+// DefaultShouldRetry retries transport errors, other than the request's own
+// context being cancelled, and 429, 502, 503 and 504 responses.
+func DefaultShouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns the delay to wait before the given retry, where retry 1 is
+// the first retry.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	initial := p.InitialBackoff
+	if initial <= 0 {
+		initial = defaultRetryInitialBackoff
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRetryMaxBackoff
+	}
+	d := initial
+	for i := 1; i < retry && d < maxBackoff; i++ {
+		d *= 2
+	}
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+	if p.Jitter > 0 {
+		d -= time.Duration(rand.Float64() * p.Jitter * float64(d))
+	}
+	return d
+}
+
+// RetryTransport is an http.RoundTripper which retries failed requests with
+// exponential backoff. The policy for each request is looked up by the
+// operation ID in its context, as recorded by generated clients through
+// WithOperationID or WithOperationInfo, falling back to Policy.
+//
+// Requests with a body are only retried when http.Request.GetBody is set,
+// which is the case for the bodies generated clients build.
+type RetryTransport struct {
+	// Base is the transport used for each attempt. It defaults to
+	// http.DefaultTransport.
+	Base http.RoundTripper
+	// Policy applies to operations without an entry in OperationPolicies.
+	Policy RetryPolicy
+	// OperationPolicies holds per-operation overrides, keyed by operation
+	// ID.
+	OperationPolicies map[string]RetryPolicy
+}
+
+func (t *RetryTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *RetryTransport) policy(req *http.Request) RetryPolicy {
+	if p, ok := t.OperationPolicies[GetOperationID(req.Context())]; ok {
+		return p
+	}
+	return t.Policy
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	policy := t.policy(req)
+	if policy.MaxAttempts < 2 || !isRetryable(req, policy) {
+		return t.base().RoundTrip(req)
+	}
+	shouldRetry := policy.ShouldRetry
+	if shouldRetry == nil {
+		shouldRetry = DefaultShouldRetry
+	}
+
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		attemptReq := req
+		if attempt > 1 {
+			attemptReq = req.Clone(ctx)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				attemptReq.Body = body
+			}
+		}
+		resp, err := t.base().RoundTrip(attemptReq)
+		if attempt >= policy.MaxAttempts || !shouldRetry(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			// Drain the body so the connection can be reused.
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		if err := sleepContext(ctx, policy.backoff(attempt)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isRetryable reports whether req may be sent more than once under policy.
+func isRetryable(req *http.Request, policy RetryPolicy) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if policy.RetryNonIdempotent || req.Header.Get(IdempotencyKeyHeader) != "" {
+		return true
+	}
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// sleepContext waits for d, returning early with the context's error if ctx
+// is done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
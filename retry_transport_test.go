@@ -0,0 +1,98 @@
+package runtime
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func flakyServer(t *testing.T, failures int32) (*httptest.Server, *int32) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&attempts, 1)
+		body, _ := io.ReadAll(r.Body)
+		if n <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &attempts
+}
+
+func TestRetryTransport(t *testing.T) {
+	fast := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	t.Run("retries until success", func(t *testing.T) {
+		srv, attempts := flakyServer(t, 2)
+		client := &http.Client{Transport: &RetryTransport{Policy: fast}}
+		resp, err := client.Get(srv.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int32(3), atomic.LoadInt32(attempts))
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		srv, attempts := flakyServer(t, 5)
+		client := &http.Client{Transport: &RetryTransport{Policy: fast}}
+		resp, err := client.Get(srv.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, int32(3), atomic.LoadInt32(attempts))
+	})
+
+	t.Run("does not retry non-idempotent methods", func(t *testing.T) {
+		srv, attempts := flakyServer(t, 1)
+		client := &http.Client{Transport: &RetryTransport{Policy: fast}}
+		resp, err := client.Post(srv.URL, "text/plain", strings.NewReader("hi"))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, int32(1), atomic.LoadInt32(attempts))
+	})
+
+	t.Run("per-operation policy replays the body", func(t *testing.T) {
+		srv, attempts := flakyServer(t, 1)
+		transport := &RetryTransport{
+			OperationPolicies: map[string]RetryPolicy{
+				"createPet": {MaxAttempts: 2, InitialBackoff: time.Millisecond, RetryNonIdempotent: true},
+			},
+		}
+		ctx := WithOperationID(context.Background(), "createPet")
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL, strings.NewReader(`{"name":"rex"}`))
+		require.NoError(t, err)
+		resp, err := (&http.Client{Transport: transport}).Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, `{"name":"rex"}`, string(body))
+		assert.Equal(t, int32(2), atomic.LoadInt32(attempts))
+	})
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	assert.Equal(t, time.Second, p.backoff(1))
+	assert.Equal(t, 2*time.Second, p.backoff(2))
+	assert.Equal(t, 4*time.Second, p.backoff(3))
+	assert.Equal(t, 5*time.Second, p.backoff(4))
+
+	p.Jitter = 0.5
+	for i := 0; i < 10; i++ {
+		d := p.backoff(1)
+		assert.True(t, d > 500*time.Millisecond && d <= time.Second, d)
+	}
+}
@@ -0,0 +1,90 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// SecurityRequirement is one entry of an operation's security list in the
+// spec: it maps security scheme names to the scopes required from each. All
+// schemes of a requirement must be satisfied together, while the entries of
+// an operation's list are alternatives. An empty requirement means the
+// operation may be called anonymously.
+type SecurityRequirement map[string][]string
+
+// WithSecurityRequirements returns a copy of ctx recording the security
+// requirements of the operation being called.
+func WithSecurityRequirements(ctx context.Context, requirements []SecurityRequirement) context.Context {
+	return context.WithValue(ctx, securityRequirementsContextKey, requirements)
+}
+
+// GetSecurityRequirements returns the security requirements stored in ctx,
+// and whether there were any recorded.
+func GetSecurityRequirements(ctx context.Context) ([]SecurityRequirement, bool) {
+	requirements, ok := ctx.Value(securityRequirementsContextKey).([]SecurityRequirement)
+	return requirements, ok
+}
+
+// SecurityProvider authenticates req for a single security scheme, for
+// example by setting an API key header or a bearer token obtained for the
+// given OAuth scopes.
+type SecurityProvider func(ctx context.Context, req *http.Request, scopes []string) error
+
+// SecurityRequestEditor returns a RequestEditorFn which authenticates each
+// request according to the security requirements in its context. It applies
+// the first requirement, in spec order, for which every scheme has a
+// provider; an empty requirement is satisfied without authenticating. If no
+// requirement can be satisfied, the request fails. Requests without recorded
+// requirements are left alone.
+func SecurityRequestEditor(providers map[string]SecurityProvider) RequestEditorFn {
+	return func(ctx context.Context, req *http.Request) error {
+		requirements, ok := GetSecurityRequirements(ctx)
+		if !ok || len(requirements) == 0 {
+			return nil
+		}
+		for _, requirement := range requirements {
+			if !canSatisfy(requirement, providers) {
+				continue
+			}
+			// Apply schemes in a stable order.
+			schemes := make([]string, 0, len(requirement))
+			for scheme := range requirement {
+				schemes = append(schemes, scheme)
+			}
+			sort.Strings(schemes)
+			for _, scheme := range schemes {
+				if err := providers[scheme](ctx, req, requirement[scheme]); err != nil {
+					return fmt.Errorf("error applying security scheme '%s': %w", scheme, err)
+				}
+			}
+			return nil
+		}
+		return fmt.Errorf("no provider for any of the security requirements of operation '%s': %s",
+			GetOperationID(ctx), describeRequirements(requirements))
+	}
+}
+
+func canSatisfy(requirement SecurityRequirement, providers map[string]SecurityProvider) bool {
+	for scheme := range requirement {
+		if providers[scheme] == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func describeRequirements(requirements []SecurityRequirement) string {
+	alternatives := make([]string, len(requirements))
+	for i, requirement := range requirements {
+		schemes := make([]string, 0, len(requirement))
+		for scheme := range requirement {
+			schemes = append(schemes, scheme)
+		}
+		sort.Strings(schemes)
+		alternatives[i] = "[" + strings.Join(schemes, " and ") + "]"
+	}
+	return strings.Join(alternatives, " or ")
+}
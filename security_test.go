@@ -0,0 +1,55 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecurityRequestEditor(t *testing.T) {
+	editor := SecurityRequestEditor(map[string]SecurityProvider{
+		"apiKey": func(ctx context.Context, req *http.Request, scopes []string) error {
+			req.Header.Set("X-Api-Key", "secret")
+			return nil
+		},
+		"oauth": func(ctx context.Context, req *http.Request, scopes []string) error {
+			req.Header.Set("Authorization", "Bearer token-for-"+strings.Join(scopes, "+"))
+			return nil
+		},
+	})
+	apply := func(requirements []SecurityRequirement) (http.Header, error) {
+		ctx := WithOperationID(context.Background(), "op")
+		if requirements != nil {
+			ctx = WithSecurityRequirements(ctx, requirements)
+		}
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		err := editor(ctx, req)
+		return req.Header, err
+	}
+
+	h, err := apply([]SecurityRequirement{{"basic": nil}, {"oauth": {"pets:read", "pets:write"}}})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token-for-pets:read+pets:write", h.Get("Authorization"))
+	assert.Empty(t, h.Get("X-Api-Key"))
+
+	h, err = apply([]SecurityRequirement{{"apiKey": nil, "oauth": {"admin"}}})
+	require.NoError(t, err)
+	assert.Equal(t, "secret", h.Get("X-Api-Key"))
+	assert.Equal(t, "Bearer token-for-admin", h.Get("Authorization"))
+
+	h, err = apply([]SecurityRequirement{{"basic": nil}, {}})
+	require.NoError(t, err)
+	assert.Empty(t, h)
+
+	h, err = apply(nil)
+	require.NoError(t, err)
+	assert.Empty(t, h)
+
+	_, err = apply([]SecurityRequirement{{"basic": nil}, {"mtls": nil, "apiKey": nil}})
+	assert.EqualError(t, err, "no provider for any of the security requirements of operation 'op': [basic] or [apiKey and mtls]")
+}
@@ -0,0 +1,61 @@
+package runtime
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// JoinServerURL appends pathTemplate, such as "/pets/{petId}" or an already
+// substituted "/pets/42", to the server URL base. Unlike resolving the path
+// as a URL reference, any path prefix of base is kept, whether or not it ends
+// in a slash, so "https://api.example.com/v1" joined with "/pets" yields
+// "https://api.example.com/v1/pets". Duplicate slashes at the seam and within
+// either part are collapsed. pathTemplate is expected to be escaped already,
+// and may carry a query string.
+//
+// base must be an absolute http or https URL.
+func JoinServerURL(base string, pathTemplate string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("invalid server URL '%s': %w", base, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("server URL '%s' must use the http or https scheme", base)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("server URL '%s' has no host", base)
+	}
+
+	path, query, hasQuery := strings.Cut(pathTemplate, "?")
+	joined := u.EscapedPath()
+	if path != "" {
+		joined = strings.TrimRight(joined, "/") + "/" + strings.TrimLeft(path, "/")
+	}
+	ref, err := url.Parse(collapseSlashes(joined))
+	if err != nil {
+		return "", fmt.Errorf("invalid path '%s': %w", pathTemplate, err)
+	}
+	u.Path = ref.Path
+	u.RawPath = ref.RawPath
+	if hasQuery {
+		u.RawQuery = query
+	}
+	return u.String(), nil
+}
+
+// collapseSlashes replaces runs of slashes in path with a single one.
+func collapseSlashes(path string) string {
+	if !strings.Contains(path, "//") {
+		return path
+	}
+	var b strings.Builder
+	b.Grow(len(path))
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' && i > 0 && path[i-1] == '/' {
+			continue
+		}
+		b.WriteByte(path[i])
+	}
+	return b.String()
+}
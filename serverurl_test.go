@@ -0,0 +1,38 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoinServerURL(t *testing.T) {
+	tests := []struct {
+		base     string
+		path     string
+		expected string
+	}{
+		{"https://api.example.com", "/pets", "https://api.example.com/pets"},
+		{"https://api.example.com/", "/pets", "https://api.example.com/pets"},
+		{"https://api.example.com/v1", "/pets", "https://api.example.com/v1/pets"},
+		{"https://api.example.com/v1/", "/pets", "https://api.example.com/v1/pets"},
+		{"https://api.example.com/v1//", "//pets//42", "https://api.example.com/v1/pets/42"},
+		{"https://api.example.com/v1", "pets/", "https://api.example.com/v1/pets/"},
+		{"https://api.example.com/v1", "", "https://api.example.com/v1"},
+		{"http://localhost:8080/api", "/owners/jo%2Fann", "http://localhost:8080/api/owners/jo%2Fann"},
+		{"https://api.example.com/v1", "/pets?limit=10", "https://api.example.com/v1/pets?limit=10"},
+	}
+	for _, tc := range tests {
+		actual, err := JoinServerURL(tc.base, tc.path)
+		require.NoError(t, err, tc.base+" + "+tc.path)
+		assert.Equal(t, tc.expected, actual, tc.base+" + "+tc.path)
+	}
+}
+
+func TestJoinServerURL_Invalid(t *testing.T) {
+	for _, base := range []string{"api.example.com/v1", "ftp://example.com", "https://", "://bad"} {
+		_, err := JoinServerURL(base, "/pets")
+		assert.Error(t, err, base)
+	}
+}
@@ -0,0 +1,29 @@
+package runtime
+
+import (
+	"sync/atomic"
+
+	"github.com/oapi-codegen/runtime/types"
+)
+
+var settingsFrozen atomic.Bool
+
+// FreezeSettings makes the package-wide settings read-only, as Freeze does
+// for a registry, so that changing them after startup, which would affect
+// requests already being served, panics rather than takes effect. It
+// covers SetJSONCodec, SetLimits, SetValidator, SetParamInferrer,
+// SetBindingErrorFormatter, SetBindFailureHook, SetBindTraceHook,
+// SetDisallowUnknownFields and SetUseJSONNumber, and freezes the settings
+// of package types with types.FreezeSettings.
+func FreezeSettings() {
+	settingsFrozen.Store(true)
+	types.FreezeSettings()
+}
+
+// checkSettingsFrozen panics if FreezeSettings was called, naming the
+// setter called after it.
+func checkSettingsFrozen(setter string) {
+	if settingsFrozen.Load() {
+		panic("runtime: " + setter + " called after FreezeSettings")
+	}
+}
@@ -0,0 +1,30 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFreezeSettings(t *testing.T) {
+	// FreezeSettings also freezes package types, which can't be undone, so
+	// only the flag it sets here is set.
+	settingsFrozen.Store(true)
+	defer settingsFrozen.Store(false)
+
+	assert.PanicsWithValue(t, "runtime: SetLimits called after FreezeSettings", func() { SetLimits(Limits{}) })
+	for name, set := range map[string]func(){
+		"SetJSONCodec":             func() { SetJSONCodec(nil) },
+		"SetValidator":             func() { SetValidator(nil) },
+		"SetParamInferrer":         func() { SetParamInferrer(nil) },
+		"SetBindingErrorFormatter": func() { SetBindingErrorFormatter(nil) },
+		"SetBindFailureHook":       func() { SetBindFailureHook(nil) },
+		"SetBindTraceHook":         func() { SetBindTraceHook(nil) },
+		"SetDisallowUnknownFields": func() { SetDisallowUnknownFields(true) },
+		"SetUseJSONNumber":         func() { SetUseJSONNumber(true) },
+	} {
+		assert.Panics(t, set, name)
+	}
+	assert.Equal(t, DefaultLimits(), GetLimits())
+	assert.False(t, disallowUnknownFields.Load())
+}
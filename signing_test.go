@@ -0,0 +1,65 @@
+package runtime
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCanonicalRequest(t *testing.T) {
+	newRequest := func(rawQuery string, body io.Reader) *http.Request {
+		req, err := http.NewRequest(http.MethodPost, "https://api.example.com/pets?"+rawQuery, body)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Add("X-Tags", "  a   b ")
+		req.Header.Add("X-Tags", "c")
+		return req
+	}
+
+	req := newRequest("limit=10&tag=b&tag=a", strings.NewReader(`{"name":"Rex"}`))
+	canonical, err := NewCanonicalRequest(req, []string{"X-Tags", "Host", "content-type"})
+	require.NoError(t, err)
+	sum := sha256.Sum256([]byte(`{"name":"Rex"}`))
+	assert.Equal(t, strings.Join([]string{
+		"POST",
+		"/pets",
+		"limit=10&tag=a&tag=b",
+		"content-type:application/json\nhost:api.example.com\nx-tags:a b,c\n",
+		"content-type;host;x-tags",
+		hex.EncodeToString(sum[:]),
+	}, "\n"), canonical.String())
+	assert.Equal(t, []string{"  a   b ", "c"}, req.Header["X-Tags"], "the request's headers are left untouched")
+
+	// Reordering the query doesn't change the canonical request, and a body
+	// without GetBody can still be sent afterwards.
+	req = newRequest("tag=b&limit=10&tag=a", io.NopCloser(strings.NewReader(`{"name":"Rex"}`)))
+	other, err := NewCanonicalRequest(req, []string{"host", "x-tags", "Content-Type"})
+	require.NoError(t, err)
+	assert.Equal(t, canonical.String(), other.String())
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"Rex"}`, string(body))
+}
+
+func TestSigningRequestEditor(t *testing.T) {
+	secret := []byte("secret")
+	editor := SigningRequestEditor(HMACSigner{KeyID: "key-1", Secret: secret}, "host")
+	req, err := http.NewRequest(http.MethodGet, "https://api.example.com/pets?b=2&a=1", nil)
+	require.NoError(t, err)
+	require.NoError(t, editor(context.Background(), req))
+
+	canonical, err := NewCanonicalRequest(req, []string{"host"})
+	require.NoError(t, err)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(canonical.String()))
+	assert.Equal(t, "HMAC-SHA256 KeyId=key-1, SignedHeaders=host, Signature="+hex.EncodeToString(mac.Sum(nil)),
+		req.Header.Get("Authorization"))
+}
@@ -0,0 +1,105 @@
+package runtime
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SSEEvent is an event of a Server-Sent Events (text/event-stream) stream.
+type SSEEvent struct {
+	// ID is the last event ID the stream set, as of this event. A client
+	// reconnecting sends it back in the Last-Event-ID header.
+	ID string
+	// Event is the event type, or empty for the default type, "message".
+	Event string
+	// Data is the event's data, with the lines of multi-line data joined
+	// by newlines.
+	Data string
+}
+
+// Unmarshal decodes the event's data as JSON into v.
+func (e SSEEvent) Unmarshal(v interface{}) error {
+	return jsonUnmarshal([]byte(e.Data), v)
+}
+
+// SSEDecoder reads the events of a Server-Sent Events stream, such as the
+// body of a response to an operation declared as text/event-stream, one at
+// a time, following the parsing rules of the HTML specification: comments
+// are skipped, and events without data aren't dispatched.
+type SSEDecoder struct {
+	r           *bufio.Reader
+	started     bool
+	lastEventID string
+	retry       time.Duration
+}
+
+// NewSSEDecoder returns a decoder reading events from r.
+func NewSSEDecoder(r io.Reader) *SSEDecoder {
+	return &SSEDecoder{r: bufio.NewReader(r)}
+}
+
+// Retry returns the reconnection delay the stream last set, or zero if it
+// set none.
+func (d *SSEDecoder) Retry() time.Duration {
+	return d.retry
+}
+
+// Decode returns the next event in the stream. At the end of the stream it
+// returns io.EOF; an event left incomplete by the end of the stream is
+// discarded.
+func (d *SSEDecoder) Decode() (SSEEvent, error) {
+	var event SSEEvent
+	var data strings.Builder
+	var hasData bool
+	for {
+		line, err := d.r.ReadString('\n')
+		if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+			return SSEEvent{}, err
+		}
+		if !d.started {
+			d.started = true
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		if errors.Is(err, io.EOF) {
+			// The last line isn't terminated, so the event it belongs to
+			// can't be complete.
+			return SSEEvent{}, io.EOF
+		}
+
+		if line == "" {
+			if !hasData {
+				event.Event = ""
+				continue
+			}
+			event.ID = d.lastEventID
+			event.Data = strings.TrimSuffix(data.String(), "\n")
+			return event, nil
+		}
+		if line[0] == ':' {
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.Event = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				d.lastEventID = value
+			}
+		case "retry":
+			if ms, err := strconv.ParseUint(value, 10, 63); err == nil {
+				d.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
@@ -0,0 +1,31 @@
+//go:build go1.23
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+)
+
+// All returns an iterator over the remaining events in the stream. It stops
+// at the end of the stream, or after yielding the first error. Once ctx is
+// done, it yields ctx.Err() as its last error.
+func (d *SSEDecoder) All(ctx context.Context) iter.Seq2[SSEEvent, error] {
+	return func(yield func(SSEEvent, error) bool) {
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(SSEEvent{}, err)
+				return
+			}
+			event, err := d.Decode()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if !yield(event, err) || err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+//go:build go1.23
+
+package runtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSEDecoder_All(t *testing.T) {
+	dec := NewSSEDecoder(strings.NewReader("data: 1\n\nevent: ping\ndata: 2\n\n"))
+
+	var events []SSEEvent
+	for event, err := range dec.All(context.Background()) {
+		require.NoError(t, err)
+		events = append(events, event)
+	}
+	assert.Equal(t, []SSEEvent{{Data: "1"}, {Event: "ping", Data: "2"}}, events)
+}
+
+func TestSSEDecoder_AllCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dec := NewSSEDecoder(strings.NewReader("data: 1\n\ndata: 2\n\n"))
+
+	var data []string
+	var lastErr error
+	for event, err := range dec.All(ctx) {
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		data = append(data, event.Data)
+		cancel()
+	}
+	assert.Equal(t, []string{"1"}, data)
+	assert.ErrorIs(t, lastErr, context.Canceled)
+}
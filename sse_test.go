@@ -0,0 +1,64 @@
+package runtime
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSEDecoder(t *testing.T) {
+	stream := "\ufeff: heartbeat\r\n" +
+		"retry: 3000\n" +
+		"\n" +
+		"id: 1\n" +
+		"data: {\"id\":1}\n" +
+		"\n" +
+		"event: update\n" +
+		"data:first\n" +
+		"data:  second\n" +
+		"\n" +
+		"id: 2\u0000\n" +
+		"data\n" +
+		"\n" +
+		"data: incomplete"
+	dec := NewSSEDecoder(strings.NewReader(stream))
+
+	event, err := dec.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, SSEEvent{ID: "1", Data: `{"id":1}`}, event)
+	var record ndjsonRecord
+	require.NoError(t, event.Unmarshal(&record))
+	assert.Equal(t, 1, record.ID)
+	assert.Equal(t, 3*time.Second, dec.Retry())
+
+	event, err = dec.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, SSEEvent{ID: "1", Event: "update", Data: "first\n second"}, event)
+
+	// An id containing NUL is ignored, and a data line without a colon
+	// holds empty data.
+	event, err = dec.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, SSEEvent{ID: "1"}, event)
+
+	_, err = dec.Decode()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestSSEDecoder_ReadError(t *testing.T) {
+	readErr := errors.New("connection reset")
+	dec := NewSSEDecoder(io.MultiReader(strings.NewReader("data: 1\n\n"), iotest.ErrReader(readErr)))
+
+	event, err := dec.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, "1", event.Data)
+
+	_, err = dec.Decode()
+	assert.ErrorIs(t, err, readErr)
+}
@@ -0,0 +1,68 @@
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recordingMiddleware(name string, calls *[]string) StrictEchoMiddlewareFunc {
+	return func(f StrictEchoHandlerFunc, operationID string) StrictEchoHandlerFunc {
+		return func(ctx echo.Context, request interface{}) (interface{}, error) {
+			*calls = append(*calls, name+":"+operationID)
+			return f(ctx, request)
+		}
+	}
+}
+
+func TestChainStrictMiddlewares(t *testing.T) {
+	var calls []string
+	var names []string
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		calls = append(calls, "handler")
+		names = MiddlewareNames(ctx)
+		return "ok", nil
+	}
+
+	chain := ChainStrictMiddlewares(
+		Named("first", recordingMiddleware("first", &calls)),
+		Named("second", recordingMiddleware("second", &calls)),
+		recordingMiddleware("third", &calls),
+	)
+
+	e := echo.New()
+	ctx := e.NewContext(httptest.NewRequest(http.MethodGet, "/pets", nil), httptest.NewRecorder())
+	response, err := chain(handler, "findPets")(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", response)
+	assert.Equal(t, []string{"first:findPets", "second:findPets", "third:findPets", "handler"}, calls)
+	assert.Equal(t, []string{"first", "second"}, names)
+}
+
+func TestForOperations(t *testing.T) {
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	e := echo.New()
+	ctx := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	var calls []string
+	only := ForOperations(recordingMiddleware("auth", &calls), "createPet", "deletePet")
+	for _, op := range []string{"createPet", "healthz", "deletePet"} {
+		_, err := only(handler, op)(ctx, nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"auth:createPet", "auth:deletePet"}, calls)
+
+	calls = nil
+	except := ExceptOperations(recordingMiddleware("auth", &calls), "healthz")
+	for _, op := range []string{"createPet", "healthz", "deletePet"} {
+		_, err := except(handler, op)(ctx, nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"auth:createPet", "auth:deletePet"}, calls)
+}
@@ -0,0 +1,33 @@
+//go:build go1.21
+
+package echo
+
+import (
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+	"github.com/oapi-codegen/runtime"
+)
+
+// LoggerMiddleware returns a StrictEchoMiddlewareFunc which stores base,
+// with the operation ID, method and route path attached, in the context of
+// the request, where handlers retrieve it with runtime.LoggerFrom. A nil
+// base stands for slog.Default().
+func LoggerMiddleware(base *slog.Logger) StrictEchoMiddlewareFunc {
+	return func(f StrictEchoHandlerFunc, operationID string) StrictEchoHandlerFunc {
+		return func(ctx echo.Context, request interface{}) (interface{}, error) {
+			logger := base
+			if logger == nil {
+				logger = slog.Default()
+			}
+			r := ctx.Request()
+			logger = logger.With(
+				slog.String("operation_id", operationID),
+				slog.String("method", r.Method),
+				slog.String("path_template", ctx.Path()),
+			)
+			ctx.SetRequest(r.WithContext(runtime.WithLogger(r.Context(), logger)))
+			return f(ctx, request)
+		}
+	}
+}
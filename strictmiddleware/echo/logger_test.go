@@ -0,0 +1,34 @@
+//go:build go1.21
+
+package echo
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/oapi-codegen/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	base := slog.New(slog.NewTextHandler(&buf, nil))
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		runtime.LoggerFrom(ctx.Request().Context()).Info("handled")
+		return nil, nil
+	}
+
+	e := echo.New()
+	ctx := e.NewContext(httptest.NewRequest(http.MethodGet, "/pets/5", nil), httptest.NewRecorder())
+	ctx.SetPath("/pets/:petId")
+	_, err := LoggerMiddleware(base)(handler, "getPet")(ctx, nil)
+	assert.NoError(t, err)
+
+	assert.Contains(t, buf.String(), "operation_id=getPet")
+	assert.Contains(t, buf.String(), "method=GET")
+	assert.Contains(t, buf.String(), "path_template=/pets/:petId")
+}
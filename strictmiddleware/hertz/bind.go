@@ -0,0 +1,85 @@
+package hertz
+
+import (
+	"net/url"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/oapi-codegen/runtime"
+)
+
+// Hertz hands out parameters as byte slices backed by its request buffers,
+// which are reused once the handler returns. The shims below copy them into
+// strings before binding, so that nothing bound into dest aliases them.
+
+// BindPathParameter binds the path parameter name of c into dest.
+func BindPathParameter(c *app.RequestContext, style string, explode bool, name string, dest interface{}) error {
+	// Hertz hands out path parameters unescaped, while the runtime unescapes
+	// them itself, so the value is escaped again for a % to survive.
+	return runtime.BindStyledParameterWithOptions(style, name, url.PathEscape(c.Param(name)), dest, runtime.BindStyledParameterOptions{
+		ParamLocation: runtime.ParamLocationPath,
+		Explode:       explode,
+		Required:      true,
+	})
+}
+
+// BindQueryParameter binds the query parameter name of c into dest, in the
+// same way as runtime.BindQueryParameter.
+func BindQueryParameter(c *app.RequestContext, style string, explode bool, required bool, name string, dest interface{}) error {
+	return runtime.BindQueryParameter(style, explode, required, name, QueryValues(c), dest)
+}
+
+// BindHeaderParameter binds the header parameter name of c into dest. It
+// reports whether the header was present, as optional parameters are left
+// untouched when it isn't.
+func BindHeaderParameter(c *app.RequestContext, style string, explode bool, required bool, name string, dest interface{}) (bool, error) {
+	value := c.GetHeader(name)
+	if value == nil {
+		if required {
+			return false, requiredParam(name, runtime.ParamLocationHeader, dest)
+		}
+		return false, nil
+	}
+	return true, runtime.BindStyledParameterWithOptions(style, name, string(value), dest, runtime.BindStyledParameterOptions{
+		ParamLocation: runtime.ParamLocationHeader,
+		Explode:       explode,
+		Required:      required,
+	})
+}
+
+// BindCookieParameter binds the cookie parameter name of c into dest. It
+// reports whether the cookie was present, as optional parameters are left
+// untouched when it isn't.
+func BindCookieParameter(c *app.RequestContext, style string, explode bool, required bool, name string, dest interface{}) (bool, error) {
+	value := c.Cookie(name)
+	if value == nil {
+		if required {
+			return false, requiredParam(name, runtime.ParamLocationCookie, dest)
+		}
+		return false, nil
+	}
+	return true, runtime.BindStyledParameterWithOptions(style, name, string(value), dest, runtime.BindStyledParameterOptions{
+		ParamLocation: runtime.ParamLocationCookie,
+		Explode:       explode,
+		Required:      required,
+	})
+}
+
+// requiredParam returns the error the runtime fails a missing required
+// parameter with, whichever release of it the adapter is built with.
+func requiredParam(name string, location runtime.ParamLocation, dest interface{}) error {
+	return runtime.BindStyledParameterWithOptions("simple", name, "", dest, runtime.BindStyledParameterOptions{
+		ParamLocation: location,
+		Required:      true,
+	})
+}
+
+// QueryValues copies the query arguments of c into url.Values, for the
+// runtime functions which take them.
+func QueryValues(c *app.RequestContext) url.Values {
+	values := make(url.Values)
+	c.QueryArgs().VisitAll(func(key, value []byte) {
+		k := string(key)
+		values[k] = append(values[k], string(value))
+	})
+	return values
+}
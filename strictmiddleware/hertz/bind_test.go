@@ -0,0 +1,89 @@
+package hertz
+
+import (
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/route/param"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestContext(uri string) *app.RequestContext {
+	c := app.NewContext(1)
+	c.Request.SetRequestURI(uri)
+	return c
+}
+
+func TestBindPathParameter(t *testing.T) {
+	c := newTestContext("/pets/3,4")
+	c.Params = append(c.Params, param.Param{Key: "ids", Value: "3,4"})
+
+	var ids []int
+	require.NoError(t, BindPathParameter(c, "simple", false, "ids", &ids))
+	assert.Equal(t, []int{3, 4}, ids)
+
+	// Hertz has already unescaped /files/50%25 and /files/%2525.
+	for _, value := range []string{"50%", "%25"} {
+		c = newTestContext("/files")
+		c.Params = append(c.Params, param.Param{Key: "name", Value: value})
+		var name string
+		require.NoError(t, BindPathParameter(c, "simple", false, "name", &name))
+		assert.Equal(t, value, name)
+	}
+}
+
+func TestBindQueryParameter(t *testing.T) {
+	c := newTestContext("/pets?tags=cat&tags=dog&limit=5")
+
+	var tags []string
+	require.NoError(t, BindQueryParameter(c, "form", true, true, "tags", &tags))
+	assert.Equal(t, []string{"cat", "dog"}, tags)
+
+	var limit int
+	require.NoError(t, BindQueryParameter(c, "form", true, false, "limit", &limit))
+	assert.Equal(t, 5, limit)
+
+	var missing *int
+	require.NoError(t, BindQueryParameter(c, "form", true, false, "offset", &missing))
+	assert.Nil(t, missing)
+}
+
+func TestBindHeaderParameter(t *testing.T) {
+	c := newTestContext("/pets")
+	c.Request.Header.Set("X-Request-Id", "42")
+
+	var id int
+	found, err := BindHeaderParameter(c, "simple", false, true, "X-Request-Id", &id)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 42, id)
+
+	found, err = BindHeaderParameter(c, "simple", false, false, "X-Missing", &id)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	_, err = BindHeaderParameter(c, "simple", false, true, "X-Missing", &id)
+	assert.ErrorContains(t, err, "X-Missing")
+}
+
+func TestBindCookieParameter(t *testing.T) {
+	c := newTestContext("/pets")
+	c.Request.Header.SetCookie("session", "abc")
+
+	var session string
+	found, err := BindCookieParameter(c, "form", true, true, "session", &session)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "abc", session)
+
+	_, err = BindCookieParameter(c, "form", true, true, "missing", &session)
+	assert.ErrorContains(t, err, "missing")
+}
+
+func TestQueryValues(t *testing.T) {
+	c := newTestContext("/pets?a=1&b=2&a=3")
+	values := QueryValues(c)
+	assert.Equal(t, []string{"1", "3"}, values["a"])
+	assert.Equal(t, []string{"2"}, values["b"])
+}
@@ -0,0 +1,11 @@
+package hertz
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+type StrictHertzHandlerFunc func(ctx context.Context, c *app.RequestContext, request interface{}) (response interface{}, err error)
+
+type StrictHertzMiddlewareFunc func(f StrictHertzHandlerFunc, operationID string) StrictHertzHandlerFunc
@@ -0,0 +1,179 @@
+package nethttp
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheControl builds the caching headers of a response: Cache-Control, and
+// optionally ETag and Expires. Its methods return modified copies, so
+// policies compose without affecting each other:
+//
+//	public := nethttp.CacheControl{}.Public().MaxAge(time.Hour)
+//	headers := public.ETag(version).Header()
+//
+// The zero value sets no headers.
+type CacheControl struct {
+	maxAge, sharedMaxAge       time.Duration
+	hasMaxAge, hasSharedMaxAge bool
+	public, private            bool
+	noCache, noStore           bool
+	mustRevalidate, immutable  bool
+	etag                       string
+	expires                    time.Time
+}
+
+// MaxAge sets max-age, the time for which the response is fresh.
+func (c CacheControl) MaxAge(d time.Duration) CacheControl {
+	c.maxAge, c.hasMaxAge = d, true
+	return c
+}
+
+// SharedMaxAge sets s-maxage, which overrides MaxAge for shared caches.
+func (c CacheControl) SharedMaxAge(d time.Duration) CacheControl {
+	c.sharedMaxAge, c.hasSharedMaxAge = d, true
+	return c
+}
+
+// Public marks the response as storable by shared caches, even if it would
+// not be otherwise, for example because the request was authenticated.
+func (c CacheControl) Public() CacheControl {
+	c.public, c.private = true, false
+	return c
+}
+
+// Private restricts storing the response to the client's own cache.
+func (c CacheControl) Private() CacheControl {
+	c.private, c.public = true, false
+	return c
+}
+
+// NoCache requires caches to revalidate the response before every reuse.
+func (c CacheControl) NoCache() CacheControl {
+	c.noCache = true
+	return c
+}
+
+// NoStore forbids caches from storing the response at all, as suits
+// responses carrying secrets.
+func (c CacheControl) NoStore() CacheControl {
+	c.noStore = true
+	return c
+}
+
+// MustRevalidate forbids caches from reusing the response once stale
+// without revalidating it.
+func (c CacheControl) MustRevalidate() CacheControl {
+	c.mustRevalidate = true
+	return c
+}
+
+// Immutable tells caches the response won't change while fresh.
+func (c CacheControl) Immutable() CacheControl {
+	c.immutable = true
+	return c
+}
+
+// ETag sets the ETag header. A tag which isn't quoted, or a weak W/ one, is
+// quoted.
+func (c CacheControl) ETag(tag string) CacheControl {
+	if tag != "" && !strings.HasPrefix(tag, `"`) && !strings.HasPrefix(tag, `W/"`) {
+		tag = strconv.Quote(tag)
+	}
+	c.etag = tag
+	return c
+}
+
+// Expires sets the Expires header, for HTTP/1.0 caches which don't
+// understand max-age.
+func (c CacheControl) Expires(t time.Time) CacheControl {
+	c.expires = t
+	return c
+}
+
+// String returns the value of the Cache-Control header.
+func (c CacheControl) String() string {
+	var directives []string
+	switch {
+	case c.public:
+		directives = append(directives, "public")
+	case c.private:
+		directives = append(directives, "private")
+	}
+	if c.noCache {
+		directives = append(directives, "no-cache")
+	}
+	if c.noStore {
+		directives = append(directives, "no-store")
+	}
+	if c.hasMaxAge {
+		directives = append(directives, "max-age="+seconds(c.maxAge))
+	}
+	if c.hasSharedMaxAge {
+		directives = append(directives, "s-maxage="+seconds(c.sharedMaxAge))
+	}
+	if c.mustRevalidate {
+		directives = append(directives, "must-revalidate")
+	}
+	if c.immutable {
+		directives = append(directives, "immutable")
+	}
+	return strings.Join(directives, ", ")
+}
+
+func seconds(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	return strconv.FormatInt(int64(d/time.Second), 10)
+}
+
+// Apply sets the headers of c on h.
+func (c CacheControl) Apply(h http.Header) {
+	if v := c.String(); v != "" {
+		h.Set("Cache-Control", v)
+	}
+	if c.etag != "" {
+		h.Set("ETag", c.etag)
+	}
+	if !c.expires.IsZero() {
+		h.Set("Expires", c.expires.UTC().Format(http.TimeFormat))
+	}
+}
+
+// Header returns the headers of c, to pass to WithHeaders or NotModified.
+func (c CacheControl) Header() http.Header {
+	h := make(http.Header)
+	c.Apply(h)
+	return h
+}
+
+// CacheControlMiddleware returns a StrictHTTPMiddlewareFunc which sets the
+// caching headers of policies[operationID] on every response of the
+// operations it holds, so that they are set consistently across handlers.
+// Headers a handler or its response sets itself take precedence, and
+// responses to requests the handler failed with an error are left alone.
+func CacheControlMiddleware(policies map[string]CacheControl) StrictHTTPMiddlewareFunc {
+	return func(f StrictHTTPHandlerFunc, operationID string) StrictHTTPHandlerFunc {
+		policy, ok := policies[operationID]
+		if !ok {
+			return f
+		}
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+			response, err := f(ctx, w, r, request)
+			if err != nil {
+				return response, err
+			}
+			h := w.Header()
+			for k, v := range policy.Header() {
+				if _, set := h[k]; !set {
+					h[k] = v
+				}
+			}
+			return response, nil
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package nethttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheControl(t *testing.T) {
+	assert.Equal(t, "", CacheControl{}.String())
+	assert.Empty(t, CacheControl{}.Header())
+
+	public := CacheControl{}.Public().MaxAge(time.Hour)
+	assert.Equal(t, "public, max-age=3600", public.String())
+	assert.Equal(t, "private, no-cache, max-age=0, must-revalidate", CacheControl{}.Public().Private().NoCache().MaxAge(0).MustRevalidate().String())
+	assert.Equal(t, "no-store", CacheControl{}.NoStore().String())
+	assert.Equal(t, "public, max-age=60, s-maxage=600, immutable", public.MaxAge(time.Minute).SharedMaxAge(10*time.Minute).Immutable().String())
+	// Deriving a policy leaves the original untouched.
+	assert.Equal(t, "public, max-age=3600", public.String())
+
+	expires := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	h := public.ETag("v1").Expires(expires).Header()
+	assert.Equal(t, "public, max-age=3600", h.Get("Cache-Control"))
+	assert.Equal(t, `"v1"`, h.Get("ETag"))
+	assert.Equal(t, "Mon, 01 Jan 2024 12:00:00 GMT", h.Get("Expires"))
+	assert.Equal(t, `W/"v1"`, CacheControl{}.ETag(`W/"v1"`).Header().Get("ETag"))
+}
+
+func TestCacheControlMiddleware(t *testing.T) {
+	mw := CacheControlMiddleware(map[string]CacheControl{
+		"getPet":   CacheControl{}.Private().MaxAge(time.Minute),
+		"getToken": CacheControl{}.NoStore(),
+	})
+	run := func(operationID string, handler StrictHTTPHandlerFunc) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		_, _ = mw(handler, operationID)(req.Context(), rec, req, nil)
+		return rec
+	}
+	ok := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return nil, nil
+	}
+
+	assert.Equal(t, "private, max-age=60", run("getPet", ok).Header().Get("Cache-Control"))
+	assert.Equal(t, "no-store", run("getToken", ok).Header().Get("Cache-Control"))
+	assert.Empty(t, run("listPets", ok).Header().Get("Cache-Control"))
+
+	rec := run("getPet", func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		w.Header().Set("Cache-Control", "no-cache")
+		return nil, nil
+	})
+	assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+
+	rec = run("getPet", func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return nil, errors.New("boom")
+	})
+	require.Empty(t, rec.Header().Get("Cache-Control"))
+}
@@ -0,0 +1,93 @@
+package nethttp
+
+import (
+	"context"
+	"net/http"
+)
+
+// ChainStrictMiddlewares composes middlewares into a single
+// StrictHTTPMiddlewareFunc. The first middleware is the outermost one: it
+// sees the request first and the response last, so
+//
+//	ChainStrictMiddlewares(a, b, c)(h, op)
+//
+// is equivalent to a(b(c(h, op), op), op).
+//
+// Note that generated strict servers apply their middleware slice the other
+// way around, with the last element outermost. Passing a single chained
+// middleware to the generated server avoids having to remember that.
+func ChainStrictMiddlewares(middlewares ...StrictHTTPMiddlewareFunc) StrictHTTPMiddlewareFunc {
+	return func(f StrictHTTPHandlerFunc, operationID string) StrictHTTPHandlerFunc {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			f = middlewares[i](f, operationID)
+		}
+		return f
+	}
+}
+
+type middlewareNamesContextKey struct{}
+
+// Named wraps a middleware so that its name is recorded in the request
+// context when it is entered. MiddlewareNamesFromContext returns the names of
+// all named middlewares which have run so far, outermost first, which is
+// useful for debugging the order a chain was actually applied in.
+func Named(name string, m StrictHTTPMiddlewareFunc) StrictHTTPMiddlewareFunc {
+	return func(f StrictHTTPHandlerFunc, operationID string) StrictHTTPHandlerFunc {
+		next := m(f, operationID)
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+			names := MiddlewareNamesFromContext(ctx)
+			// Copy so that sibling requests never share a backing array.
+			names = append(names[:len(names):len(names)], name)
+			return next(context.WithValue(ctx, middlewareNamesContextKey{}, names), w, r, request)
+		}
+	}
+}
+
+// MiddlewareNamesFromContext returns the names of the Named middlewares which
+// have been entered for the current request, outermost first.
+func MiddlewareNamesFromContext(ctx context.Context) []string {
+	names, _ := ctx.Value(middlewareNamesContextKey{}).([]string)
+	return names
+}
+
+// When returns a middleware which applies m only to operations for which
+// match returns true; all other operations are passed through untouched. The
+// decision is made once, when the generated server wraps each operation, so
+// it adds no per-request cost.
+func When(match func(operationID string) bool, m StrictHTTPMiddlewareFunc) StrictHTTPMiddlewareFunc {
+	return func(f StrictHTTPHandlerFunc, operationID string) StrictHTTPHandlerFunc {
+		if !match(operationID) {
+			return f
+		}
+		return m(f, operationID)
+	}
+}
+
+// ForOperations returns a middleware which applies m only to the given
+// operation IDs.
+func ForOperations(m StrictHTTPMiddlewareFunc, operationIDs ...string) StrictHTTPMiddlewareFunc {
+	set := operationSet(operationIDs)
+	return When(func(operationID string) bool {
+		_, ok := set[operationID]
+		return ok
+	}, m)
+}
+
+// ExceptOperations returns a middleware which applies m to every operation
+// except the given operation IDs, such as a health check which must stay
+// unauthenticated.
+func ExceptOperations(m StrictHTTPMiddlewareFunc, operationIDs ...string) StrictHTTPMiddlewareFunc {
+	set := operationSet(operationIDs)
+	return When(func(operationID string) bool {
+		_, ok := set[operationID]
+		return !ok
+	}, m)
+}
+
+func operationSet(operationIDs []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(operationIDs))
+	for _, id := range operationIDs {
+		set[id] = struct{}{}
+	}
+	return set
+}
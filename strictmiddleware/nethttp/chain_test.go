@@ -0,0 +1,76 @@
+package nethttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recordingMiddleware(name string, calls *[]string) StrictHTTPMiddlewareFunc {
+	return func(f StrictHTTPHandlerFunc, operationID string) StrictHTTPHandlerFunc {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+			*calls = append(*calls, name+":"+operationID)
+			return f(ctx, w, r, request)
+		}
+	}
+}
+
+func TestChainStrictMiddlewares(t *testing.T) {
+	var calls []string
+	var names []string
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		calls = append(calls, "handler")
+		names = MiddlewareNamesFromContext(ctx)
+		return "ok", nil
+	}
+
+	chain := ChainStrictMiddlewares(
+		Named("first", recordingMiddleware("first", &calls)),
+		Named("second", recordingMiddleware("second", &calls)),
+		recordingMiddleware("third", &calls),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/pets", nil)
+	response, err := chain(handler, "findPets")(req.Context(), httptest.NewRecorder(), req, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", response)
+	assert.Equal(t, []string{"first:findPets", "second:findPets", "third:findPets", "handler"}, calls)
+	assert.Equal(t, []string{"first", "second"}, names)
+}
+
+func TestChainStrictMiddlewares_Empty(t *testing.T) {
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return request, nil
+	}
+	req := httptest.NewRequest(http.MethodGet, "/pets", nil)
+	response, err := ChainStrictMiddlewares()(handler, "findPets")(req.Context(), httptest.NewRecorder(), req, 42)
+	require.NoError(t, err)
+	assert.Equal(t, 42, response)
+}
+
+func TestForOperations(t *testing.T) {
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	var calls []string
+	only := ForOperations(recordingMiddleware("auth", &calls), "createPet", "deletePet")
+	for _, op := range []string{"createPet", "healthz", "deletePet"} {
+		_, err := only(handler, op)(req.Context(), httptest.NewRecorder(), req, nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"auth:createPet", "auth:deletePet"}, calls)
+
+	calls = nil
+	except := ExceptOperations(recordingMiddleware("auth", &calls), "healthz")
+	for _, op := range []string{"createPet", "healthz", "deletePet"} {
+		_, err := except(handler, op)(req.Context(), httptest.NewRecorder(), req, nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"auth:createPet", "auth:deletePet"}, calls)
+}
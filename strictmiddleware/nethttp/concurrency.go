@@ -0,0 +1,54 @@
+package nethttp
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/oapi-codegen/runtime"
+)
+
+// ConcurrencyLimitMiddleware returns a StrictHTTPMiddlewareFunc which allows
+// at most limits[operationID] requests of each operation to be handled at
+// once, protecting expensive endpoints without external infrastructure.
+// Operations without a positive limit aren't limited. Requests arriving
+// while an operation is saturated aren't queued: they are answered with a
+// 429 Too Many Requests problem, and a Retry-After header of retryAfter,
+// rounded up to whole seconds, when it is positive. A request holds its slot
+// until its response has been written, so that streamed responses count
+// against the limit.
+func ConcurrencyLimitMiddleware(limits map[string]int, retryAfter time.Duration) StrictHTTPMiddlewareFunc {
+	return func(f StrictHTTPHandlerFunc, operationID string) StrictHTTPHandlerFunc {
+		limit := limits[operationID]
+		if limit <= 0 {
+			return f
+		}
+		// The semaphore is shared by every request of the operation, as the
+		// generated server wraps each operation once.
+		sem := make(chan struct{}, limit)
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+			select {
+			case sem <- struct{}{}:
+			default:
+				return tooManyRequests(operationID, retryAfter), nil
+			}
+			response, err := f(ctx, w, r, request)
+			return releaseAfterResponse(r.Context(), response, err, func() { <-sem }), err
+		}
+	}
+}
+
+func tooManyRequests(operationID string, retryAfter time.Duration) Response {
+	return ResponseFunc(func(w http.ResponseWriter) error {
+		if retryAfter > 0 {
+			seconds := (retryAfter + time.Second - 1) / time.Second
+			w.Header().Set("Retry-After", strconv.FormatInt(int64(seconds), 10))
+		}
+		return runtime.WriteProblem(w, &runtime.ProblemDetails{
+			Title:  http.StatusText(http.StatusTooManyRequests),
+			Status: http.StatusTooManyRequests,
+			Detail: "too many concurrent requests for operation " + operationID,
+		})
+	})
+}
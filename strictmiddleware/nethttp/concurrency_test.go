@@ -0,0 +1,103 @@
+package nethttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrencyLimitMiddleware(t *testing.T) {
+	release := make(chan struct{})
+	entered := make(chan struct{})
+	blocking := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		entered <- struct{}{}
+		<-release
+		return ResponseFunc(func(w http.ResponseWriter) error {
+			_, err := w.Write([]byte("done"))
+			return err
+		}), nil
+	}
+	mw := ConcurrencyLimitMiddleware(map[string]int{"report": 1}, 1500*time.Millisecond)
+	handler := mw(blocking, "report")
+
+	call := func() (interface{}, *httptest.ResponseRecorder) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/report", nil)
+		response, err := handler(req.Context(), rec, req, nil)
+		require.NoError(t, err)
+		return response, rec
+	}
+	visit := func(response interface{}) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handled, err := VisitResponse(rec, response)
+		require.NoError(t, err)
+		require.True(t, handled)
+		return rec
+	}
+
+	first := make(chan interface{})
+	go func() {
+		response, _ := call()
+		first <- response
+	}()
+	<-entered
+
+	response, _ := call()
+	rec := visit(response)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+
+	close(release)
+	assert.Equal(t, "done", visit(<-first).Body.String())
+
+	// The slot is free again once the response has been written.
+	go func() { <-entered }()
+	response, _ = call()
+	assert.Equal(t, "done", visit(response).Body.String())
+}
+
+func TestConcurrencyLimitMiddleware_Unlimited(t *testing.T) {
+	next := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	mw := ConcurrencyLimitMiddleware(map[string]int{"report": 1}, 0)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	response, err := mw(next, "listPets")(req.Context(), httptest.NewRecorder(), req, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", response)
+}
+
+func TestConcurrencyLimitMiddleware_HeldUntilWritten(t *testing.T) {
+	next := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return ResponseFunc(func(w http.ResponseWriter) error {
+			_, err := w.Write([]byte("streamed"))
+			return err
+		}), nil
+	}
+	handler := ConcurrencyLimitMiddleware(map[string]int{"export": 1}, 0)(next, "export")
+	call := func() interface{} {
+		req := httptest.NewRequest(http.MethodGet, "/export", nil)
+		response, err := handler(req.Context(), httptest.NewRecorder(), req, nil)
+		require.NoError(t, err)
+		return response
+	}
+
+	streaming := call()
+	rec := httptest.NewRecorder()
+	_, err := VisitResponse(rec, call())
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code, "the slot is held until the response is written")
+
+	_, err = VisitResponse(httptest.NewRecorder(), streaming)
+	require.NoError(t, err)
+	rec = httptest.NewRecorder()
+	_, err = VisitResponse(rec, call())
+	require.NoError(t, err)
+	assert.Equal(t, "streamed", rec.Body.String())
+}
@@ -0,0 +1,43 @@
+package nethttp
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/oapi-codegen/runtime"
+)
+
+// DeadlineMiddleware returns a StrictHTTPMiddlewareFunc which reads the time
+// the caller is willing to wait from header, runtime.RequestTimeoutHeader
+// when empty, in any form runtime.ParseTimeout accepts, and applies it to
+// the request context with context.WithTimeout. Handlers then stop working
+// once the caller has given up, and runtime.DeadlineRequestEditor passes the
+// remaining budget on to the services they call. A positive maxTimeout caps
+// the timeout a caller may ask for. Requests without the header, or with an
+// invalid one, are handled without a deadline. The deadline lasts until the
+// response has been written, so that streamed responses can still use the
+// context.
+func DeadlineMiddleware(header string, maxTimeout time.Duration) StrictHTTPMiddlewareFunc {
+	if header == "" {
+		header = runtime.RequestTimeoutHeader
+	}
+	return func(f StrictHTTPHandlerFunc, operationID string) StrictHTTPHandlerFunc {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+			value := r.Header.Get(header)
+			if value == "" {
+				return f(ctx, w, r, request)
+			}
+			timeout, err := runtime.ParseTimeout(value)
+			if err != nil {
+				return f(ctx, w, r, request)
+			}
+			if maxTimeout > 0 && timeout > maxTimeout {
+				timeout = maxTimeout
+			}
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			response, err := f(ctx, w, r.WithContext(ctx), request)
+			return releaseAfterResponse(ctx, response, err, cancel), err
+		}
+	}
+}
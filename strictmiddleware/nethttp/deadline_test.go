@@ -0,0 +1,70 @@
+package nethttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadlineMiddleware(t *testing.T) {
+	var remaining time.Duration
+	var hasDeadline bool
+	next := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		var deadline time.Time
+		deadline, hasDeadline = ctx.Deadline()
+		remaining = time.Until(deadline)
+		assert.Equal(t, ctx, r.Context())
+		return nil, nil
+	}
+	handler := DeadlineMiddleware("", time.Minute)(next, "getPet")
+	call := func(value string) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if value != "" {
+			req.Header.Set("X-Request-Timeout", value)
+		}
+		_, err := handler(req.Context(), httptest.NewRecorder(), req, nil)
+		require.NoError(t, err)
+	}
+
+	call("1500m")
+	require.True(t, hasDeadline)
+	assert.True(t, remaining > time.Second && remaining <= 1500*time.Millisecond, remaining)
+
+	call("2H")
+	require.True(t, hasDeadline)
+	assert.True(t, remaining > 59*time.Second && remaining <= time.Minute, "the timeout is capped")
+
+	call("")
+	assert.False(t, hasDeadline)
+	call("soon")
+	assert.False(t, hasDeadline)
+}
+
+func TestDeadlineMiddleware_StreamedResponse(t *testing.T) {
+	var handlerCtx context.Context
+	next := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		handlerCtx = ctx
+		return ResponseFunc(func(w http.ResponseWriter) error {
+			// The response is written after the middleware returned, still
+			// within the deadline.
+			assert.NoError(t, handlerCtx.Err())
+			return nil
+		}), nil
+	}
+	handler := DeadlineMiddleware("", 0)(next, "events")
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-Timeout", "1M")
+	response, err := handler(req.Context(), httptest.NewRecorder(), req, nil)
+	require.NoError(t, err)
+	require.NoError(t, handlerCtx.Err())
+
+	handled, err := VisitResponse(httptest.NewRecorder(), response)
+	require.NoError(t, err)
+	require.True(t, handled)
+	assert.ErrorIs(t, handlerCtx.Err(), context.Canceled, "the deadline is released once written")
+}
@@ -0,0 +1,52 @@
+package nethttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrClientDisconnected is wrapped by the error which DisconnectMiddleware
+// returns when the client went away while the request was being handled.
+var ErrClientDisconnected = errors.New("client disconnected")
+
+// IsClientDisconnect reports whether err, returned by a handler serving a
+// request with context ctx, was caused by the client going away rather than
+// by the handler itself. net/http cancels the request context when the
+// client closes the connection, so a cancelled context together with either
+// no error or a context.Canceled error indicates a disconnect. Deadline
+// expiry is not considered a disconnect.
+func IsClientDisconnect(ctx context.Context, err error) bool {
+	if errors.Is(err, ErrClientDisconnected) {
+		return true
+	}
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		return false
+	}
+	return err == nil || errors.Is(err, context.Canceled)
+}
+
+// DisconnectMiddleware returns a StrictHTTPMiddlewareFunc which detects
+// requests abandoned by the client. For those, onDisconnect is called with
+// the operation ID, if it is non-nil, and the handler's result is replaced by
+// an error wrapping ErrClientDisconnected, so that the strict error handler
+// can tell it apart from genuine handler failures and skip writing a
+// response nobody will read.
+func DisconnectMiddleware(onDisconnect func(ctx context.Context, operationID string)) StrictHTTPMiddlewareFunc {
+	return func(f StrictHTTPHandlerFunc, operationID string) StrictHTTPHandlerFunc {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+			response, err := f(ctx, w, r, request)
+			if !IsClientDisconnect(ctx, err) {
+				return response, err
+			}
+			if onDisconnect != nil {
+				onDisconnect(ctx, operationID)
+			}
+			if err == nil {
+				return nil, fmt.Errorf("operation %s: %w", operationID, ErrClientDisconnected)
+			}
+			return nil, fmt.Errorf("operation %s: %w: %w", operationID, ErrClientDisconnected, err)
+		}
+	}
+}
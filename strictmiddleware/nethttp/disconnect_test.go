@@ -0,0 +1,53 @@
+package nethttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsClientDisconnect(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	assert.True(t, IsClientDisconnect(cancelled, nil))
+	assert.True(t, IsClientDisconnect(cancelled, context.Canceled))
+	assert.False(t, IsClientDisconnect(cancelled, errors.New("database is down")))
+	assert.False(t, IsClientDisconnect(expired, context.DeadlineExceeded))
+	assert.False(t, IsClientDisconnect(context.Background(), nil))
+	assert.True(t, IsClientDisconnect(context.Background(), ErrClientDisconnected))
+}
+
+func TestDisconnectMiddleware(t *testing.T) {
+	var disconnected []string
+	mw := DisconnectMiddleware(func(ctx context.Context, operationID string) {
+		disconnected = append(disconnected, operationID)
+	})
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodGet, "/reports", nil).WithContext(ctx)
+	response, err := mw(handler, "buildReport")(ctx, httptest.NewRecorder(), req, nil)
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, ErrClientDisconnected)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"buildReport"}, disconnected)
+
+	ok := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return "done", nil
+	}
+	response, err = mw(ok, "buildReport")(context.Background(), httptest.NewRecorder(), req, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", response)
+	assert.Len(t, disconnected, 1)
+}
@@ -0,0 +1,42 @@
+//go:build go1.21
+
+package nethttp
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/oapi-codegen/runtime"
+)
+
+// LoggerMiddleware returns a StrictHTTPMiddlewareFunc which stores base,
+// with the operation ID, method and path template attached, in the request
+// context, where handlers retrieve it with runtime.LoggerFrom. The path
+// template is taken from the runtime.OperationInfo in the context if it
+// has one, or else, from Go 1.23 on, from the http.ServeMux pattern the
+// request matched. A nil base stands for slog.Default().
+func LoggerMiddleware(base *slog.Logger) StrictHTTPMiddlewareFunc {
+	return func(f StrictHTTPHandlerFunc, operationID string) StrictHTTPHandlerFunc {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+			logger := base
+			if logger == nil {
+				logger = slog.Default()
+			}
+			logger = logger.With(
+				slog.String("operation_id", operationID),
+				slog.String("method", r.Method),
+				slog.String("path_template", pathTemplate(ctx, r)),
+			)
+			return f(runtime.WithLogger(ctx, logger), w, r, request)
+		}
+	}
+}
+
+// pathTemplate returns the spec path the request was routed by.
+func pathTemplate(ctx context.Context, r *http.Request) string {
+	if info, ok := runtime.GetOperationInfo(ctx); ok && info.PathTemplate != "" {
+		return info.PathTemplate
+	}
+	return servemuxPattern(r)
+}
@@ -0,0 +1,21 @@
+//go:build go1.23
+
+package nethttp
+
+import (
+	"net/http"
+	"strings"
+)
+
+// servemuxPattern returns the path of the http.ServeMux pattern r matched.
+func servemuxPattern(r *http.Request) string {
+	// ServeMux patterns may start with a method and a host.
+	pattern := r.Pattern
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		pattern = path
+	}
+	if i := strings.IndexByte(pattern, '/'); i > 0 {
+		pattern = pattern[i:]
+	}
+	return pattern
+}
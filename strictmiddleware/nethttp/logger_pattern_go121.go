@@ -0,0 +1,11 @@
+//go:build go1.21 && !go1.23
+
+package nethttp
+
+import "net/http"
+
+// servemuxPattern returns an empty path, as http.Request only records the
+// http.ServeMux pattern it matched from Go 1.23 on.
+func servemuxPattern(r *http.Request) string {
+	return ""
+}
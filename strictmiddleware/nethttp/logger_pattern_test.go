@@ -0,0 +1,31 @@
+//go:build go1.23
+
+package nethttp
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/oapi-codegen/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerMiddleware_ServeMuxPattern(t *testing.T) {
+	var buf bytes.Buffer
+	base := slog.New(slog.NewTextHandler(&buf, nil))
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		runtime.LoggerFrom(ctx).Info("handled")
+		return nil, nil
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/pets/5", nil)
+	r.Pattern = "GET /pets/{petId}"
+	_, _ = LoggerMiddleware(base)(handler, "getPet")(r.Context(), httptest.NewRecorder(), r, nil)
+
+	assert.Contains(t, buf.String(), "operation_id=getPet")
+	assert.Contains(t, buf.String(), "path_template=/pets/{petId}")
+}
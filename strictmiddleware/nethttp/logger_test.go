@@ -0,0 +1,31 @@
+//go:build go1.21
+
+package nethttp
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/oapi-codegen/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	base := slog.New(slog.NewTextHandler(&buf, nil))
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		runtime.LoggerFrom(ctx).Info("handled")
+		return nil, nil
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/pets", nil)
+	ctx := runtime.WithOperationInfo(r.Context(), runtime.OperationInfo{PathTemplate: "/pets"})
+	_, _ = LoggerMiddleware(base)(handler, "addPet")(ctx, httptest.NewRecorder(), r, nil)
+	assert.Contains(t, buf.String(), "operation_id=addPet")
+	assert.Contains(t, buf.String(), "method=POST")
+	assert.Contains(t, buf.String(), "path_template=/pets")
+}
@@ -0,0 +1,153 @@
+package nethttp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+
+	"github.com/oapi-codegen/runtime"
+)
+
+// RangeResponse is a Response serving Content, a representation of Size
+// bytes, in part when the request asks for it with a Range header, so that
+// strict handlers of binary download operations support resumable
+// downloads. Range and IfRange hold the request's Range and If-Range
+// headers, which the operation declares as parameters; ServeRange reads them
+// from the request instead.
+//
+// A single satisfiable range is answered with 206 Partial Content, several
+// with a multipart/byteranges body, and a Range of which no range is
+// satisfiable with 416 Range Not Satisfiable. The whole representation is
+// served with a 200 when no Range is given, when it is malformed or asks for
+// more than the whole, or when IfRange doesn't match the ETag or
+// Last-Modified header of Headers, as the representation then changed since
+// the client got its first part. If Content is also an io.Closer, it is
+// closed once the response has been written.
+type RangeResponse struct {
+	Range       string
+	IfRange     string
+	ContentType string
+	Size        int64
+	// Headers should carry the ETag or Last-Modified validators of the
+	// representation, which clients use to resume a download only if it
+	// didn't change.
+	Headers http.Header
+	Content io.ReadSeeker
+}
+
+func (r RangeResponse) VisitResponse(w http.ResponseWriter) error {
+	if c, ok := r.Content.(io.Closer); ok {
+		defer func() { _ = c.Close() }()
+	}
+	for k, v := range r.Headers {
+		w.Header()[k] = v
+	}
+	w.Header().Set("Accept-Ranges", "bytes")
+
+	var ranges []runtime.ByteRange
+	if r.IfRange == "" || ifRangeMatches(r.IfRange, w.Header()) {
+		var err error
+		ranges, err = runtime.ParseRangeHeader(r.Range, r.Size)
+		var notSatisfiable *runtime.RangeNotSatisfiableError
+		if errors.As(err, &notSatisfiable) {
+			w.Header().Set("Content-Range", notSatisfiable.ContentRange())
+			http.Error(w, notSatisfiable.Error(), http.StatusRequestedRangeNotSatisfiable)
+			return nil
+		}
+		// A malformed Range is ignored, as is one asking for more bytes
+		// than the whole representation, which only multiplies the work of
+		// serving it.
+		if err != nil || rangesLength(ranges) > r.Size {
+			ranges = nil
+		}
+	}
+
+	switch len(ranges) {
+	case 0:
+		return r.serve(w, http.StatusOK, runtime.ByteRange{Length: r.Size})
+	case 1:
+		w.Header().Set("Content-Range", ranges[0].ContentRange(r.Size))
+		return r.serve(w, http.StatusPartialContent, ranges[0])
+	}
+
+	mw := multipart.NewWriter(w)
+	w.Header().Set("Content-Type", "multipart/byteranges; boundary="+mw.Boundary())
+	w.WriteHeader(http.StatusPartialContent)
+	for _, br := range ranges {
+		header := textproto.MIMEHeader{"Content-Range": {br.ContentRange(r.Size)}}
+		if r.ContentType != "" {
+			header.Set("Content-Type", r.ContentType)
+		}
+		part, err := mw.CreatePart(header)
+		if err != nil {
+			return err
+		}
+		if err := copyRange(part, r.Content, br); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+// serve writes the bytes of br as the whole body.
+func (r RangeResponse) serve(w http.ResponseWriter, statusCode int, br runtime.ByteRange) error {
+	if r.ContentType != "" {
+		w.Header().Set("Content-Type", r.ContentType)
+	}
+	w.Header().Set("Content-Length", strconv.FormatInt(br.Length, 10))
+	w.WriteHeader(statusCode)
+	return copyRange(w, r.Content, br)
+}
+
+// ServeRange writes content, a representation of size bytes, honoring the
+// Range and If-Range headers of req, as RangeResponse does. It suits plain
+// net/http handlers and ResponseFunc.
+func ServeRange(w http.ResponseWriter, req *http.Request, contentType string, size int64, content io.ReadSeeker) error {
+	return RangeResponse{
+		Range:       req.Header.Get("Range"),
+		IfRange:     req.Header.Get("If-Range"),
+		ContentType: contentType,
+		Size:        size,
+		Content:     content,
+	}.VisitResponse(w)
+}
+
+func copyRange(w io.Writer, content io.ReadSeeker, br runtime.ByteRange) error {
+	if br.Length == 0 {
+		return nil
+	}
+	if _, err := content.Seek(br.Start, io.SeekStart); err != nil {
+		return fmt.Errorf("error seeking to byte %d: %w", br.Start, err)
+	}
+	_, err := io.CopyN(w, content, br.Length)
+	return err
+}
+
+func rangesLength(ranges []runtime.ByteRange) int64 {
+	var n int64
+	for _, br := range ranges {
+		n += br.Length
+	}
+	return n
+}
+
+// ifRangeMatches reports whether the If-Range header ifRange matches the
+// representation described by h: an entity tag must match its ETag
+// strongly, and a date must equal its Last-Modified date.
+func ifRangeMatches(ifRange string, h http.Header) bool {
+	if strings.HasPrefix(ifRange, `"`) || strings.HasPrefix(ifRange, "W/") {
+		etag := h.Get("ETag")
+		return !strings.HasPrefix(ifRange, "W/") && ifRange == etag
+	}
+	date, err := http.ParseTime(ifRange)
+	if err != nil {
+		return false
+	}
+	lastModified, err := http.ParseTime(h.Get("Last-Modified"))
+	return err == nil && date.Equal(lastModified)
+}
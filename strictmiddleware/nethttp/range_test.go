@@ -0,0 +1,117 @@
+package nethttp
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rangeContent = "0123456789"
+
+func serveTestRange(t *testing.T, resp RangeResponse) *httptest.ResponseRecorder {
+	t.Helper()
+	resp.ContentType = "text/plain"
+	resp.Size = int64(len(rangeContent))
+	resp.Content = strings.NewReader(rangeContent)
+	rec := httptest.NewRecorder()
+	require.NoError(t, resp.VisitResponse(rec))
+	return rec
+}
+
+func TestRangeResponse(t *testing.T) {
+	t.Run("whole", func(t *testing.T) {
+		rec := serveTestRange(t, RangeResponse{})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "bytes", rec.Header().Get("Accept-Ranges"))
+		assert.Equal(t, "10", rec.Header().Get("Content-Length"))
+		assert.Equal(t, rangeContent, rec.Body.String())
+	})
+
+	t.Run("single range", func(t *testing.T) {
+		rec := serveTestRange(t, RangeResponse{Range: "bytes=4-"})
+		assert.Equal(t, http.StatusPartialContent, rec.Code)
+		assert.Equal(t, "bytes 4-9/10", rec.Header().Get("Content-Range"))
+		assert.Equal(t, "6", rec.Header().Get("Content-Length"))
+		assert.Equal(t, "text/plain", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "456789", rec.Body.String())
+	})
+
+	t.Run("not satisfiable", func(t *testing.T) {
+		rec := serveTestRange(t, RangeResponse{Range: "bytes=10-"})
+		assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, rec.Code)
+		assert.Equal(t, "bytes */10", rec.Header().Get("Content-Range"))
+	})
+
+	t.Run("malformed range is ignored", func(t *testing.T) {
+		rec := serveTestRange(t, RangeResponse{Range: "bytes=5-1"})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, rangeContent, rec.Body.String())
+	})
+
+	t.Run("overlapping ranges are ignored", func(t *testing.T) {
+		rec := serveTestRange(t, RangeResponse{Range: "bytes=0-7,2-9"})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, rangeContent, rec.Body.String())
+	})
+
+	t.Run("if-range", func(t *testing.T) {
+		headers := http.Header{
+			"Etag":          {`"v2"`},
+			"Last-Modified": {"Wed, 21 Oct 2015 07:28:00 GMT"},
+		}
+		for ifRange, partial := range map[string]bool{
+			`"v2"`:                          true,
+			`"v1"`:                          false,
+			`W/"v2"`:                        false,
+			"Wed, 21 Oct 2015 07:28:00 GMT": true,
+			"Thu, 22 Oct 2015 07:28:00 GMT": false,
+		} {
+			rec := serveTestRange(t, RangeResponse{Range: "bytes=0-1", IfRange: ifRange, Headers: headers})
+			if partial {
+				assert.Equal(t, http.StatusPartialContent, rec.Code, ifRange)
+			} else {
+				assert.Equal(t, http.StatusOK, rec.Code, ifRange)
+			}
+		}
+	})
+}
+
+func TestRangeResponse_MultipleRanges(t *testing.T) {
+	rec := serveTestRange(t, RangeResponse{Range: "bytes=0-1,-2"})
+	assert.Equal(t, http.StatusPartialContent, rec.Code)
+
+	mediaType, params, err := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/byteranges", mediaType)
+	mr := multipart.NewReader(rec.Body, params["boundary"])
+	var parts []string
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, "text/plain", part.Header.Get("Content-Type"))
+		data, err := io.ReadAll(part)
+		require.NoError(t, err)
+		parts = append(parts, part.Header.Get("Content-Range")+": "+string(data))
+	}
+	assert.Equal(t, []string{"bytes 0-1/10: 01", "bytes 8-9/10: 89"}, parts)
+}
+
+func TestServeRange(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/files/1", nil)
+	req.Header.Set("Range", "bytes=-3")
+	rec := httptest.NewRecorder()
+	require.NoError(t, ServeRange(rec, req, "application/octet-stream", 10, strings.NewReader(rangeContent)))
+	assert.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Equal(t, "bytes 7-9/10", rec.Header().Get("Content-Range"))
+	assert.Equal(t, "789", rec.Body.String())
+}
@@ -0,0 +1,194 @@
+package nethttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/oapi-codegen/runtime"
+)
+
+// Response is implemented by responses which know how to write themselves.
+// A StrictHTTPMiddlewareFunc may return a Response without calling the next
+// handler, for example to reject an unauthenticated request with a 401, and
+// generated strict wrappers will write it via VisitResponse instead of
+// treating it as an unexpected response type.
+type Response interface {
+	VisitResponse(w http.ResponseWriter) error
+}
+
+// ResponseFunc adapts an ordinary function to the Response interface.
+type ResponseFunc func(w http.ResponseWriter) error
+
+func (f ResponseFunc) VisitResponse(w http.ResponseWriter) error {
+	return f(w)
+}
+
+// JSONResponse is a Response which writes Body as application/json with the
+// given status code and headers.
+type JSONResponse struct {
+	// StatusCode defaults to 200 when zero.
+	StatusCode int
+	Headers    http.Header
+	Body       interface{}
+}
+
+func (r JSONResponse) VisitResponse(w http.ResponseWriter) error {
+	for k, v := range r.Headers {
+		w.Header()[k] = v
+	}
+	body, err := runtime.GetJSONCodec().Marshal(r.Body)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusOrOK(r.StatusCode))
+	// Terminated by a newline, as encoding/json.Encoder writes it.
+	_, err = w.Write(append(body, '\n'))
+	return err
+}
+
+// StreamResponse is a Response which copies Body to the client as it is
+// read, without buffering it in memory, so strict handlers can return large
+// downloads or proxied upstream bodies. If Body is also an io.Closer, it is
+// closed once the response has been written.
+type StreamResponse struct {
+	// StatusCode defaults to 200 when zero.
+	StatusCode  int
+	ContentType string
+	// ContentLength is sent as the Content-Length header when positive;
+	// otherwise the body is sent chunked. It is ignored when Trailers is
+	// set, as trailers can only follow a chunked body.
+	ContentLength int64
+	Headers       http.Header
+	Body          io.Reader
+	// TrailerNames declares the trailers Trailers returns.
+	TrailerNames []string
+	// Trailers, when set, is called once Body has been copied, with the
+	// error reading it, if any, and returns the trailers to send after it,
+	// such as a checksum of the body or the outcome of its processing.
+	Trailers func(err error) http.Header
+}
+
+func (r StreamResponse) VisitResponse(w http.ResponseWriter) error {
+	if c, ok := r.Body.(io.Closer); ok {
+		defer func() { _ = c.Close() }()
+	}
+	for k, v := range r.Headers {
+		w.Header()[k] = v
+	}
+	if r.ContentType != "" {
+		w.Header().Set("Content-Type", r.ContentType)
+	}
+	if r.ContentLength > 0 && r.Trailers == nil {
+		w.Header().Set("Content-Length", strconv.FormatInt(r.ContentLength, 10))
+	}
+	DeclareTrailers(w, r.TrailerNames...)
+	w.WriteHeader(statusOrOK(r.StatusCode))
+	var err error
+	if r.Body != nil {
+		_, err = io.Copy(w, r.Body)
+	}
+	if r.Trailers != nil {
+		SetTrailers(w, r.Trailers(err))
+	}
+	return err
+}
+
+// HeaderResponse is a Response consisting only of a status code and headers,
+// such as a 204 No Content or a 304 Not Modified. Generated response types
+// for operations without a body can embed it.
+type HeaderResponse struct {
+	// StatusCode defaults to 200 when zero.
+	StatusCode int
+	Headers    http.Header
+}
+
+func (r HeaderResponse) VisitResponse(w http.ResponseWriter) error {
+	for k, v := range r.Headers {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(statusOrOK(r.StatusCode))
+	return nil
+}
+
+// statusOrOK returns statusCode, or 200 when it is zero, as
+// http.ResponseWriter.WriteHeader panics for a zero status code.
+func statusOrOK(statusCode int) int {
+	if statusCode == 0 {
+		return http.StatusOK
+	}
+	return statusCode
+}
+
+// NoContent returns a 204 No Content response.
+func NoContent() HeaderResponse {
+	return HeaderResponse{StatusCode: http.StatusNoContent}
+}
+
+// NotModified returns a 304 Not Modified response. Per RFC 9110, headers
+// such as ETag and Cache-Control which would have been sent with a 200
+// should be passed here as well.
+func NotModified(headers http.Header) HeaderResponse {
+	return HeaderResponse{StatusCode: http.StatusNotModified, Headers: headers}
+}
+
+// WithHeaders returns a Response which sets headers before writing resp.
+// Headers set by resp itself take precedence.
+func WithHeaders(resp Response, headers http.Header) Response {
+	return ResponseFunc(func(w http.ResponseWriter) error {
+		for k, v := range headers {
+			w.Header()[k] = v
+		}
+		return resp.VisitResponse(w)
+	})
+}
+
+// VisitResponse writes response to w if it implements Response. It reports
+// whether the response was handled, so that generated wrappers can fall back
+// to their own response types when it wasn't.
+func VisitResponse(w http.ResponseWriter, response interface{}) (bool, error) {
+	r, ok := response.(Response)
+	if !ok {
+		return false, nil
+	}
+	return true, r.VisitResponse(w)
+}
+
+// releaseAfterResponse calls release once the response a handler returned
+// has been written, as generated strict wrappers write it only after the
+// middleware returned. A Response is wrapped to call release once visited;
+// other responses, which the wrappers write themselves, hold on until ctx,
+// which the server cancels once the request has been handled, is done. An
+// error has no response to wait for, and calls release at once.
+func releaseAfterResponse(ctx context.Context, response interface{}, err error, release func()) interface{} {
+	resp, ok := response.(Response)
+	if err != nil || response == nil {
+		release()
+		return response
+	}
+	stop := make(chan struct{})
+	var once sync.Once
+	done := func() {
+		once.Do(func() {
+			close(stop)
+			release()
+		})
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			done()
+		case <-stop:
+		}
+	}()
+	if !ok {
+		return response
+	}
+	return ResponseFunc(func(w http.ResponseWriter) error {
+		defer done()
+		return resp.VisitResponse(w)
+	})
+}
@@ -0,0 +1,143 @@
+package nethttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVisitResponse_ShortCircuit(t *testing.T) {
+	var nextCalled bool
+	next := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		nextCalled = true
+		return nil, nil
+	}
+	auth := func(f StrictHTTPHandlerFunc, operationID string) StrictHTTPHandlerFunc {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+			if r.Header.Get("Authorization") == "" {
+				return JSONResponse{
+					StatusCode: http.StatusUnauthorized,
+					Headers:    http.Header{"Www-Authenticate": []string{"Bearer"}},
+					Body:       map[string]string{"message": "unauthorized"},
+				}, nil
+			}
+			return f(ctx, w, r, request)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/pets", nil)
+	response, err := auth(next, "findPets")(req.Context(), rec, req, nil)
+	require.NoError(t, err)
+	assert.False(t, nextCalled)
+
+	handled, err := VisitResponse(rec, response)
+	require.NoError(t, err)
+	assert.True(t, handled)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"message":"unauthorized"}`, rec.Body.String())
+}
+
+func TestVisitResponse_NotAResponse(t *testing.T) {
+	rec := httptest.NewRecorder()
+	handled, err := VisitResponse(rec, struct{}{})
+	require.NoError(t, err)
+	assert.False(t, handled)
+	assert.Equal(t, 0, rec.Body.Len())
+}
+
+func TestResponseFunc(t *testing.T) {
+	rec := httptest.NewRecorder()
+	handled, err := VisitResponse(rec, ResponseFunc(func(w http.ResponseWriter) error {
+		w.WriteHeader(http.StatusTeapot)
+		return nil
+	}))
+	require.NoError(t, err)
+	assert.True(t, handled)
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestStreamResponse(t *testing.T) {
+	body := &closeRecorder{Reader: strings.NewReader("0123456789")}
+	rec := httptest.NewRecorder()
+	err := StreamResponse{
+		ContentType:   "application/octet-stream",
+		ContentLength: 10,
+		Headers:       http.Header{"Content-Disposition": []string{`attachment; filename="digits.bin"`}},
+		Body:          body,
+	}.VisitResponse(rec)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/octet-stream", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "10", rec.Header().Get("Content-Length"))
+	assert.Equal(t, `attachment; filename="digits.bin"`, rec.Header().Get("Content-Disposition"))
+	assert.Equal(t, "0123456789", rec.Body.String())
+	assert.True(t, body.closed)
+}
+
+func TestStreamResponse_NilBody(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := StreamResponse{StatusCode: http.StatusAccepted}.VisitResponse(rec)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Length"))
+}
+
+func TestHeaderResponses(t *testing.T) {
+	t.Run("no content", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		require.NoError(t, NoContent().VisitResponse(rec))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, 0, rec.Body.Len())
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		require.NoError(t, NotModified(http.Header{"Etag": []string{`"v1"`}}).VisitResponse(rec))
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Equal(t, `"v1"`, rec.Header().Get("ETag"))
+	})
+
+	t.Run("zero status code", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		require.NoError(t, HeaderResponse{}.VisitResponse(rec))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		rec = httptest.NewRecorder()
+		require.NoError(t, JSONResponse{Body: 1}.VisitResponse(rec))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "1\n", rec.Body.String())
+	})
+
+	t.Run("with headers", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		resp := WithHeaders(JSONResponse{StatusCode: http.StatusCreated, Body: 1}, http.Header{
+			"Location":     []string{"/pets/1"},
+			"Content-Type": []string{"text/plain"},
+		})
+		require.NoError(t, resp.VisitResponse(rec))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "/pets/1", rec.Header().Get("Location"))
+		// The wrapped response's own headers win.
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	})
+}
@@ -0,0 +1,46 @@
+package nethttp
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+// ResponseController is the subset of *http.ResponseController which strict
+// handler implementations may use to flush a streaming response, hijack the
+// connection, or adjust its deadlines.
+type ResponseController interface {
+	Flush() error
+	Hijack() (net.Conn, *bufio.ReadWriter, error)
+	SetReadDeadline(deadline time.Time) error
+	SetWriteDeadline(deadline time.Time) error
+}
+
+var _ ResponseController = (*http.ResponseController)(nil)
+
+type responseControllerContextKey struct{}
+
+// WithResponseController returns a copy of ctx which carries a
+// ResponseController for w.
+func WithResponseController(ctx context.Context, w http.ResponseWriter) context.Context {
+	return context.WithValue(ctx, responseControllerContextKey{}, http.NewResponseController(w))
+}
+
+// ResponseControllerFromContext returns the ResponseController stored in ctx
+// by WithResponseController or ResponseControllerMiddleware.
+func ResponseControllerFromContext(ctx context.Context) (ResponseController, bool) {
+	rc, ok := ctx.Value(responseControllerContextKey{}).(ResponseController)
+	return rc, ok
+}
+
+// ResponseControllerMiddleware is a StrictHTTPMiddlewareFunc which makes the
+// underlying http.ResponseWriter reachable from strict handler
+// implementations, which otherwise only receive a context and the typed
+// request object.
+func ResponseControllerMiddleware(f StrictHTTPHandlerFunc, operationID string) StrictHTTPHandlerFunc {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return f(WithResponseController(ctx, w), w, r, request)
+	}
+}
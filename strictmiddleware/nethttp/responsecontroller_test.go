@@ -0,0 +1,33 @@
+package nethttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseControllerMiddleware(t *testing.T) {
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		rc, ok := ResponseControllerFromContext(ctx)
+		require.True(t, ok)
+		_, err := w.Write([]byte("data: hello\n\n"))
+		require.NoError(t, err)
+		return nil, rc.Flush()
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	_, err := ResponseControllerMiddleware(handler, "streamEvents")(req.Context(), rec, req, nil)
+	require.NoError(t, err)
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, "data: hello\n\n", rec.Body.String())
+}
+
+func TestResponseControllerFromContext_Missing(t *testing.T) {
+	_, ok := ResponseControllerFromContext(context.Background())
+	assert.False(t, ok)
+}
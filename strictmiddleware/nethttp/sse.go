@@ -0,0 +1,165 @@
+package nethttp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oapi-codegen/runtime"
+)
+
+// SSEWriter writes Server-Sent Events (text/event-stream) to a client,
+// flushing after every event so that it is delivered immediately. It is safe
+// for concurrent use, so heartbeats may be written while events are being
+// produced.
+type SSEWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+	rc *http.ResponseController
+}
+
+// NewSSEWriter prepares w for an event stream by setting the Content-Type
+// and Cache-Control headers, and returns a writer for it. The status line is
+// sent with the first event, so further headers may still be set until then.
+func NewSSEWriter(w http.ResponseWriter) *SSEWriter {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	return &SSEWriter{w: w, rc: http.NewResponseController(w)}
+}
+
+// ErrSSEFieldLineBreak is returned by WriteEvent for an event name or id
+// containing a line break, which would end the field early and let the rest
+// of the value be read as fields or events of its own.
+var ErrSSEFieldLineBreak = errors.New("event name and id must not contain line breaks")
+
+// sseLineBreaks matches the line endings of the event stream format.
+var sseLineBreaks = regexp.MustCompile("\r\n|\r|\n")
+
+// WriteEvent sends an event. An empty name or id is omitted from the event,
+// and one containing a line break fails with ErrSSEFieldLineBreak. Strings
+// and byte slices are sent as is, split over several data lines if they
+// contain line breaks, and any other data is sent as JSON.
+func (s *SSEWriter) WriteEvent(name, id string, data interface{}) error {
+	if strings.ContainsAny(name, "\r\n") || strings.ContainsAny(id, "\r\n") {
+		return ErrSSEFieldLineBreak
+	}
+	var payload string
+	switch d := data.(type) {
+	case string:
+		payload = d
+	case []byte:
+		payload = string(d)
+	default:
+		b, err := runtime.GetJSONCodec().Marshal(data)
+		if err != nil {
+			return fmt.Errorf("error encoding event data: %w", err)
+		}
+		payload = string(b)
+	}
+
+	var b strings.Builder
+	if id != "" {
+		writeSSEField(&b, "id", id)
+	}
+	if name != "" {
+		writeSSEField(&b, "event", name)
+	}
+	for _, line := range sseLineBreaks.Split(payload, -1) {
+		writeSSEField(&b, "data", line)
+	}
+	b.WriteByte('\n')
+	return s.write(b.String())
+}
+
+// WriteComment sends a comment line, which clients ignore. Comments are
+// typically used to keep idle connections open through proxies.
+func (s *SSEWriter) WriteComment(text string) error {
+	return s.write(": " + sseLineBreaks.ReplaceAllString(text, " ") + "\n\n")
+}
+
+// SetRetry tells the client how long to wait before reconnecting after the
+// connection is lost.
+func (s *SSEWriter) SetRetry(d time.Duration) error {
+	return s.write("retry: " + strconv.FormatInt(d.Milliseconds(), 10) + "\n\n")
+}
+
+// Heartbeat writes a comment every interval until stop is closed or a write
+// fails, which usually means the client has gone away.
+func (s *SSEWriter) Heartbeat(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := s.WriteComment("heartbeat"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *SSEWriter) write(data string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := io.WriteString(s.w, data); err != nil {
+		return err
+	}
+	if err := s.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+func writeSSEField(b *strings.Builder, field, value string) {
+	b.WriteString(field)
+	b.WriteString(": ")
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// SSEResponse is a Response which streams Server-Sent Events, for
+// operations declared as text/event-stream. Stream is called with a writer
+// for the event stream and should return once there are no more events,
+// typically when the request context is done.
+type SSEResponse struct {
+	Headers http.Header
+	// HeartbeatInterval, when positive, sends a comment at this interval
+	// while Stream runs, keeping the connection open when events are rare.
+	HeartbeatInterval time.Duration
+	Stream            func(sse *SSEWriter) error
+}
+
+func (r SSEResponse) VisitResponse(w http.ResponseWriter) error {
+	for k, v := range r.Headers {
+		w.Header()[k] = v
+	}
+	sse := NewSSEWriter(w)
+	w.WriteHeader(http.StatusOK)
+	if err := sse.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	if r.HeartbeatInterval > 0 {
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			sse.Heartbeat(r.HeartbeatInterval, stop)
+		}()
+		defer func() {
+			close(stop)
+			<-done
+		}()
+	}
+	if r.Stream == nil {
+		return nil
+	}
+	return r.Stream(sse)
+}
@@ -0,0 +1,84 @@
+package nethttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSEWriter(t *testing.T) {
+	w := httptest.NewRecorder()
+	sse := NewSSEWriter(w)
+	require.NoError(t, sse.SetRetry(2*time.Second))
+	require.NoError(t, sse.WriteEvent("greeting", "1", "hello\nworld"))
+	require.NoError(t, sse.WriteEvent("", "", map[string]int{"count": 2}))
+	require.NoError(t, sse.WriteComment("ping"))
+
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "retry: 2000\n\n"+
+		"id: 1\nevent: greeting\ndata: hello\ndata: world\n\n"+
+		"data: {\"count\":2}\n\n"+
+		": ping\n\n", w.Body.String())
+}
+
+func TestSSEWriter_LineBreaks(t *testing.T) {
+	w := httptest.NewRecorder()
+	sse := NewSSEWriter(w)
+	require.NoError(t, sse.WriteEvent("", "", "a\r\nb\rc\nd"))
+	assert.Equal(t, "data: a\ndata: b\ndata: c\ndata: d\n\n", w.Body.String())
+
+	// Line breaks in the id or name would inject fields or events.
+	err := sse.WriteEvent("update\ndata: injected", "", "x")
+	assert.True(t, errors.Is(err, ErrSSEFieldLineBreak))
+	err = sse.WriteEvent("", "1\r\n\r\ndata: injected", "x")
+	assert.True(t, errors.Is(err, ErrSSEFieldLineBreak))
+	assert.Equal(t, "data: a\ndata: b\ndata: c\ndata: d\n\n", w.Body.String())
+}
+
+// heartbeatRecorder is a ResponseRecorder which signals its first
+// heartbeat.
+type heartbeatRecorder struct {
+	*httptest.ResponseRecorder
+	once      sync.Once
+	heartbeat chan struct{}
+}
+
+func (r *heartbeatRecorder) Write(p []byte) (int, error) {
+	n, err := r.ResponseRecorder.Write(p)
+	if strings.HasPrefix(string(p), ": heartbeat") {
+		r.once.Do(func() { close(r.heartbeat) })
+	}
+	return n, err
+}
+
+func (r *heartbeatRecorder) WriteString(s string) (int, error) {
+	return r.Write([]byte(s))
+}
+
+func TestSSEResponse(t *testing.T) {
+	w := &heartbeatRecorder{ResponseRecorder: httptest.NewRecorder(), heartbeat: make(chan struct{})}
+	resp := SSEResponse{
+		Headers:           http.Header{"X-Stream": {"events"}},
+		HeartbeatInterval: time.Millisecond,
+		Stream: func(sse *SSEWriter) error {
+			<-w.heartbeat
+			return sse.WriteEvent("done", "", "bye")
+		},
+	}
+	handled, err := VisitResponse(w, resp)
+	require.NoError(t, err)
+	assert.True(t, handled)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "events", w.Header().Get("X-Stream"))
+	assert.Contains(t, w.Body.String(), ": heartbeat\n\n")
+	assert.Contains(t, w.Body.String(), "event: done\ndata: bye\n\n")
+}
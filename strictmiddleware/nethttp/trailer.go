@@ -0,0 +1,26 @@
+package nethttp
+
+import (
+	"net/http"
+)
+
+// DeclareTrailers announces, in the Trailer header, the names of the
+// trailers a response will send after its body, such as a checksum only
+// known once the body is written. It must be called before the status line
+// is written. Declaring trailers is optional, but lets clients and proxies
+// expect them.
+func DeclareTrailers(w http.ResponseWriter, names ...string) {
+	for _, name := range names {
+		w.Header().Add("Trailer", http.CanonicalHeaderKey(name))
+	}
+}
+
+// SetTrailers sets trailers to be sent once the body has been written. It
+// is called after writing the body, and whether or not the trailers were
+// declared. Trailers are only sent with chunked responses, so the response
+// must not have a Content-Length.
+func SetTrailers(w http.ResponseWriter, trailers http.Header) {
+	for k, v := range trailers {
+		w.Header()[http.TrailerPrefix+http.CanonicalHeaderKey(k)] = v
+	}
+}
@@ -0,0 +1,73 @@
+package nethttp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamResponse_Trailers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sum := sha256.New()
+		_ = StreamResponse{
+			ContentType:   "text/plain",
+			ContentLength: 5,
+			Body:          io.TeeReader(strings.NewReader("hello"), sum),
+			TrailerNames:  []string{"X-Checksum"},
+			Trailers: func(err error) http.Header {
+				return http.Header{"X-Checksum": {hex.EncodeToString(sum.Sum(nil))}}
+			},
+		}.VisitResponse(w)
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, int64(-1), resp.ContentLength)
+	assert.Contains(t, resp.Trailer, "X-Checksum")
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(body))
+	want := sha256.Sum256([]byte("hello"))
+	assert.Equal(t, hex.EncodeToString(want[:]), resp.Trailer.Get("X-Checksum"))
+}
+
+func TestStreamResponse_TrailersOnError(t *testing.T) {
+	readErr := errors.New("upstream failed")
+	rec := httptest.NewRecorder()
+	err := StreamResponse{
+		Body: iotest.ErrReader(readErr),
+		Trailers: func(err error) http.Header {
+			status := "ok"
+			if err != nil {
+				status = err.Error()
+			}
+			return http.Header{"X-Status": {status}}
+		},
+	}.VisitResponse(rec)
+	assert.ErrorIs(t, err, readErr)
+	assert.Equal(t, "upstream failed", rec.Result().Trailer.Get("X-Status"))
+}
+
+func TestSetTrailers(t *testing.T) {
+	rec := httptest.NewRecorder()
+	DeclareTrailers(rec, "x-checksum")
+	_, _ = rec.WriteString("body")
+	SetTrailers(rec, http.Header{"x-checksum": {"abc"}, "X-Undeclared": {"1"}})
+
+	result := rec.Result()
+	assert.Equal(t, "X-Checksum", rec.Header().Get("Trailer"))
+	assert.Equal(t, "abc", result.Trailer.Get("X-Checksum"))
+	assert.Equal(t, "1", result.Trailer.Get("X-Undeclared"))
+}
@@ -0,0 +1,62 @@
+package nethttp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ResponseValidatorFunc checks a typed response object returned by the
+// handler of the given operation, before it is serialized. It can run
+// schema validation, such as kin-openapi's, or application invariants.
+type ResponseValidatorFunc func(ctx context.Context, operationID string, response interface{}) error
+
+// ResponseValidationError is returned for responses which fail validation.
+type ResponseValidationError struct {
+	OperationID string
+	Err         error
+}
+
+func (e *ResponseValidationError) Error() string {
+	return fmt.Sprintf("operation %s: invalid response: %s", e.OperationID, e.Err)
+}
+
+func (e *ResponseValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ResponseValidationOptions configures ResponseValidationMiddleware.
+type ResponseValidationOptions struct {
+	// ReportOnly passes invalid responses on to the client after reporting
+	// them, which suits canary rollouts where a validation bug must not
+	// break production traffic.
+	ReportOnly bool
+	// OnInvalid, if set, is called for every response failing validation.
+	OnInvalid func(ctx context.Context, err *ResponseValidationError)
+}
+
+// ResponseValidationMiddleware returns a StrictHTTPMiddlewareFunc which
+// passes every successful response to validate. Invalid responses are
+// reported to opts.OnInvalid and, unless opts.ReportOnly is set, replaced by
+// a *ResponseValidationError for the strict error handler to answer, usually
+// with a 500.
+func ResponseValidationMiddleware(validate ResponseValidatorFunc, opts ResponseValidationOptions) StrictHTTPMiddlewareFunc {
+	return func(f StrictHTTPHandlerFunc, operationID string) StrictHTTPHandlerFunc {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+			response, err := f(ctx, w, r, request)
+			if err != nil || response == nil {
+				return response, err
+			}
+			if verr := validate(ctx, operationID, response); verr != nil {
+				invalid := &ResponseValidationError{OperationID: operationID, Err: verr}
+				if opts.OnInvalid != nil {
+					opts.OnInvalid(ctx, invalid)
+				}
+				if !opts.ReportOnly {
+					return nil, invalid
+				}
+			}
+			return response, nil
+		}
+	}
+}
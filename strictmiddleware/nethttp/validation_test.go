@@ -0,0 +1,58 @@
+package nethttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validatedPet struct {
+	Name string
+}
+
+func TestResponseValidationMiddleware(t *testing.T) {
+	validate := func(ctx context.Context, operationID string, response interface{}) error {
+		if pet, ok := response.(validatedPet); ok && pet.Name == "" {
+			return errors.New("name is required")
+		}
+		return nil
+	}
+	handler := func(pet validatedPet) StrictHTTPHandlerFunc {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+			return pet, nil
+		}
+	}
+	call := func(m StrictHTTPMiddlewareFunc, pet validatedPet) (interface{}, error) {
+		r := httptest.NewRequest(http.MethodGet, "/pets/1", nil)
+		return m(handler(pet), "getPet")(r.Context(), httptest.NewRecorder(), r, nil)
+	}
+
+	var reported []*ResponseValidationError
+	onInvalid := func(ctx context.Context, err *ResponseValidationError) {
+		reported = append(reported, err)
+	}
+
+	m := ResponseValidationMiddleware(validate, ResponseValidationOptions{OnInvalid: onInvalid})
+	response, err := call(m, validatedPet{Name: "rex"})
+	require.NoError(t, err)
+	assert.Equal(t, validatedPet{Name: "rex"}, response)
+
+	response, err = call(m, validatedPet{})
+	var invalid *ResponseValidationError
+	require.True(t, errors.As(err, &invalid))
+	assert.Equal(t, "getPet", invalid.OperationID)
+	assert.EqualError(t, invalid, "operation getPet: invalid response: name is required")
+	assert.Nil(t, response)
+	assert.Len(t, reported, 1)
+
+	m = ResponseValidationMiddleware(validate, ResponseValidationOptions{ReportOnly: true, OnInvalid: onInvalid})
+	response, err = call(m, validatedPet{})
+	require.NoError(t, err)
+	assert.Equal(t, validatedPet{}, response)
+	assert.Len(t, reported, 2)
+}
@@ -0,0 +1,121 @@
+package nethttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// WebSocketUpgradeFunc performs the WebSocket opening handshake, replying
+// 101 Switching Protocols with responseHeader, and returns the connection
+// it established. It is how a WebSocket library is plugged into a
+// WebSocketResponse; on a failed handshake it is expected to have written
+// an error response already, as libraries do. The Upgrade method of
+// github.com/gorilla/websocket's Upgrader has this signature, and
+// nhooyr.io/websocket's Accept only needs wrapping:
+//
+//	upgrade := func(w http.ResponseWriter, r *http.Request, h http.Header) (*websocket.Conn, error) {
+//		for k, v := range h {
+//			w.Header()[k] = v
+//		}
+//		return websocket.Accept(w, r, nil)
+//	}
+type WebSocketUpgradeFunc[C any] func(w http.ResponseWriter, r *http.Request, responseHeader http.Header) (C, error)
+
+// ErrNoWebSocketRequest is returned by a WebSocketResponse which has no
+// request to upgrade, usually because WebSocketMiddleware isn't installed.
+var ErrNoWebSocketRequest = errors.New("no request to upgrade to a WebSocket; is WebSocketMiddleware installed?")
+
+// WebSocketResponse is a Response which upgrades the connection to a
+// WebSocket and serves it, for operations modeling WebSocket endpoints, so
+// that they can be implemented in strict handlers along with the rest of the
+// API. Requests which don't ask for an upgrade are answered with 426 Upgrade
+// Required.
+type WebSocketResponse[C any] struct {
+	// Request is the request to upgrade. NewWebSocketResponse takes it from
+	// the context.
+	Request *http.Request
+	Upgrade WebSocketUpgradeFunc[C]
+	// Headers are sent with the 101 Switching Protocols response.
+	Headers http.Header
+	// Serve is called with the established connection, and should return
+	// once it is done with it. If the connection is an io.Closer, as
+	// gorilla's is, it is closed once Serve returns.
+	Serve func(conn C) error
+}
+
+// NewWebSocketResponse returns a WebSocketResponse upgrading the request
+// stored in ctx by WebSocketMiddleware.
+func NewWebSocketResponse[C any](ctx context.Context, upgrade WebSocketUpgradeFunc[C], serve func(conn C) error) WebSocketResponse[C] {
+	r, _ := WebSocketRequestFromContext(ctx)
+	return WebSocketResponse[C]{Request: r, Upgrade: upgrade, Serve: serve}
+}
+
+func (r WebSocketResponse[C]) VisitResponse(w http.ResponseWriter) error {
+	if r.Request == nil {
+		return ErrNoWebSocketRequest
+	}
+	if !IsWebSocketUpgrade(r.Request) {
+		w.Header().Set("Connection", "Upgrade")
+		w.Header().Set("Upgrade", "websocket")
+		http.Error(w, "this endpoint only accepts WebSocket connections", http.StatusUpgradeRequired)
+		return nil
+	}
+	conn, err := r.Upgrade(w, r.Request, r.Headers)
+	if err != nil {
+		return fmt.Errorf("error upgrading to a WebSocket: %w", err)
+	}
+	if c, ok := interface{}(conn).(io.Closer); ok {
+		defer func() { _ = c.Close() }()
+	}
+	if r.Serve == nil {
+		return nil
+	}
+	return r.Serve(conn)
+}
+
+// IsWebSocketUpgrade reports whether r asks for its connection to be
+// upgraded to a WebSocket.
+func IsWebSocketUpgrade(r *http.Request) bool {
+	return headerHasToken(r.Header, "Connection", "upgrade") &&
+		headerHasToken(r.Header, "Upgrade", "websocket")
+}
+
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type webSocketRequestContextKey struct{}
+
+// WithWebSocketRequest returns a copy of ctx which carries r, the request a
+// WebSocketResponse upgrades.
+func WithWebSocketRequest(ctx context.Context, r *http.Request) context.Context {
+	return context.WithValue(ctx, webSocketRequestContextKey{}, r)
+}
+
+// WebSocketRequestFromContext returns the request stored in ctx by
+// WithWebSocketRequest or WebSocketMiddleware.
+func WebSocketRequestFromContext(ctx context.Context) (*http.Request, bool) {
+	r, ok := ctx.Value(webSocketRequestContextKey{}).(*http.Request)
+	return r, ok
+}
+
+// WebSocketMiddleware is a StrictHTTPMiddlewareFunc which makes the
+// underlying *http.Request reachable from strict handler implementations,
+// which otherwise only receive a context and the typed request object, so
+// that they can return a WebSocketResponse.
+func WebSocketMiddleware(f StrictHTTPHandlerFunc, operationID string) StrictHTTPHandlerFunc {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return f(WithWebSocketRequest(ctx, r), w, r, request)
+	}
+}
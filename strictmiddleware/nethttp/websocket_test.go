@@ -0,0 +1,89 @@
+package nethttp
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rawUpgrade stands in for a WebSocket library, completing the handshake
+// without framing, so the test can exchange plain lines.
+func rawUpgrade(w http.ResponseWriter, r *http.Request, h http.Header) (net.Conn, error) {
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return nil, err
+	}
+	_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n")
+	_ = h.Write(rw)
+	_, _ = rw.WriteString("\r\n")
+	return conn, rw.Flush()
+}
+
+func TestWebSocketResponse(t *testing.T) {
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return NewWebSocketResponse(ctx, rawUpgrade, func(conn net.Conn) error {
+			line, err := bufio.NewReader(conn).ReadString('\n')
+			if err != nil {
+				return err
+			}
+			_, err = conn.Write([]byte("echo: " + line))
+			return err
+		}), nil
+	}
+	strict := WebSocketMiddleware(handler, "chat")
+	served := make(chan error, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, err := strict(r.Context(), w, r, nil)
+		if err == nil {
+			_, err = VisitResponse(w, resp)
+		}
+		served <- err
+	}))
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("GET /chat HTTP/1.1\r\nHost: example.com\r\nConnection: keep-alive, Upgrade\r\nUpgrade: websocket\r\n\r\n"))
+	require.NoError(t, err)
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+
+	_, err = conn.Write([]byte("hello\n"))
+	require.NoError(t, err)
+	line, err := br.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "echo: hello\n", line)
+	require.NoError(t, <-served)
+}
+
+func TestWebSocketResponse_NotAnUpgrade(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/chat", nil)
+	resp := NewWebSocketResponse(WithWebSocketRequest(req.Context(), req), rawUpgrade, nil)
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, resp.VisitResponse(rec))
+	assert.Equal(t, http.StatusUpgradeRequired, rec.Code)
+	assert.Equal(t, "websocket", rec.Header().Get("Upgrade"))
+}
+
+func TestWebSocketResponse_NoRequest(t *testing.T) {
+	resp := NewWebSocketResponse(context.Background(), rawUpgrade, nil)
+	assert.ErrorIs(t, resp.VisitResponse(httptest.NewRecorder()), ErrNoWebSocketRequest)
+}
+
+func TestIsWebSocketUpgrade(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/chat", nil)
+	assert.False(t, IsWebSocketUpgrade(req))
+	req.Header.Set("Connection", "keep-alive, Upgrade")
+	req.Header.Set("Upgrade", "WebSocket")
+	assert.True(t, IsWebSocketUpgrade(req))
+}
@@ -0,0 +1,104 @@
+package nethttp
+
+import (
+	"encoding"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/oapi-codegen/runtime"
+)
+
+// WriteResponse writes v as the body of a response with the given status
+// code and content type, choosing the serialization from the media type:
+//
+//   - An io.Reader is streamed whatever the media type, and closed if it is
+//     an io.Closer.
+//   - JSON media types, including +json suffixes, are encoded with the
+//     runtime's JSONCodec.
+//   - XML media types, including +xml suffixes, are encoded with
+//     encoding/xml.
+//   - Otherwise, v must already be a body: a []byte, a string, or an
+//     encoding.TextMarshaler.
+//
+// A nil v, or a nil pointer, writes the status code only. The Content-Type header is set unless
+// contentType is empty. Response unions generated for an operation can use
+// WriteResponse to implement Response for each of their members, rather than
+// duplicating this per operation.
+func WriteResponse(w http.ResponseWriter, statusCode int, contentType string, v interface{}) error {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		w.WriteHeader(statusCode)
+		return nil
+	}
+	if r, ok := v.(io.Reader); ok {
+		if c, ok := r.(io.Closer); ok {
+			defer func() { _ = c.Close() }()
+		}
+		setContentType(w, contentType)
+		w.WriteHeader(statusCode)
+		_, err := io.Copy(w, r)
+		return err
+	}
+	mediaType := ""
+	if contentType != "" {
+		var err error
+		if mediaType, _, err = mime.ParseMediaType(contentType); err != nil {
+			return fmt.Errorf("invalid content type '%s': %w", contentType, err)
+		}
+	}
+
+	var body []byte
+	var err error
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		body, err = runtime.GetJSONCodec().Marshal(v)
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		body, err = xml.Marshal(v)
+	default:
+		switch b := v.(type) {
+		case []byte:
+			body = b
+		case string:
+			body = []byte(b)
+		case encoding.TextMarshaler:
+			body, err = b.MarshalText()
+		default:
+			return fmt.Errorf("can not write %T as '%s'", v, contentType)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("error encoding '%s' response: %w", mediaType, err)
+	}
+	// Only send the status line once the body has been encoded, so that an
+	// encoding error can still be answered with an error response.
+	setContentType(w, contentType)
+	w.WriteHeader(statusCode)
+	_, err = w.Write(body)
+	return err
+}
+
+func setContentType(w http.ResponseWriter, contentType string) {
+	if contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+}
+
+// ContentResponse is a Response which writes Body through WriteResponse,
+// for response types which don't need anything more specific.
+type ContentResponse struct {
+	StatusCode  int
+	ContentType string
+	Headers     http.Header
+	Body        interface{}
+}
+
+func (r ContentResponse) VisitResponse(w http.ResponseWriter) error {
+	for k, v := range r.Headers {
+		w.Header()[k] = v
+	}
+	return WriteResponse(w, r.StatusCode, r.ContentType, r.Body)
+}
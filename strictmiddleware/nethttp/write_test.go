@@ -0,0 +1,107 @@
+package nethttp
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oapi-codegen/runtime"
+)
+
+type writePet struct {
+	Name string `json:"name" xml:"name"`
+}
+
+func TestWriteResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        interface{}
+		expected    string
+	}{
+		{"json", "application/json", writePet{Name: "rex"}, `{"name":"rex"}`},
+		{"problem json", "application/problem+json; charset=utf-8", map[string]int{"status": 400}, `{"status":400}`},
+		{"xml", "application/xml", writePet{Name: "rex"}, `<writePet><name>rex</name></writePet>`},
+		{"text", "text/plain", "pong", "pong"},
+		{"bytes", "application/octet-stream", []byte{1, 2}, "\x01\x02"},
+		{"reader", "application/octet-stream", io.NopCloser(strings.NewReader("stream")), "stream"},
+		{"json reader", "application/json", strings.NewReader(`{"name":"rex"}`), `{"name":"rex"}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			require.NoError(t, WriteResponse(w, http.StatusCreated, tc.contentType, tc.body))
+			assert.Equal(t, http.StatusCreated, w.Code)
+			assert.Equal(t, tc.contentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tc.expected, w.Body.String())
+		})
+	}
+
+	t.Run("no body", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		require.NoError(t, WriteResponse(w, http.StatusNoContent, "", nil))
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Type"))
+
+		w = httptest.NewRecorder()
+		require.NoError(t, WriteResponse(w, http.StatusNotFound, "application/json", (*writePet)(nil)))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("encoding error leaves response unwritten", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		assert.Error(t, WriteResponse(w, http.StatusOK, "application/json", func() {}))
+		assert.False(t, w.Flushed)
+		assert.Empty(t, w.Body.String())
+		assert.Empty(t, w.Header())
+	})
+
+	t.Run("unsupported body", func(t *testing.T) {
+		assert.Error(t, WriteResponse(httptest.NewRecorder(), http.StatusOK, "text/plain", 42))
+	})
+}
+
+func TestContentResponse(t *testing.T) {
+	w := httptest.NewRecorder()
+	handled, err := VisitResponse(w, ContentResponse{
+		StatusCode:  http.StatusOK,
+		ContentType: "application/json",
+		Headers:     http.Header{"X-Request-Id": {"1"}},
+		Body:        writePet{Name: "rex"},
+	})
+	require.NoError(t, err)
+	assert.True(t, handled)
+	assert.Equal(t, "1", w.Header().Get("X-Request-Id"))
+	assert.Equal(t, `{"name":"rex"}`, w.Body.String())
+}
+
+// countingCodec wraps encoding/json, counting how often it marshals.
+type countingCodec struct {
+	runtime.StdJSONCodec
+	marshals int
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshals++
+	return c.StdJSONCodec.Marshal(v)
+}
+
+func TestJSONCodec(t *testing.T) {
+	codec := &countingCodec{}
+	runtime.SetJSONCodec(codec)
+	defer runtime.SetJSONCodec(nil)
+
+	require.NoError(t, WriteResponse(httptest.NewRecorder(), http.StatusOK, "application/json", writePet{Name: "rex"}))
+	w := httptest.NewRecorder()
+	require.NoError(t, JSONResponse{StatusCode: http.StatusOK, Body: writePet{Name: "rex"}}.VisitResponse(w))
+	assert.Equal(t, "{\"name\":\"rex\"}\n", w.Body.String())
+	sse := NewSSEWriter(httptest.NewRecorder())
+	require.NoError(t, sse.WriteEvent("pet", "", writePet{Name: "rex"}))
+	assert.Equal(t, 3, codec.marshals)
+}
@@ -24,6 +24,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -53,8 +54,26 @@ func StyleParam(style string, explode bool, paramName string, value interface{})
 
 // Given an input value, such as a primitive type, array or object, turn it
 // into a parameter based on style/explode definition, performing whatever
-// escaping is necessary based on parameter location
+// escaping is necessary based on parameter location. The parameter name is
+// escaped along with the value, so that names containing reserved
+// characters still produce a valid query string or path. Errors name the
+// parameter and its location, as rendered by DescribeParam.
+//
+// The output is deterministic: the properties of objects and the keys of
+// maps are always written sorted by name, byte-wise, with non-string map
+// keys compared in their styled form, so that the same value produces the
+// same URL on every call.
 func StyleParamWithLocation(style string, explode bool, paramName string, paramLocation ParamLocation, value interface{}) (string, error) {
+	s, err := styleParam(style, explode, escapeParameterString(paramName, paramLocation), paramLocation, value)
+	if err != nil {
+		return "", fmt.Errorf("error styling %s: %w", DescribeParam(paramName, paramLocation), err)
+	}
+	return s, nil
+}
+
+// styleParam is StyleParamWithLocation for a paramName which is already
+// escaped.
+func styleParam(style string, explode bool, paramName string, paramLocation ParamLocation, value interface{}) (string, error) {
 	t := reflect.TypeOf(value)
 	v := reflect.ValueOf(value)
 
@@ -80,7 +99,7 @@ func StyleParamWithLocation(style string, explode bool, paramName string, paramL
 		if !convertableToTime && !convertableToDate {
 			b, err := tu.MarshalText()
 			if err != nil {
-				return "", fmt.Errorf("error marshaling '%s' as text: %s", value, err)
+				return "", fmt.Errorf("error marshaling '%s' as text: %w", value, err)
 			}
 
 			return stylePrimitive(style, explode, paramName, paramLocation, string(b))
@@ -109,7 +128,7 @@ func styleSlice(style string, explode bool, paramName string, paramLocation Para
 		if !explode {
 			return "", errors.New("deepObjects must be exploded")
 		}
-		return MarshalDeepObject(values, paramName)
+		return marshalDeepObjectParam(values, paramName)
 	}
 
 	var prefix string
@@ -126,28 +145,28 @@ func styleSlice(style string, explode bool, paramName string, paramLocation Para
 			separator = ","
 		}
 	case "matrix":
-		prefix = fmt.Sprintf(";%s=", paramName)
+		prefix = ";" + paramName + "="
 		if explode {
 			separator = prefix
 		} else {
 			separator = ","
 		}
 	case "form":
-		prefix = fmt.Sprintf("%s=", paramName)
+		prefix = paramName + "="
 		if explode {
 			separator = "&" + prefix
 		} else {
 			separator = ","
 		}
 	case "spaceDelimited":
-		prefix = fmt.Sprintf("%s=", paramName)
+		prefix = paramName + "="
 		if explode {
 			separator = "&" + prefix
 		} else {
 			separator = " "
 		}
 	case "pipeDelimited":
-		prefix = fmt.Sprintf("%s=", paramName)
+		prefix = paramName + "="
 		if explode {
 			separator = "&" + prefix
 		} else {
@@ -158,18 +177,51 @@ func styleSlice(style string, explode bool, paramName string, paramLocation Para
 	}
 
 	// We're going to assume here that the array is one of simple types.
-	var err error
-	var part string
-	parts := make([]string, len(values))
+	buf := getStyleBuffer()
+	defer putStyleBuffer(buf)
+	buf.WriteString(prefix)
 	for i, v := range values {
-		part, err = primitiveToString(v)
-		part = escapeParameterString(part, paramLocation)
-		parts[i] = part
+		if i > 0 {
+			buf.WriteString(separator)
+		}
+		if inner, ok := nestedSliceValue(v); ok {
+			// The items of an inner array are separated by commas, so the
+			// outer array's can't be.
+			if separator == "," {
+				return "", ErrNestedArrayStyle
+			}
+			for j := 0; j < inner.Len(); j++ {
+				part, err := primitiveToString(inner.Index(j).Interface())
+				if err != nil {
+					return "", fmt.Errorf("error formatting item %d.%d: %w", i, j, err)
+				}
+				if j > 0 {
+					buf.WriteByte(',')
+				}
+				buf.WriteString(escapeParameterString(part, paramLocation))
+			}
+			continue
+		}
+		part, err := primitiveToString(v)
 		if err != nil {
-			return "", fmt.Errorf("error formatting '%s': %s", paramName, err)
+			return "", fmt.Errorf("error formatting item %d: %w", i, err)
 		}
+		buf.WriteString(escapeParameterString(part, paramLocation))
 	}
-	return prefix + strings.Join(parts, separator), nil
+	return buf.String(), nil
+}
+
+// nestedSliceValue returns the array v holds, if it is the item of an array
+// of arrays.
+func nestedSliceValue(v interface{}) (reflect.Value, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return reflect.Value{}, false
+	}
+	return rv, true
 }
 
 func sortedKeys(strMap map[string]string) []string {
@@ -223,7 +275,7 @@ func styleStruct(style string, explode bool, paramName string, paramLocation Par
 		if !explode {
 			return "", errors.New("deepObjects must be exploded")
 		}
-		return MarshalDeepObject(value, paramName)
+		return marshalDeepObjectParam(value, paramName)
 	}
 
 	// If input has Marshaler, such as object has Additional Property or AnyOf,
@@ -233,14 +285,14 @@ func styleStruct(style string, explode bool, paramName string, paramLocation Par
 		if err != nil {
 			return "", fmt.Errorf("failed to marshal input to JSON: %w", err)
 		}
-		e := json.NewDecoder(bytes.NewReader(buf))
+		e := newJSONDecoder(bytes.NewReader(buf))
 		e.UseNumber()
 		var i2 interface{}
 		err = e.Decode(&i2)
 		if err != nil {
 			return "", fmt.Errorf("failed to unmarshal JSON: %w", err)
 		}
-		s, err := StyleParamWithLocation(style, explode, paramName, paramLocation, i2)
+		s, err := styleParam(style, explode, paramName, paramLocation, i2)
 		if err != nil {
 			return "", fmt.Errorf("error style JSON structure: %w", err)
 		}
@@ -274,7 +326,7 @@ func styleStruct(style string, explode bool, paramName string, paramLocation Par
 		}
 		str, err := primitiveToString(f.Interface())
 		if err != nil {
-			return "", fmt.Errorf("error formatting '%s': %s", paramName, err)
+			return "", fmt.Errorf("error formatting field '%s': %w", fieldName, err)
 		}
 		fieldDict[fieldName] = str
 	}
@@ -287,41 +339,26 @@ func styleMap(style string, explode bool, paramName string, paramLocation ParamL
 		if !explode {
 			return "", errors.New("deepObjects must be exploded")
 		}
-		return MarshalDeepObject(value, paramName)
+		return marshalDeepObjectParam(value, paramName)
 	}
 	v := reflect.ValueOf(value)
 
 	fieldDict := make(map[string]string)
-	for _, fieldName := range v.MapKeys() {
-		str, err := primitiveToString(v.MapIndex(fieldName).Interface())
+	for _, key := range v.MapKeys() {
+		fieldName, err := primitiveToString(key.Interface())
+		if err != nil {
+			return "", fmt.Errorf("error formatting key '%v': %w", key, err)
+		}
+		str, err := primitiveToString(v.MapIndex(key).Interface())
 		if err != nil {
-			return "", fmt.Errorf("error formatting '%s': %s", paramName, err)
+			return "", fmt.Errorf("error formatting key '%s': %w", fieldName, err)
 		}
-		fieldDict[fieldName.String()] = str
+		fieldDict[fieldName] = str
 	}
 	return processFieldDict(style, explode, paramName, paramLocation, fieldDict)
 }
 
 func processFieldDict(style string, explode bool, paramName string, paramLocation ParamLocation, fieldDict map[string]string) (string, error) {
-	var parts []string
-
-	// This works for everything except deepObject. We'll handle that one
-	// separately.
-	if style != "deepObject" {
-		if explode {
-			for _, k := range sortedKeys(fieldDict) {
-				v := escapeParameterString(fieldDict[k], paramLocation)
-				parts = append(parts, k+"="+v)
-			}
-		} else {
-			for _, k := range sortedKeys(fieldDict) {
-				v := escapeParameterString(fieldDict[k], paramLocation)
-				parts = append(parts, k)
-				parts = append(parts, v)
-			}
-		}
-	}
-
 	var prefix string
 	var separator string
 
@@ -341,32 +378,50 @@ func processFieldDict(style string, explode bool, paramName string, paramLocatio
 			prefix = ";"
 		} else {
 			separator = ","
-			prefix = fmt.Sprintf(";%s=", paramName)
+			prefix = ";" + paramName + "="
 		}
 	case "form":
 		if explode {
 			separator = "&"
 		} else {
-			prefix = fmt.Sprintf("%s=", paramName)
+			prefix = paramName + "="
 			separator = ","
 		}
 	case "deepObject":
-		{
-			if !explode {
-				return "", fmt.Errorf("deepObject parameters must be exploded")
-			}
-			for _, k := range sortedKeys(fieldDict) {
-				v := fieldDict[k]
-				part := fmt.Sprintf("%s[%s]=%s", paramName, k, v)
-				parts = append(parts, part)
-			}
-			separator = "&"
+		if !explode {
+			return "", fmt.Errorf("deepObject parameters must be exploded")
 		}
+		separator = "&"
 	default:
 		return "", fmt.Errorf("unsupported style '%s'", style)
 	}
 
-	return prefix + strings.Join(parts, separator), nil
+	buf := getStyleBuffer()
+	defer putStyleBuffer(buf)
+	buf.WriteString(prefix)
+	for i, k := range sortedKeys(fieldDict) {
+		if i > 0 {
+			buf.WriteString(separator)
+		}
+		switch {
+		case style == "deepObject":
+			// deepObject values aren't escaped.
+			buf.WriteString(paramName)
+			buf.WriteByte('[')
+			buf.WriteString(k)
+			buf.WriteString("]=")
+			buf.WriteString(fieldDict[k])
+		case explode:
+			buf.WriteString(k)
+			buf.WriteByte('=')
+			buf.WriteString(escapeParameterString(fieldDict[k], paramLocation))
+		default:
+			buf.WriteString(k)
+			buf.WriteString(separator)
+			buf.WriteString(escapeParameterString(fieldDict[k], paramLocation))
+		}
+	}
+	return buf.String(), nil
 }
 
 func stylePrimitive(style string, explode bool, paramName string, paramLocation ParamLocation, value interface{}) (string, error) {
@@ -381,9 +436,9 @@ func stylePrimitive(style string, explode bool, paramName string, paramLocation
 	case "label":
 		prefix = "."
 	case "matrix":
-		prefix = fmt.Sprintf(";%s=", paramName)
+		prefix = ";" + paramName + "="
 	case "form":
-		prefix = fmt.Sprintf("%s=", paramName)
+		prefix = paramName + "="
 	default:
 		return "", fmt.Errorf("unsupported style '%s'", style)
 	}
@@ -435,7 +490,7 @@ func primitiveToString(value interface{}) (string, error) {
 			if err != nil {
 				return "", fmt.Errorf("failed to marshal input to JSON: %w", err)
 			}
-			e := json.NewDecoder(bytes.NewReader(buf))
+			e := newJSONDecoder(bytes.NewReader(buf))
 			e.UseNumber()
 			var i2 interface{}
 			err = e.Decode(&i2)
@@ -460,6 +515,28 @@ func primitiveToString(value interface{}) (string, error) {
 	return output, nil
 }
 
+// styleBufferPool holds the buffers styled parameters are assembled in, so
+// that high-QPS clients don't allocate a new one for every parameter.
+var styleBufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// maxPooledStyleBuffer keeps the buffers of unusually large parameters
+// from being retained by the pool.
+const maxPooledStyleBuffer = 64 << 10
+
+func getStyleBuffer() *bytes.Buffer {
+	return styleBufferPool.Get().(*bytes.Buffer)
+}
+
+func putStyleBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledStyleBuffer {
+		return
+	}
+	buf.Reset()
+	styleBufferPool.Put(buf)
+}
+
 // escapeParameterString escapes a parameter value bas on the location of that parameter.
 // Query params and path params need different kinds of escaping, while header
 // and cookie params seem not to need escaping.
@@ -15,12 +15,15 @@ package runtime
 
 import (
 	"fmt"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/oapi-codegen/runtime/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStyleParam(t *testing.T) {
@@ -720,3 +723,112 @@ func TestIssue37(t *testing.T) {
 		}
 	}
 }
+
+func BenchmarkStyleParamWithLocation(b *testing.B) {
+	type Object struct {
+		FirstName string `json:"firstName"`
+		Role      string `json:"role"`
+	}
+	cases := []struct {
+		style   string
+		explode bool
+		value   interface{}
+	}{
+		{"form", true, 5},
+		{"form", true, []int{3, 4, 5, 6}},
+		{"form", false, []string{"a b", "c&d", "e"}},
+		{"matrix", true, []int{3, 4, 5}},
+		{"simple", false, Object{FirstName: "Alex", Role: "admin"}},
+		{"form", true, map[string]string{"firstName": "Alex", "role": "admin"}},
+		{"deepObject", true, Object{FirstName: "Alex", Role: "admin"}},
+	}
+	for _, c := range cases {
+		b.Run(fmt.Sprintf("%s/explode=%v/%T", c.style, c.explode, c.value), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := StyleParamWithLocation(c.style, c.explode, "id", ParamLocationQuery, c.value); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestStyleParamEscapesName(t *testing.T) {
+	result, err := StyleParamWithLocation("form", true, "filter[name]", ParamLocationQuery, "a b")
+	require.NoError(t, err)
+	assert.Equal(t, "filter%5Bname%5D=a+b", result)
+	parsed, err := url.ParseQuery(result)
+	require.NoError(t, err)
+	var value string
+	require.NoError(t, BindQueryParameter("form", true, true, "filter[name]", parsed, &value))
+	assert.Equal(t, "a b", value)
+
+	result, err = StyleParamWithLocation("form", false, "ids list", ParamLocationQuery, []int{1, 2})
+	require.NoError(t, err)
+	assert.Equal(t, "ids+list=1,2", result)
+
+	result, err = StyleParamWithLocation("matrix", false, "ünï", ParamLocationPath, []int{5, 6})
+	require.NoError(t, err)
+	assert.Equal(t, ";%C3%BCn%C3%AF=5,6", result)
+	var ids []int
+	require.NoError(t, BindStyledParameterWithOptions("matrix", "ünï", result, &ids, BindStyledParameterOptions{ParamLocation: ParamLocationPath}))
+	assert.Equal(t, []int{5, 6}, ids)
+
+	result, err = StyleParamWithLocation("deepObject", true, "my obj", ParamLocationQuery, map[string]string{"k": "v"})
+	require.NoError(t, err)
+	assert.Equal(t, "my+obj[k]=v", result)
+	parsed, err = url.ParseQuery(result)
+	require.NoError(t, err)
+	var obj map[string]string
+	require.NoError(t, UnmarshalDeepObject(&obj, "my obj", parsed))
+	assert.Equal(t, map[string]string{"k": "v"}, obj)
+
+	result, err = StyleParamWithLocation("form", true, "name", ParamLocationQuery, "v")
+	require.NoError(t, err)
+	assert.Equal(t, "name=v", result)
+}
+
+func TestStyleParamNestedArrays(t *testing.T) {
+	matrix := [][]int{{1, 2}, {3, 4}}
+
+	result, err := StyleParamWithLocation("form", true, "m", ParamLocationQuery, matrix)
+	require.NoError(t, err)
+	assert.Equal(t, "m=1,2&m=3,4", result)
+
+	result, err = StyleParamWithLocation("matrix", true, "m", ParamLocationPath, matrix)
+	require.NoError(t, err)
+	assert.Equal(t, ";m=1,2;m=3,4", result)
+
+	result, err = StyleParamWithLocation("pipeDelimited", false, "m", ParamLocationQuery, matrix)
+	require.NoError(t, err)
+	assert.Equal(t, "m=1,2|3,4", result)
+
+	_, err = StyleParamWithLocation("form", false, "m", ParamLocationQuery, matrix)
+	assert.ErrorIs(t, err, ErrNestedArrayStyle)
+	_, err = StyleParamWithLocation("simple", false, "m", ParamLocationPath, matrix)
+	assert.ErrorIs(t, err, ErrNestedArrayStyle)
+}
+
+func TestStyleParamSortsKeys(t *testing.T) {
+	m := map[string]int{}
+	for i := 0; i < 20; i++ {
+		m[fmt.Sprintf("k%02d", i)] = i
+	}
+	first, err := StyleParamWithLocation("form", true, "m", ParamLocationQuery, m)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(first, "k00=0&k01=1&k02=2&"))
+	for i := 0; i < 10; i++ {
+		again, err := StyleParamWithLocation("form", true, "m", ParamLocationQuery, m)
+		require.NoError(t, err)
+		assert.Equal(t, first, again)
+	}
+
+	result, err := StyleParamWithLocation("simple", false, "m", ParamLocationPath, map[int]string{2: "b", 10: "a", 1: "c"})
+	require.NoError(t, err)
+	assert.Equal(t, "1,c,10,a,2,b", result)
+
+	result, err = MarshalDeepObject(map[string]interface{}{"z": 1, "a": map[string]int{"y": 2, "b": 3}}, "p")
+	require.NoError(t, err)
+	assert.Equal(t, "p[a][b]=3&p[a][y]=2&p[z]=1", result)
+}
@@ -0,0 +1,30 @@
+package runtime
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ReadTrailers returns the trailers of resp, which are only known once its
+// body has been read to the end. Whatever is left unread of the body is
+// discarded first, and the body is closed, so ReadTrailers is called once
+// the caller is done with the body, for example to check a checksum the
+// server sent after it. Trailers the server declared but didn't send are
+// left out.
+func ReadTrailers(resp *http.Response) (http.Header, error) {
+	if resp.Body != nil {
+		_, err := io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading response body: %w", err)
+		}
+	}
+	trailers := make(http.Header, len(resp.Trailer))
+	for k, v := range resp.Trailer {
+		if len(v) > 0 {
+			trailers[k] = v
+		}
+	}
+	return trailers, nil
+}
@@ -0,0 +1,38 @@
+package runtime
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadTrailers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", "Digest, X-Missing")
+		_, _ = io.WriteString(w, "payload")
+		w.Header().Set("Digest", "sha-256=abc")
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	// Only part of the body is read; ReadTrailers discards the rest.
+	buf := make([]byte, 3)
+	_, err = io.ReadFull(resp.Body, buf)
+	require.NoError(t, err)
+
+	trailers, err := ReadTrailers(resp)
+	require.NoError(t, err)
+	assert.Equal(t, http.Header{"Digest": {"sha-256=abc"}}, trailers)
+}
+
+func TestReadTrailers_NoTrailers(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody}
+	trailers, err := ReadTrailers(resp)
+	require.NoError(t, err)
+	assert.Empty(t, trailers)
+}
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// Any holds an arbitrary JSON value, for untyped schemas and properties.
+// Unlike a plain interface{}, it unmarshals numbers, including those nested
+// in objects and arrays, as json.Number instead of float64, so that 64-bit
+// integers and decimals keep their precision through a round trip.
+type Any struct {
+	Value interface{}
+}
+
+func (a Any) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.Value)
+}
+
+func (a *Any) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := UnmarshalUseNumber(data, &v); err != nil {
+		return err
+	}
+	a.Value = v
+	return nil
+}
+
+// UnmarshalUseNumber is json.Unmarshal, except that numbers unmarshaled into
+// interface{} values become json.Number instead of float64.
+func UnmarshalUseNumber(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	// Reject trailing data, as json.Unmarshal does.
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("invalid data after top-level value")
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAny(t *testing.T) {
+	var v struct {
+		Meta Any `json:"meta"`
+	}
+	data := `{"meta":{"id":1234567890123456789,"price":0.1,"ids":[9007199254740993]}}`
+	require.NoError(t, json.Unmarshal([]byte(data), &v))
+	meta, ok := v.Meta.Value.(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, json.Number("1234567890123456789"), meta["id"])
+	assert.Equal(t, json.Number("0.1"), meta["price"])
+	assert.Equal(t, []interface{}{json.Number("9007199254740993")}, meta["ids"])
+
+	out, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.JSONEq(t, data, string(out))
+	assert.Contains(t, string(out), "9007199254740993")
+}
+
+func TestUnmarshalUseNumber(t *testing.T) {
+	var v interface{}
+	require.NoError(t, UnmarshalUseNumber([]byte(" 12 "), &v))
+	assert.Equal(t, json.Number("12"), v)
+	assert.Error(t, UnmarshalUseNumber([]byte("1 2"), &v))
+	assert.Error(t, UnmarshalUseNumber([]byte("1 ]"), &v))
+	assert.Error(t, UnmarshalUseNumber([]byte(`{"a":1}}`), &v))
+	assert.Error(t, UnmarshalUseNumber([]byte("{"), &v))
+}
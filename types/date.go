@@ -2,6 +2,8 @@ package types
 
 import (
 	"encoding/json"
+	"fmt"
+	"sync/atomic"
 	"time"
 )
 
@@ -25,8 +27,7 @@ func (d *Date) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
-	d.Time = parsed
-	return nil
+	return d.set(parsed, dateRange.Load())
 }
 
 func (d Date) String() string {
@@ -38,6 +39,116 @@ func (d *Date) UnmarshalText(data []byte) error {
 	if err != nil {
 		return err
 	}
+	return d.set(parsed, dateRange.Load())
+}
+
+// set stores parsed in d, provided it is within r, if r isn't nil.
+func (d *Date) set(parsed time.Time, r *DateRange) error {
+	if r != nil {
+		if err := r.Check(Date{Time: parsed}); err != nil {
+			return err
+		}
+	}
 	d.Time = parsed
 	return nil
 }
+
+// DateRange bounds the dates Date accepts when unmarshaling.
+type DateRange struct {
+	// Min is the earliest date allowed. A zero Min leaves the range open.
+	Min Date
+	// Max is the latest date allowed. A zero Max leaves the range open.
+	Max Date
+	// NotInFuture disallows dates after the current date in UTC.
+	NotInFuture bool
+}
+
+// DateOutOfRangeError is returned when a date outside the range set with
+// SetDateRange is unmarshaled into a Date. Min or Max is zero when the range
+// is open on that side.
+type DateOutOfRangeError struct {
+	Date Date
+	Min  Date
+	Max  Date
+}
+
+func (e *DateOutOfRangeError) Error() string {
+	if !e.Min.IsZero() && e.Date.Before(e.Min.Time) {
+		return fmt.Sprintf("date %s is before %s", e.Date, e.Min)
+	}
+	return fmt.Sprintf("date %s is after %s", e.Date, e.Max)
+}
+
+var dateRange atomic.Pointer[DateRange]
+
+// SetDateRange sets the range of dates which unmarshaling and binding a Date
+// accepts, failing with a *DateOutOfRangeError otherwise, so that bounds
+// such as "not before 1900" are enforced in one place. The zero DateRange
+// accepts every date, which is the default. Fields which need a range of
+// their own use RangedDate instead. It panics after FreezeSettings.
+func SetDateRange(r DateRange) {
+	checkSettingsFrozen("SetDateRange")
+	if r == (DateRange{}) {
+		dateRange.Store(nil)
+		return
+	}
+	dateRange.Store(&r)
+}
+
+// Check returns a *DateOutOfRangeError if d is outside r.
+func (r DateRange) Check(d Date) error {
+	max := r.Max
+	if r.NotInFuture {
+		today := Date{Time: time.Now().UTC().Truncate(24 * time.Hour)}
+		if max.IsZero() || today.Before(max.Time) {
+			max = today
+		}
+	}
+	if (!r.Min.IsZero() && d.Before(r.Min.Time)) || (!max.IsZero() && d.After(max.Time)) {
+		return &DateOutOfRangeError{Date: d, Min: r.Min, Max: max}
+	}
+	return nil
+}
+
+// DateRanger is implemented by types naming the range of dates a RangedDate
+// accepts. DateRange must work on the zero value.
+type DateRanger interface {
+	DateRange() DateRange
+}
+
+// RangedDate is a Date which only accepts dates within the range R names
+// when unmarshaling and binding, rather than the one set with SetDateRange,
+// so that fields can be bounded each their own way, such as a birth date
+// never in the future and an expiry date never in the past.
+type RangedDate[R DateRanger] struct {
+	Date
+}
+
+// Range returns the range R names.
+func (d RangedDate[R]) Range() DateRange {
+	var r R
+	return r.DateRange()
+}
+
+func (d *RangedDate[R]) UnmarshalJSON(data []byte) error {
+	var dateStr string
+	if err := json.Unmarshal(data, &dateStr); err != nil {
+		return err
+	}
+	return d.UnmarshalText([]byte(dateStr))
+}
+
+func (d *RangedDate[R]) UnmarshalText(data []byte) error {
+	parsed, err := time.Parse(DateFormat, string(data))
+	if err != nil {
+		return err
+	}
+	r := d.Range()
+	return d.Date.set(parsed, &r)
+}
+
+// Bind implements runtime.Binder, so that RangedDates can be bound from
+// parameters wherever a struct would otherwise be expected.
+func (d *RangedDate[R]) Bind(src string) error {
+	return d.UnmarshalText([]byte(src))
+}
@@ -2,11 +2,13 @@ package types
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDate_MarshalJSON(t *testing.T) {
@@ -63,3 +65,60 @@ func TestDate_UnmarshalText(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, testDate, date.Time)
 }
+
+func TestDate_Range(t *testing.T) {
+	defer SetDateRange(DateRange{})
+	SetDateRange(DateRange{
+		Min:         Date{time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)},
+		NotInFuture: true,
+	})
+
+	var d Date
+	assert.NoError(t, d.UnmarshalText([]byte("1900-01-01")))
+
+	err := json.Unmarshal([]byte(`"1899-12-31"`), &d)
+	var rangeErr *DateOutOfRangeError
+	require.True(t, errors.As(err, &rangeErr))
+	assert.Equal(t, "1899-12-31", rangeErr.Date.String())
+	assert.EqualError(t, err, "date 1899-12-31 is before 1900-01-01")
+	assert.Equal(t, "1900-01-01", d.String(), "d is left untouched")
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format(DateFormat)
+	err = d.UnmarshalText([]byte(tomorrow))
+	require.True(t, errors.As(err, &rangeErr))
+
+	SetDateRange(DateRange{})
+	assert.NoError(t, d.UnmarshalText([]byte(tomorrow)))
+}
+
+func TestDateRange_Check(t *testing.T) {
+	r := DateRange{Max: Date{time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)}}
+	assert.NoError(t, r.Check(Date{time.Date(1800, 1, 1, 0, 0, 0, 0, time.UTC)}))
+	assert.EqualError(t, r.Check(Date{time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)}), "date 2000-01-02 is after 2000-01-01")
+}
+
+// since1900 bounds dates to 1900 and after.
+type since1900 struct{}
+
+func (since1900) DateRange() DateRange {
+	return DateRange{Min: Date{time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)}}
+}
+
+func TestRangedDate(t *testing.T) {
+	// The package-wide range doesn't apply.
+	defer SetDateRange(DateRange{})
+	SetDateRange(DateRange{Max: Date{time.Date(1800, 1, 1, 0, 0, 0, 0, time.UTC)}})
+
+	var d RangedDate[since1900]
+	require.NoError(t, json.Unmarshal([]byte(`"2000-02-03"`), &d))
+	assert.Equal(t, "2000-02-03", d.String())
+	data, err := json.Marshal(d)
+	require.NoError(t, err)
+	assert.Equal(t, `"2000-02-03"`, string(data))
+
+	var rangeErr *DateOutOfRangeError
+	assert.ErrorAs(t, json.Unmarshal([]byte(`"1899-12-31"`), &d), &rangeErr)
+	assert.ErrorAs(t, d.Bind("1899-12-31"), &rangeErr)
+	assert.Error(t, d.UnmarshalText([]byte("yesterday")))
+	assert.Equal(t, "2000-02-03", d.String(), "d is left untouched")
+}
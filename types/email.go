@@ -3,6 +3,9 @@ package types
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
 )
 
 // ErrValidationEmail is the sentinel error returned when an email fails validation
@@ -36,5 +39,81 @@ func (e *Email) UnmarshalJSON(data []byte) error {
 		return ErrValidationEmail
 	}
 
+	return checkEmailDomain(s)
+}
+
+// UnmarshalText sets e to text. While a policy is installed with
+// SetEmailDomainPolicy, it validates text like UnmarshalJSON does, so that
+// parameters bound into an Email are held to the policy as bodies are;
+// otherwise it accepts any text, as parameter binding always has.
+func (e *Email) UnmarshalText(text []byte) error {
+	s := string(text)
+	if emailDomainPolicy.Load() == nil {
+		*e = Email(s)
+		return nil
+	}
+	if !emailRegex.MatchString(s) {
+		return ErrValidationEmail
+	}
+	if err := checkEmailDomain(s); err != nil {
+		return err
+	}
+	*e = Email(s)
+	return nil
+}
+
+// Domain returns the domain part of e, after its last '@'.
+func (e Email) Domain() string {
+	s := string(e)
+	return s[strings.LastIndexByte(s, '@')+1:]
+}
+
+// EmailDomainPolicy decides whether addresses at domain are accepted,
+// returning an error to reject them.
+type EmailDomainPolicy func(domain string) error
+
+// EmailDomainError is returned when the policy set with
+// SetEmailDomainPolicy rejects an email address's domain.
+type EmailDomainError struct {
+	Domain string
+	Err    error
+}
+
+func (e *EmailDomainError) Error() string {
+	return fmt.Sprintf("email: domain '%s' is not allowed: %s", e.Domain, e.Err)
+}
+
+func (e *EmailDomainError) Unwrap() error {
+	return e.Err
+}
+
+type emailDomainPolicyHolder struct {
+	policy EmailDomainPolicy
+}
+
+var emailDomainPolicy atomic.Pointer[emailDomainPolicyHolder]
+
+// SetEmailDomainPolicy installs the policy every Email which is unmarshaled
+// or bound must satisfy, such as rejecting disposable email providers or
+// only accepting corporate domains. Domains are passed to it lowercased.
+// Passing nil removes the policy. It panics after FreezeSettings.
+func SetEmailDomainPolicy(policy EmailDomainPolicy) {
+	checkSettingsFrozen("SetEmailDomainPolicy")
+	if policy == nil {
+		emailDomainPolicy.Store(nil)
+		return
+	}
+	emailDomainPolicy.Store(&emailDomainPolicyHolder{policy: policy})
+}
+
+func checkEmailDomain(email string) error {
+	h := emailDomainPolicy.Load()
+	if h == nil {
+		return nil
+	}
+	domain := strings.ToLower(Email(email).Domain())
+	if err := h.policy(domain); err != nil {
+		return &EmailDomainError{Domain: domain, Err: err}
+	}
 	return nil
 }
@@ -2,6 +2,7 @@ package types
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -174,3 +175,33 @@ func TestEmail_UnmarshalJSON_NullableEmail_Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestEmail_DomainPolicy(t *testing.T) {
+	defer SetEmailDomainPolicy(nil)
+	SetEmailDomainPolicy(func(domain string) error {
+		if domain == "mailinator.com" {
+			return errors.New("disposable email provider")
+		}
+		return nil
+	})
+
+	var e Email
+	assert.NoError(t, json.Unmarshal([]byte(`"alex@example.com"`), &e))
+	assert.Equal(t, "example.com", e.Domain())
+
+	err := json.Unmarshal([]byte(`"alex@Mailinator.com"`), &e)
+	var domainErr *EmailDomainError
+	assert.True(t, errors.As(err, &domainErr))
+	assert.Equal(t, "mailinator.com", domainErr.Domain)
+	assert.EqualError(t, err, "email: domain 'mailinator.com' is not allowed: disposable email provider")
+
+	assert.True(t, errors.As(e.UnmarshalText([]byte("alex@mailinator.com")), &domainErr))
+	assert.ErrorIs(t, e.UnmarshalText([]byte("alex")), ErrValidationEmail)
+	assert.NoError(t, e.UnmarshalText([]byte("alex@example.org")))
+	assert.Equal(t, Email("alex@example.org"), e)
+
+	// Without a policy, text isn't validated.
+	SetEmailDomainPolicy(nil)
+	assert.NoError(t, e.UnmarshalText([]byte("alex")))
+	assert.Equal(t, Email("alex"), e)
+}
@@ -0,0 +1,132 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
+// ErrInvalidEnumValue is the sentinel error returned when a value which
+// isn't one of an enum's values is unmarshaled into an Enum.
+var ErrInvalidEnumValue = errors.New("enum: value is not one of the allowed values")
+
+// EnumValuer is implemented by enum types, such as generated string or
+// integer types, listing the values their schema allows. EnumValues must
+// work on the zero value.
+type EnumValuer[T any] interface {
+	comparable
+	EnumValues() []T
+}
+
+// Enum holds a value of the enum type T, and rejects values T doesn't list
+// when unmarshaling, so that enum types share this implementation rather
+// than each repeating it. It marshals as the bare value, and binds from
+// parameters through its UnmarshalText and Bind methods.
+type Enum[T EnumValuer[T]] struct {
+	Value T
+}
+
+var lenientEnums atomic.Bool
+
+// SetLenientEnums sets whether unmarshaling an Enum accepts values which
+// aren't allowed, passing them through instead of failing, for clients
+// which must tolerate values added to a server's enums later. IsValid still
+// reports such values as invalid. Servers binding leniently, with
+// runtime.WithBindWarnings, accept them for those requests only, whether or
+// not this is on. It panics after FreezeSettings.
+func SetLenientEnums(lenient bool) {
+	checkSettingsFrozen("SetLenientEnums")
+	lenientEnums.Store(lenient)
+}
+
+// NewEnum returns an Enum holding value.
+func NewEnum[T EnumValuer[T]](value T) Enum[T] {
+	return Enum[T]{Value: value}
+}
+
+// Values returns the values T allows.
+func (e Enum[T]) Values() []T {
+	var zero T
+	return zero.EnumValues()
+}
+
+// IsValid reports whether e holds one of the values T allows.
+func (e Enum[T]) IsValid() bool {
+	for _, v := range e.Values() {
+		if v == e.Value {
+			return true
+		}
+	}
+	return false
+}
+
+func (e Enum[T]) String() string {
+	return fmt.Sprint(e.Value)
+}
+
+func (e Enum[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.Value)
+}
+
+func (e *Enum[T]) UnmarshalJSON(data []byte) error {
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return e.set(value, lenientEnums.Load())
+}
+
+// UnmarshalJSONLenient is UnmarshalJSON, except that it accepts values T
+// doesn't list as if SetLenientEnums were on, for lenient binding, which
+// records them as warnings instead.
+func (e *Enum[T]) UnmarshalJSONLenient(data []byte) error {
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return e.set(value, true)
+}
+
+func (e Enum[T]) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
+
+// UnmarshalText parses text as a value of T. String types take text as it
+// is, and other types, such as integers, parse it as JSON.
+func (e *Enum[T]) UnmarshalText(text []byte) error {
+	return e.unmarshalText(text, lenientEnums.Load())
+}
+
+// Bind implements runtime.Binder, so that Enums can be bound from
+// parameters wherever a struct would otherwise be expected.
+func (e *Enum[T]) Bind(src string) error {
+	return e.UnmarshalText([]byte(src))
+}
+
+// BindLenient is Bind, except that it accepts values T doesn't list as if
+// SetLenientEnums were on, for lenient binding, which records them as
+// warnings instead.
+func (e *Enum[T]) BindLenient(src string) error {
+	return e.unmarshalText([]byte(src), true)
+}
+
+func (e *Enum[T]) unmarshalText(text []byte, lenient bool) error {
+	var value T
+	if rv := reflect.ValueOf(&value).Elem(); rv.Kind() == reflect.String {
+		rv.SetString(string(text))
+	} else if err := json.Unmarshal(text, &value); err != nil {
+		return err
+	}
+	return e.set(value, lenient)
+}
+
+func (e *Enum[T]) set(value T, lenient bool) error {
+	candidate := Enum[T]{Value: value}
+	if !candidate.IsValid() && !lenient {
+		return fmt.Errorf("%w: %v", ErrInvalidEnumValue, value)
+	}
+	*e = candidate
+	return nil
+}
@@ -0,0 +1,66 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type color string
+
+func (color) EnumValues() []color { return []color{"red", "green"} }
+
+type priority int
+
+func (priority) EnumValues() []priority { return []priority{1, 2, 3} }
+
+func TestEnum(t *testing.T) {
+	type Paint struct {
+		Color    Enum[color]    `json:"color"`
+		Priority Enum[priority] `json:"priority"`
+	}
+
+	var p Paint
+	require.NoError(t, json.Unmarshal([]byte(`{"color":"red","priority":2}`), &p))
+	assert.Equal(t, color("red"), p.Color.Value)
+	assert.Equal(t, priority(2), p.Priority.Value)
+	assert.True(t, p.Color.IsValid())
+	assert.Equal(t, []color{"red", "green"}, p.Color.Values())
+
+	data, err := json.Marshal(p)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"color":"red","priority":2}`, string(data))
+
+	err = json.Unmarshal([]byte(`{"color":"blue"}`), &p)
+	assert.ErrorIs(t, err, ErrInvalidEnumValue)
+	assert.Equal(t, color("red"), p.Color.Value)
+
+	var prio Enum[priority]
+	require.NoError(t, prio.UnmarshalText([]byte("3")))
+	assert.Equal(t, priority(3), prio.Value)
+	assert.ErrorIs(t, prio.Bind("4"), ErrInvalidEnumValue)
+	text, err := prio.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "3", string(text))
+
+	SetLenientEnums(true)
+	defer SetLenientEnums(false)
+	require.NoError(t, json.Unmarshal([]byte(`{"color":"blue"}`), &p))
+	assert.Equal(t, color("blue"), p.Color.Value)
+	assert.False(t, p.Color.IsValid())
+}
+
+func TestEnum_Lenient(t *testing.T) {
+	var c Enum[color]
+	require.NoError(t, c.UnmarshalJSONLenient([]byte(`"blue"`)))
+	assert.Equal(t, color("blue"), c.Value)
+	assert.False(t, c.IsValid())
+	assert.Error(t, c.UnmarshalJSONLenient([]byte(`1`)))
+
+	var prio Enum[priority]
+	require.NoError(t, prio.BindLenient("4"))
+	assert.Equal(t, priority(4), prio.Value)
+	assert.ErrorIs(t, prio.Bind("4"), ErrInvalidEnumValue)
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// JSON holds an arbitrary JSON value as raw bytes, decoded only when asked
+// to, for schema-less properties and pass-through proxy endpoints, where
+// eagerly decoding into map[string]interface{} wastes CPU and loses the
+// precision of large numbers. Unlike json.RawMessage, it checks that it
+// holds valid JSON before marshaling.
+type JSON []byte
+
+// NewJSON marshals v into a JSON.
+func NewJSON(v interface{}) (JSON, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return JSON(data), nil
+}
+
+// Valid reports whether j holds a single valid JSON value.
+func (j JSON) Valid() bool {
+	return json.Valid(j)
+}
+
+// IsNull reports whether j is empty or holds the JSON null literal.
+func (j JSON) IsNull() bool {
+	return len(bytes.TrimSpace(j)) == 0 || IsJSONNull(j)
+}
+
+// Decode unmarshals j into dest.
+func (j JSON) Decode(dest interface{}) error {
+	return json.Unmarshal(j, dest)
+}
+
+// DecodeInto unmarshals j into a new value of type T.
+func DecodeInto[T any](j JSON) (T, error) {
+	var v T
+	err := j.Decode(&v)
+	return v, err
+}
+
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return JSONNull, nil
+	}
+	if !j.Valid() {
+		return nil, errors.New("types.JSON: invalid JSON")
+	}
+	return j, nil
+}
+
+func (j *JSON) UnmarshalJSON(data []byte) error {
+	// data is only valid until UnmarshalJSON returns.
+	*j = append((*j)[:0], data...)
+	return nil
+}
@@ -0,0 +1,49 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSON(t *testing.T) {
+	var v struct {
+		Extra JSON `json:"extra"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"extra": {"id": 12345678901234567890, "tags": ["a"]}}`), &v))
+	assert.True(t, v.Extra.Valid())
+	assert.False(t, v.Extra.IsNull())
+
+	out, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"extra":{"id":12345678901234567890,"tags":["a"]}}`, string(out))
+	assert.Contains(t, string(out), "12345678901234567890", "numbers are passed through as they are")
+
+	type extra struct {
+		ID   uint64   `json:"id"`
+		Tags []string `json:"tags"`
+	}
+	decoded, err := DecodeInto[extra](v.Extra)
+	require.NoError(t, err)
+	assert.Equal(t, extra{ID: 12345678901234567890, Tags: []string{"a"}}, decoded)
+
+	_, err = DecodeInto[string](v.Extra)
+	assert.Error(t, err)
+}
+
+func TestJSON_Marshal(t *testing.T) {
+	out, err := json.Marshal(struct{ V JSON }{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"V":null}`, string(out))
+	assert.True(t, JSON(nil).IsNull())
+	assert.True(t, JSON("null").IsNull())
+
+	_, err = json.Marshal(struct{ V JSON }{V: JSON("{")})
+	assert.Error(t, err)
+
+	j, err := NewJSON(map[string]int{"a": 1})
+	require.NoError(t, err)
+	assert.Equal(t, JSON(`{"a":1}`), j)
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// JSONNull is the JSON null literal, for marshalers writing an explicit
+// null.
+var JSONNull = json.RawMessage("null")
+
+// IsJSONNull reports whether data is the JSON null literal, ignoring
+// surrounding whitespace.
+func IsJSONNull(data []byte) bool {
+	return bytes.Equal(bytes.TrimSpace(data), JSONNull)
+}
+
+// UnmarshalOrNull unmarshals data into dest, unless it is null, in which
+// case dest is left untouched and isNull is true. It decodes OpenAPI 3.1
+// unions with null, such as type: ["string", "null"], where encoding/json
+// would otherwise silently skip the null.
+func UnmarshalOrNull(data []byte, dest interface{}) (isNull bool, err error) {
+	if IsJSONNull(data) {
+		return true, nil
+	}
+	return false, json.Unmarshal(data, dest)
+}
+
+// OrNull holds a value of an OpenAPI 3.1 union of T with null, such as
+// type: ["string", "null"], for properties which are always present but may
+// be null. Unlike nullable.Nullable, it doesn't track whether the property
+// was specified at all: an absent property leaves the zero OrNull, which
+// holds T's zero value.
+type OrNull[T any] struct {
+	Value T
+	Null  bool
+}
+
+// NewOrNull returns an OrNull holding value.
+func NewOrNull[T any](value T) OrNull[T] {
+	return OrNull[T]{Value: value}
+}
+
+// Null returns an OrNull holding null.
+func Null[T any]() OrNull[T] {
+	return OrNull[T]{Null: true}
+}
+
+// Get returns the value, and false if it is null.
+func (o OrNull[T]) Get() (T, bool) {
+	return o.Value, !o.Null
+}
+
+func (o OrNull[T]) MarshalJSON() ([]byte, error) {
+	if o.Null {
+		return JSONNull, nil
+	}
+	return json.Marshal(o.Value)
+}
+
+func (o *OrNull[T]) UnmarshalJSON(data []byte) error {
+	var value T
+	isNull, err := UnmarshalOrNull(data, &value)
+	if err != nil {
+		return err
+	}
+	*o = OrNull[T]{Value: value, Null: isNull}
+	return nil
+}
@@ -0,0 +1,57 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsJSONNull(t *testing.T) {
+	assert.True(t, IsJSONNull([]byte("null")))
+	assert.True(t, IsJSONNull([]byte(" null\n")))
+	assert.False(t, IsJSONNull([]byte(`"null"`)))
+	assert.False(t, IsJSONNull(nil))
+}
+
+func TestUnmarshalOrNull(t *testing.T) {
+	s := "unchanged"
+	isNull, err := UnmarshalOrNull([]byte("null"), &s)
+	require.NoError(t, err)
+	assert.True(t, isNull)
+	assert.Equal(t, "unchanged", s)
+
+	isNull, err = UnmarshalOrNull([]byte(`"abc"`), &s)
+	require.NoError(t, err)
+	assert.False(t, isNull)
+	assert.Equal(t, "abc", s)
+
+	_, err = UnmarshalOrNull([]byte("1"), &s)
+	assert.Error(t, err)
+}
+
+func TestOrNull(t *testing.T) {
+	type Pet struct {
+		Nickname OrNull[string] `json:"nickname"`
+	}
+
+	var pet Pet
+	require.NoError(t, json.Unmarshal([]byte(`{"nickname":null}`), &pet))
+	assert.True(t, pet.Nickname.Null)
+	_, ok := pet.Nickname.Get()
+	assert.False(t, ok)
+	data, err := json.Marshal(pet)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"nickname":null}`, string(data))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"nickname":"Rex"}`), &pet))
+	value, ok := pet.Nickname.Get()
+	assert.True(t, ok)
+	assert.Equal(t, "Rex", value)
+
+	data, err = json.Marshal(Pet{Nickname: NewOrNull("Rex")})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"nickname":"Rex"}`, string(data))
+	assert.Equal(t, OrNull[int]{Null: true}, Null[int]())
+}
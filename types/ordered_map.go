@@ -0,0 +1,114 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// OrderedMap is a JSON object which keeps its properties in order: the
+// order they were unmarshaled in, followed by those set since, in the order
+// they were first set. It is for APIs where property order is significant,
+// such as signed or canonical documents, and can be used as the type of a
+// field of a generated model. The zero value is an empty map ready to use.
+type OrderedMap[V any] struct {
+	keys   []string
+	values map[string]V
+}
+
+// Len returns the number of properties.
+func (m *OrderedMap[V]) Len() int {
+	return len(m.keys)
+}
+
+// Keys returns the names of the properties, in order.
+func (m *OrderedMap[V]) Keys() []string {
+	return append([]string(nil), m.keys...)
+}
+
+// Get returns the value of the property key.
+func (m *OrderedMap[V]) Get(key string) (V, bool) {
+	v, ok := m.values[key]
+	return v, ok
+}
+
+// Set sets the property key to value. A new property goes last, while an
+// existing one keeps its position.
+func (m *OrderedMap[V]) Set(key string, value V) {
+	if m.values == nil {
+		m.values = make(map[string]V)
+	}
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Delete removes the property key.
+func (m *OrderedMap[V]) Delete(key string) {
+	if _, ok := m.values[key]; !ok {
+		return
+	}
+	delete(m.values, key)
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+func (m OrderedMap[V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling property '%s': %w", key, err)
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (m *OrderedMap[V]) UnmarshalJSON(data []byte) error {
+	if IsJSONNull(data) {
+		*m = OrderedMap[V]{}
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil {
+		return err
+	} else if tok != json.Delim('{') {
+		return errors.New("types.OrderedMap: expected a JSON object")
+	}
+	var result OrderedMap[V]
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+		var value V
+		if err := dec.Decode(&value); err != nil {
+			return fmt.Errorf("error unmarshaling property '%s': %w", key, err)
+		}
+		result.Set(key, value)
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	*m = result
+	return nil
+}
@@ -0,0 +1,16 @@
+//go:build go1.23
+
+package types
+
+import "iter"
+
+// All returns an iterator over the properties, in order.
+func (m *OrderedMap[V]) All() iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		for _, key := range m.keys {
+			if !yield(key, m.values[key]) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,23 @@
+//go:build go1.23
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderedMap_All(t *testing.T) {
+	var m OrderedMap[int]
+	m.Set("b", 1)
+	m.Set("a", 2)
+	var keys []string
+	var sum int
+	for k, v := range m.All() {
+		keys = append(keys, k)
+		sum += v
+	}
+	assert.Equal(t, []string{"b", "a"}, keys)
+	assert.Equal(t, 3, sum)
+}
@@ -0,0 +1,49 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderedMap(t *testing.T) {
+	var m OrderedMap[int]
+	m.Set("z", 1)
+	m.Set("a", 2)
+	m.Set("m", 3)
+	m.Set("z", 4)
+	assert.Equal(t, []string{"z", "a", "m"}, m.Keys())
+	v, ok := m.Get("z")
+	assert.True(t, ok)
+	assert.Equal(t, 4, v)
+
+	m.Delete("a")
+	m.Delete("missing")
+	assert.Equal(t, 2, m.Len())
+
+	out, err := json.Marshal(m)
+	require.NoError(t, err)
+	assert.Equal(t, `{"z":4,"m":3}`, string(out))
+}
+
+func TestOrderedMap_UnmarshalJSON(t *testing.T) {
+	var doc struct {
+		Claims OrderedMap[json.RawMessage] `json:"claims"`
+	}
+	data := `{"claims":{"sub":"1","aud":["a","b"],"iat":1700000000,"nested":{"y":1,"x":2}}}`
+	require.NoError(t, json.Unmarshal([]byte(data), &doc))
+	assert.Equal(t, []string{"sub", "aud", "iat", "nested"}, doc.Claims.Keys())
+
+	out, err := json.Marshal(doc)
+	require.NoError(t, err)
+	assert.Equal(t, data, string(out))
+
+	var m OrderedMap[string]
+	m.Set("stale", "x")
+	require.NoError(t, json.Unmarshal([]byte("null"), &m))
+	assert.Equal(t, 0, m.Len())
+	assert.Error(t, json.Unmarshal([]byte(`["a"]`), &m))
+	assert.Error(t, json.Unmarshal([]byte(`{"a":1}`), &m))
+}
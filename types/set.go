@@ -0,0 +1,146 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrDuplicateSetItem is the sentinel error returned when an array holding
+// the same item twice is unmarshaled or bound into a Set.
+var ErrDuplicateSetItem = errors.New("set: duplicate item")
+
+// setItem constrains the items of a Set to the types which have a natural
+// order, so that sets marshal deterministically.
+type setItem interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64 | ~string
+}
+
+// Set holds the items of an array whose schema sets uniqueItems, so that the
+// constraint is enforced by the type rather than by a validator. It
+// unmarshals from a JSON array, rejecting duplicates with
+// ErrDuplicateSetItem, and marshals as an array sorted in ascending order.
+// It binds from array parameters, such as comma-separated ones, through its
+// BindArray and UnmarshalText methods. The zero value is an empty set ready
+// to use.
+type Set[T setItem] struct {
+	items map[T]struct{}
+}
+
+var lenientSets atomic.Bool
+
+// SetLenientSets sets whether unmarshaling or binding a Set drops duplicate
+// items instead of failing, for clients of servers which don't enforce
+// uniqueItems themselves. It panics after FreezeSettings.
+func SetLenientSets(lenient bool) {
+	checkSettingsFrozen("SetLenientSets")
+	lenientSets.Store(lenient)
+}
+
+// NewSet returns a Set holding items, with duplicates dropped.
+func NewSet[T setItem](items ...T) Set[T] {
+	var s Set[T]
+	for _, item := range items {
+		s.Add(item)
+	}
+	return s
+}
+
+// Len returns the number of items.
+func (s Set[T]) Len() int {
+	return len(s.items)
+}
+
+// Contains reports whether item is in the set.
+func (s Set[T]) Contains(item T) bool {
+	_, ok := s.items[item]
+	return ok
+}
+
+// Add adds item to the set, reporting whether it wasn't in it already.
+func (s *Set[T]) Add(item T) bool {
+	if _, ok := s.items[item]; ok {
+		return false
+	}
+	if s.items == nil {
+		s.items = make(map[T]struct{})
+	}
+	s.items[item] = struct{}{}
+	return true
+}
+
+// Remove removes item from the set.
+func (s *Set[T]) Remove(item T) {
+	delete(s.items, item)
+}
+
+// Items returns the items, sorted in ascending order.
+func (s Set[T]) Items() []T {
+	items := make([]T, 0, len(s.items))
+	for item := range s.items {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i] < items[j] })
+	return items
+}
+
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Items())
+}
+
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	return s.set(items)
+}
+
+func (s Set[T]) MarshalText() ([]byte, error) {
+	items := s.Items()
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = fmt.Sprint(item)
+	}
+	return []byte(strings.Join(parts, ",")), nil
+}
+
+// UnmarshalText parses text as comma-separated items.
+func (s *Set[T]) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return s.set(nil)
+	}
+	return s.BindArray(strings.Split(string(text), ","))
+}
+
+// BindArray implements runtime.ArrayBinder, so that Sets can be bound from
+// array parameters of any style. String types take items as they are, and
+// other types, such as integers, parse them as JSON.
+func (s *Set[T]) BindArray(parts []string) error {
+	items := make([]T, len(parts))
+	for i, part := range parts {
+		if rv := reflect.ValueOf(&items[i]).Elem(); rv.Kind() == reflect.String {
+			rv.SetString(part)
+		} else if err := json.Unmarshal([]byte(part), &items[i]); err != nil {
+			return fmt.Errorf("error binding item '%s': %w", part, err)
+		}
+	}
+	return s.set(items)
+}
+
+func (s *Set[T]) set(items []T) error {
+	var result Set[T]
+	for _, item := range items {
+		if !result.Add(item) && !lenientSets.Load() {
+			return fmt.Errorf("%w: %v", ErrDuplicateSetItem, item)
+		}
+	}
+	*s = result
+	return nil
+}
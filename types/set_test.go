@@ -0,0 +1,57 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet(t *testing.T) {
+	s := NewSet(3, 1, 2, 3)
+	assert.Equal(t, 3, s.Len())
+	assert.True(t, s.Contains(2))
+	assert.False(t, s.Add(1))
+	assert.True(t, s.Add(10))
+	s.Remove(2)
+	assert.Equal(t, []int{1, 3, 10}, s.Items())
+
+	out, err := json.Marshal(s)
+	require.NoError(t, err)
+	assert.Equal(t, `[1,3,10]`, string(out))
+
+	var empty Set[string]
+	out, err = json.Marshal(empty)
+	require.NoError(t, err)
+	assert.Equal(t, `[]`, string(out))
+}
+
+func TestSet_UnmarshalJSON(t *testing.T) {
+	var doc struct {
+		Tags Set[string] `json:"tags"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"tags":["b","a"]}`), &doc))
+	assert.Equal(t, []string{"a", "b"}, doc.Tags.Items())
+
+	err := json.Unmarshal([]byte(`{"tags":["b","a","b"]}`), &doc)
+	assert.ErrorIs(t, err, ErrDuplicateSetItem)
+	assert.Equal(t, []string{"a", "b"}, doc.Tags.Items(), "a failed unmarshal leaves the set alone")
+
+	SetLenientSets(true)
+	defer SetLenientSets(false)
+	require.NoError(t, json.Unmarshal([]byte(`{"tags":["c","c"]}`), &doc))
+	assert.Equal(t, []string{"c"}, doc.Tags.Items())
+}
+
+func TestSet_BindArray(t *testing.T) {
+	var ids Set[int64]
+	require.NoError(t, ids.UnmarshalText([]byte("5,2,9")))
+	assert.Equal(t, []int64{2, 5, 9}, ids.Items())
+	text, err := ids.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "2,5,9", string(text))
+
+	assert.ErrorIs(t, ids.BindArray([]string{"1", "1"}), ErrDuplicateSetItem)
+	assert.Error(t, ids.BindArray([]string{"x"}))
+}
@@ -0,0 +1,22 @@
+package types
+
+import "sync/atomic"
+
+var settingsFrozen atomic.Bool
+
+// FreezeSettings makes the package-wide settings read-only, so that
+// changing them after startup, which would affect values already being
+// unmarshaled, panics rather than takes effect. It covers
+// SetLenientEnums, SetLenientSets, SetRejectNilUUID, SetEmailDomainPolicy
+// and SetDateRange. runtime.FreezeSettings calls it.
+func FreezeSettings() {
+	settingsFrozen.Store(true)
+}
+
+// checkSettingsFrozen panics if FreezeSettings was called, naming the
+// setter called after it.
+func checkSettingsFrozen(setter string) {
+	if settingsFrozen.Load() {
+		panic("types: " + setter + " called after FreezeSettings")
+	}
+}
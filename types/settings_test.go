@@ -0,0 +1,19 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFreezeSettings(t *testing.T) {
+	FreezeSettings()
+	defer settingsFrozen.Store(false)
+
+	assert.PanicsWithValue(t, "types: SetLenientEnums called after FreezeSettings", func() { SetLenientEnums(true) })
+	assert.Panics(t, func() { SetLenientSets(true) })
+	assert.Panics(t, func() { SetRejectNilUUID(true) })
+	assert.Panics(t, func() { SetEmailDomainPolicy(nil) })
+	assert.Panics(t, func() { SetDateRange(DateRange{}) })
+	assert.False(t, lenientEnums.Load())
+}
@@ -1,7 +1,37 @@
 package types
 
 import (
+	"errors"
+	"sync/atomic"
+
 	"github.com/google/uuid"
 )
 
 type UUID = uuid.UUID
+
+// ErrNilUUID is the sentinel error returned when the nil UUID,
+// 00000000-0000-0000-0000-000000000000, is bound while nil UUIDs are
+// rejected.
+var ErrNilUUID = errors.New("uuid: the nil UUID is not allowed")
+
+var rejectNilUUID atomic.Bool
+
+// SetRejectNilUUID sets whether binding a parameter into a UUID rejects the
+// nil UUID with ErrNilUUID, since it usually stands for a client that forgot
+// to set an ID rather than for a real one. As UUID is an alias of uuid.UUID,
+// which has its own UnmarshalJSON, this applies to the values the runtime
+// binders parse, but not to JSON bodies; use CheckUUID there. It panics
+// after FreezeSettings.
+func SetRejectNilUUID(reject bool) {
+	checkSettingsFrozen("SetRejectNilUUID")
+	rejectNilUUID.Store(reject)
+}
+
+// CheckUUID returns ErrNilUUID if u is the nil UUID and SetRejectNilUUID is
+// in effect.
+func CheckUUID(u UUID) error {
+	if u == uuid.Nil && rejectNilUUID.Load() {
+		return ErrNilUUID
+	}
+	return nil
+}
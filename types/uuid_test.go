@@ -51,3 +51,12 @@ func TestUUID_UnmarshalJSON_Pass(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, testUUID, b.UUIDField)
 }
+
+func TestCheckUUID(t *testing.T) {
+	assert.NoError(t, CheckUUID(uuid.Nil))
+
+	defer SetRejectNilUUID(false)
+	SetRejectNilUUID(true)
+	assert.ErrorIs(t, CheckUUID(uuid.Nil), ErrNilUUID)
+	assert.NoError(t, CheckUUID(uuid.MustParse("9cb14230-b640-11ec-b909-0242ac120002")))
+}
@@ -0,0 +1,122 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// UnknownDiscriminatorError is returned by Union.ValueByDiscriminator when
+// the discriminator property holds a value without a mapping.
+type UnknownDiscriminatorError struct {
+	Field string
+	Value string
+}
+
+func (e *UnknownDiscriminatorError) Error() string {
+	return fmt.Sprintf("unknown discriminator value '%s' in property '%s'", e.Value, e.Field)
+}
+
+// Union holds the raw JSON of a oneOf or anyOf value, so that types
+// generated for such schemas can embed it and delegate their polymorphic
+// serialization to it, rather than each carrying their own copy. The zero
+// value holds no JSON and marshals as null.
+type Union struct {
+	raw json.RawMessage
+}
+
+// Raw returns the JSON the union holds.
+func (u Union) Raw() json.RawMessage {
+	return u.raw
+}
+
+// As unmarshals the union into dest, which should point to the type of one
+// of its variants.
+func (u Union) As(dest interface{}) error {
+	return jsonUnmarshal(u.raw, dest)
+}
+
+// UnionAs returns the union unmarshaled as the variant T.
+func UnionAs[T any](u Union) (T, error) {
+	var v T
+	err := u.As(&v)
+	return v, err
+}
+
+// From replaces the union's JSON with that of v.
+func (u *Union) From(v interface{}) error {
+	raw, err := jsonMarshal(v)
+	if err != nil {
+		return err
+	}
+	u.raw = raw
+	return nil
+}
+
+// Merge merges the JSON of v into that of the union, as anyOf values made
+// up of several variants require.
+func (u *Union) Merge(v interface{}) error {
+	raw, err := jsonMarshal(v)
+	if err != nil {
+		return err
+	}
+	merged, err := JSONMerge(u.raw, raw)
+	if err != nil {
+		return err
+	}
+	u.raw = merged
+	return nil
+}
+
+// Discriminator returns the string value of the union's discriminator
+// property field, or an empty string when the property is absent.
+func (u Union) Discriminator(field string) (string, error) {
+	var object map[string]json.RawMessage
+	if err := jsonUnmarshal(u.raw, &object); err != nil {
+		return "", err
+	}
+	raw, ok := object[field]
+	if !ok {
+		return "", nil
+	}
+	var value string
+	if err := jsonUnmarshal(raw, &value); err != nil {
+		return "", fmt.Errorf("error reading discriminator property '%s': %w", field, err)
+	}
+	return value, nil
+}
+
+// ValueByDiscriminator unmarshals the union into the variant its
+// discriminator property field selects. mapping maps discriminator values
+// to functions returning a pointer to a new value of the variant, such as
+//
+//	func() any { return &Cat{} }
+//
+// and the pointer is returned once the union has been unmarshaled into it.
+// A value without a mapping yields an *UnknownDiscriminatorError.
+func (u Union) ValueByDiscriminator(field string, mapping map[string]func() any) (any, error) {
+	value, err := u.Discriminator(field)
+	if err != nil {
+		return nil, err
+	}
+	newVariant, ok := mapping[value]
+	if !ok {
+		return nil, &UnknownDiscriminatorError{Field: field, Value: value}
+	}
+	dest := newVariant()
+	if err := u.As(dest); err != nil {
+		return nil, err
+	}
+	return dest, nil
+}
+
+func (u Union) MarshalJSON() ([]byte, error) {
+	if u.raw == nil {
+		return []byte("null"), nil
+	}
+	return u.raw, nil
+}
+
+func (u *Union) UnmarshalJSON(data []byte) error {
+	u.raw = append(json.RawMessage(nil), data...)
+	return nil
+}
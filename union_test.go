@@ -0,0 +1,56 @@
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnion(t *testing.T) {
+	type Cat struct {
+		PetType string `json:"petType"`
+		Lives   int    `json:"lives"`
+	}
+	type Dog struct {
+		PetType string `json:"petType"`
+		Bark    string `json:"bark"`
+	}
+	type Pet struct {
+		Union
+	}
+	mapping := map[string]func() any{
+		"cat": func() any { return &Cat{} },
+		"dog": func() any { return &Dog{} },
+	}
+
+	var pet Pet
+	require.NoError(t, json.Unmarshal([]byte(`{"petType":"cat","lives":9}`), &pet))
+	value, err := pet.ValueByDiscriminator("petType", mapping)
+	require.NoError(t, err)
+	assert.Equal(t, &Cat{PetType: "cat", Lives: 9}, value)
+
+	cat, err := UnionAs[Cat](pet.Union)
+	require.NoError(t, err)
+	assert.Equal(t, 9, cat.Lives)
+
+	require.NoError(t, pet.From(Dog{PetType: "dog", Bark: "woof"}))
+	data, err := json.Marshal(pet)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"petType":"dog","bark":"woof"}`, string(data))
+
+	require.NoError(t, pet.Merge(map[string]int{"lives": 1}))
+	assert.JSONEq(t, `{"petType":"dog","bark":"woof","lives":1}`, string(pet.Raw()))
+
+	require.NoError(t, pet.From(map[string]string{"petType": "fish"}))
+	_, err = pet.ValueByDiscriminator("petType", mapping)
+	var unknown *UnknownDiscriminatorError
+	require.True(t, errors.As(err, &unknown))
+	assert.Equal(t, "fish", unknown.Value)
+
+	data, err = json.Marshal(Pet{})
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+}
@@ -0,0 +1,211 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+const urlEncodedContentType = "application/x-www-form-urlencoded"
+
+// EncodeURLEncodedBody serializes the struct v as an
+// application/x-www-form-urlencoded request body. Each property is styled
+// according to its entry in encodings, keyed by the property's JSON name, the
+// same way query parameters are: style defaults to "form" and explode to
+// true, as the OpenAPI encoding object specifies, and "spaceDelimited",
+// "pipeDelimited" and "deepObject" are supported as well. A property whose
+// encoding has a JSON ContentType is sent as a single JSON string instead.
+//
+// Nil pointers and properties tagged omitempty holding their zero value are
+// left out.
+func EncodeURLEncodedBody(v interface{}, encodings map[string]RequestBodyEncoding) (string, error) {
+	ptrVal := reflect.Indirect(reflect.ValueOf(v))
+	if ptrVal.Kind() != reflect.Struct {
+		return "", errors.New("form data body should be a struct")
+	}
+	tValue := ptrVal.Type()
+
+	var parts []string
+	for i := 0; i < tValue.NumField(); i++ {
+		field := ptrVal.Field(i)
+		tag := tValue.Field(i).Tag.Get(tagName)
+		if !field.CanInterface() || tag == "-" {
+			continue
+		}
+		omitEmpty := strings.HasSuffix(tag, ",omitempty")
+		if (omitEmpty && field.IsZero()) || (field.Kind() == reflect.Ptr && field.IsNil()) {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = tValue.Field(i).Name
+		}
+
+		encoding := encodings[name]
+		if encoding.ContentType != "" {
+			if !strings.HasPrefix(encoding.ContentType, jsonContentType) {
+				return "", errors.New("unsupported encoding, only application/json is supported")
+			}
+			data, err := jsonMarshal(field.Interface())
+			if err != nil {
+				return "", fmt.Errorf("error marshaling property '%s': %w", name, err)
+			}
+			parts = append(parts, url.QueryEscape(name)+"="+url.QueryEscape(string(data)))
+			continue
+		}
+
+		style := encoding.Style
+		if style == "" {
+			style = "form"
+		}
+		explode := true
+		if encoding.Explode != nil {
+			explode = *encoding.Explode
+		}
+
+		part, err := styleURLEncodedProperty(style, explode, name, field.Interface())
+		if err != nil {
+			return "", err
+		}
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "&"), nil
+}
+
+func styleURLEncodedProperty(style string, explode bool, name string, value interface{}) (string, error) {
+	if style == "deepObject" {
+		if !explode {
+			return "", errors.New("deepObjects must be exploded")
+		}
+		return styleDeepObject(value, name, true)
+	}
+	styled, err := styleParam(style, explode, url.QueryEscape(name), ParamLocationQuery, value)
+	if err != nil {
+		return "", fmt.Errorf("error styling property '%s': %w", name, err)
+	}
+	// Values have already been query escaped, so any remaining space or
+	// pipe is a delimiter which still needs escaping in a body.
+	switch style {
+	case "spaceDelimited":
+		styled = strings.ReplaceAll(styled, " ", "%20")
+	case "pipeDelimited":
+		styled = strings.ReplaceAll(styled, "|", "%7C")
+	}
+	return styled, nil
+}
+
+// BindURLEncodedBody is the server-side counterpart of EncodeURLEncodedBody.
+// It binds the values of an application/x-www-form-urlencoded body to the
+// struct dest, styling each property according to its entry in encodings,
+// keyed by JSON name, the same way query parameters are bound. Style
+// defaults to "form" and explode to true, and a property whose encoding has
+// a JSON ContentType is unmarshaled from a single JSON string.
+func BindURLEncodedBody(values url.Values, dest interface{}, encodings map[string]RequestBodyEncoding) error {
+	if err := bindURLEncodedBody(values, dest, encodings); err != nil {
+		return err
+	}
+	return validateBound(dest)
+}
+
+// bindURLEncodedBody is BindURLEncodedBody without the validation.
+func bindURLEncodedBody(values url.Values, dest interface{}, encodings map[string]RequestBodyEncoding) error {
+	ptrVal := reflect.ValueOf(dest)
+	if ptrVal.Kind() != reflect.Ptr || ptrVal.Elem().Kind() != reflect.Struct {
+		return errors.New("form data body destination should be a pointer to a struct")
+	}
+	v := ptrVal.Elem()
+	tValue := v.Type()
+
+	for i := 0; i < tValue.NumField(); i++ {
+		field := v.Field(i)
+		tag := tValue.Field(i).Tag.Get(tagName)
+		if !field.CanSet() || tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = tValue.Field(i).Name
+		}
+
+		encoding := encodings[name]
+		required := encoding.Required != nil && *encoding.Required
+		if encoding.ContentType != "" {
+			if !strings.HasPrefix(encoding.ContentType, jsonContentType) {
+				return errors.New("unsupported encoding, only application/json is supported")
+			}
+			value, found := values[name]
+			if !found {
+				if required {
+					return fmt.Errorf("property '%s' is required", name)
+				}
+				continue
+			}
+			if err := jsonUnmarshalDynamic([]byte(value[0]), field.Addr().Interface()); err != nil {
+				return fmt.Errorf("error unmarshaling property '%s': %w", name, err)
+			}
+			continue
+		}
+
+		style := encoding.Style
+		if style == "" {
+			style = "form"
+		}
+		explode := true
+		if encoding.Explode != nil {
+			explode = *encoding.Explode
+		}
+		if err := bindURLEncodedProperty(style, explode, required, name, values, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func bindURLEncodedProperty(style string, explode, required bool, name string, values url.Values, field reflect.Value) error {
+	var delimiter string
+	switch style {
+	case "spaceDelimited":
+		delimiter = " "
+	case "pipeDelimited":
+		delimiter = "|"
+	default:
+		if err := BindQueryParameter(style, explode, required, name, values, field.Addr().Interface()); err != nil {
+			return fmt.Errorf("error binding property '%s': %w", name, err)
+		}
+		return nil
+	}
+
+	// BindQueryParameter doesn't handle the delimited styles, which only
+	// apply to arrays, so split them here.
+	value, found := values[name]
+	if !found {
+		if required {
+			return fmt.Errorf("property '%s' is required", name)
+		}
+		return nil
+	}
+	parts := value
+	if !explode {
+		parts = strings.Split(value[0], delimiter)
+	}
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		field = field.Elem()
+	}
+	if field.Kind() != reflect.Slice {
+		return fmt.Errorf("property '%s' of style '%s' must be an array", name, style)
+	}
+	if !explode && isNestedArray(field.Type()) {
+		return fmt.Errorf("property '%s': %w", name, ErrNestedArrayStyle)
+	}
+	if err := bindSplitPartsToDestinationArray(parts, field.Addr().Interface()); err != nil {
+		return fmt.Errorf("error binding property '%s': %w", name, err)
+	}
+	return nil
+}
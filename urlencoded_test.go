@@ -0,0 +1,163 @@
+package runtime
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeURLEncodedBody(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+		Zip  string `json:"zip"`
+	}
+	type Body struct {
+		Name     string   `json:"name"`
+		Age      int      `json:"age"`
+		Nickname *string  `json:"nickname,omitempty"`
+		Tags     []string `json:"tags"`
+		Ids      []int    `json:"ids"`
+		Pipes    []int    `json:"pipes"`
+		Address  Address  `json:"address"`
+		Meta     Address  `json:"meta"`
+		Hidden   string   `json:"-"`
+		Empty    string   `json:"empty,omitempty"`
+	}
+	explode := false
+	body := Body{
+		Name:    "Fido & Co",
+		Age:     3,
+		Tags:    []string{"good boy", "fluffy"},
+		Ids:     []int{1, 2},
+		Pipes:   []int{3, 4},
+		Address: Address{City: "New York", Zip: "10001"},
+		Meta:    Address{City: "Oslo", Zip: "1"},
+		Hidden:  "secret",
+	}
+
+	encoded, err := EncodeURLEncodedBody(&body, map[string]RequestBodyEncoding{
+		"ids":     {Style: "form", Explode: &explode},
+		"pipes":   {Style: "pipeDelimited", Explode: &explode},
+		"address": {Style: "deepObject"},
+		"meta":    {ContentType: "application/json"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "name=Fido+%26+Co&age=3&tags=good+boy&tags=fluffy&ids=1,2&pipes=3%7C4"+
+		"&address%5Bcity%5D=New+York&address%5Bzip%5D=10001"+
+		"&meta=%7B%22city%22%3A%22Oslo%22%2C%22zip%22%3A%221%22%7D", encoded)
+
+	// The result must parse as a regular form body.
+	values, err := url.ParseQuery(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, "Fido & Co", values.Get("name"))
+	assert.Equal(t, []string{"good boy", "fluffy"}, values["tags"])
+	assert.Equal(t, "3|4", values.Get("pipes"))
+	assert.Equal(t, "New York", values.Get("address[city]"))
+	assert.JSONEq(t, `{"city":"Oslo","zip":"1"}`, values.Get("meta"))
+	assert.NotContains(t, values, "Hidden")
+	assert.NotContains(t, values, "empty")
+	assert.NotContains(t, values, "nickname")
+}
+
+func TestEncodeURLEncodedBody_DeepObjectNumbers(t *testing.T) {
+	type Limits struct {
+		Max   float64 `json:"max"`
+		Ratio float64 `json:"ratio"`
+	}
+	body := struct {
+		Limits Limits `json:"limits"`
+	}{Limits{Max: 1e6, Ratio: 0.25}}
+	encoded, err := EncodeURLEncodedBody(&body, map[string]RequestBodyEncoding{
+		"limits": {Style: "deepObject"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "limits%5Bmax%5D=1000000&limits%5Bratio%5D=0.25", encoded)
+
+	// Query parameters are styled alike.
+	styled, err := MarshalDeepObject(body.Limits, "limits")
+	require.NoError(t, err)
+	assert.Equal(t, "limits[max]=1000000&limits[ratio]=0.25", styled)
+}
+
+func TestEncodeURLEncodedBody_NotAStruct(t *testing.T) {
+	_, err := EncodeURLEncodedBody([]string{"a"}, nil)
+	assert.Error(t, err)
+}
+
+func TestBindURLEncodedBody(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+		Zip  string `json:"zip"`
+	}
+	type Body struct {
+		Name     string   `json:"name"`
+		Age      int      `json:"age"`
+		Nickname *string  `json:"nickname,omitempty"`
+		Tags     []string `json:"tags"`
+		Ids      []int    `json:"ids"`
+		Pipes    []int    `json:"pipes"`
+		Address  Address  `json:"address"`
+		Meta     Address  `json:"meta"`
+	}
+	explode := false
+	encodings := map[string]RequestBodyEncoding{
+		"ids":     {Style: "form", Explode: &explode},
+		"pipes":   {Style: "pipeDelimited", Explode: &explode},
+		"address": {Style: "deepObject"},
+		"meta":    {ContentType: "application/json"},
+	}
+
+	values, err := url.ParseQuery("name=Fido+%26+Co&age=3&nickname=fifi&tags=good+boy&tags=fluffy&ids=1,2&pipes=3%7C4" +
+		"&address%5Bcity%5D=New+York&address%5Bzip%5D=10001" +
+		"&meta=%7B%22city%22%3A%22Oslo%22%2C%22zip%22%3A%221%22%7D")
+	require.NoError(t, err)
+
+	var body Body
+	require.NoError(t, BindURLEncodedBody(values, &body, encodings))
+	assert.Equal(t, "Fido & Co", body.Name)
+	assert.Equal(t, 3, body.Age)
+	require.NotNil(t, body.Nickname)
+	assert.Equal(t, "fifi", *body.Nickname)
+	assert.Equal(t, []string{"good boy", "fluffy"}, body.Tags)
+	assert.Equal(t, []int{1, 2}, body.Ids)
+	assert.Equal(t, []int{3, 4}, body.Pipes)
+	assert.Equal(t, Address{City: "New York", Zip: "10001"}, body.Address)
+	assert.Equal(t, Address{City: "Oslo", Zip: "1"}, body.Meta)
+
+	t.Run("round trip", func(t *testing.T) {
+		encoded, err := EncodeURLEncodedBody(&body, encodings)
+		require.NoError(t, err)
+		values, err := url.ParseQuery(encoded)
+		require.NoError(t, err)
+		var decoded Body
+		require.NoError(t, BindURLEncodedBody(values, &decoded, encodings))
+		assert.Equal(t, body, decoded)
+	})
+
+	t.Run("required", func(t *testing.T) {
+		required := true
+		err := BindURLEncodedBody(url.Values{}, &body, map[string]RequestBodyEncoding{
+			"name": {Required: &required},
+		})
+		assert.ErrorContains(t, err, "'name'")
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		err := BindURLEncodedBody(url.Values{"age": {"old"}}, &body, nil)
+		assert.ErrorContains(t, err, "'age'")
+	})
+
+	t.Run("nested delimited array", func(t *testing.T) {
+		var nested struct {
+			Grid [][]int `json:"grid"`
+		}
+		for _, style := range []string{"spaceDelimited", "pipeDelimited"} {
+			err := BindURLEncodedBody(url.Values{"grid": {"1|2"}}, &nested, map[string]RequestBodyEncoding{
+				"grid": {Style: style, Explode: &explode},
+			})
+			assert.ErrorIs(t, err, ErrNestedArrayStyle, style)
+		}
+	})
+}
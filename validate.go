@@ -0,0 +1,55 @@
+package runtime
+
+import "sync/atomic"
+
+// ValidatorFunc validates a value once a binder has populated it, such as
+// by enforcing the minimum, maximum and pattern constraints of its schema.
+type ValidatorFunc func(v interface{}) error
+
+// ValidationError is returned by the body binders when the validator
+// installed with SetValidator rejects a bound value. Like a BindingError, it
+// is caused by the request and usually answered with a 400.
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return "validation failed: " + e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+type validatorHolder struct {
+	validate ValidatorFunc
+}
+
+var validator atomic.Pointer[validatorHolder]
+
+// SetValidator installs the validator which the body binders, such as
+// BindJSONBody and BindURLEncodedBody, run on their destination after
+// populating it. Passing nil restores the default of not validating. See
+// the playgroundvalidator package for an adapter for the struct tags of
+// github.com/go-playground/validator. It panics after FreezeSettings.
+func SetValidator(validate ValidatorFunc) {
+	checkSettingsFrozen("SetValidator")
+	if validate == nil {
+		validator.Store(nil)
+		return
+	}
+	validator.Store(&validatorHolder{validate: validate})
+}
+
+// validateBound runs the installed validator on dest, wrapping its error in
+// a *ValidationError.
+func validateBound(dest interface{}) error {
+	h := validator.Load()
+	if h == nil {
+		return nil
+	}
+	if err := h.validate(dest); err != nil {
+		return &ValidationError{Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package runtime
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetValidator(t *testing.T) {
+	type Pet struct {
+		Name string `json:"name"`
+	}
+	errEmptyName := errors.New("name is empty")
+	SetValidator(func(v interface{}) error {
+		if pet, ok := v.(*Pet); ok && pet.Name == "" {
+			return errEmptyName
+		}
+		return nil
+	})
+	defer SetValidator(nil)
+
+	var pet Pet
+	require.NoError(t, BindJSONBody(strings.NewReader(`{"name":"rex"}`), &pet))
+
+	err := BindJSONBody(strings.NewReader(`{"name":""}`), &pet)
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.ErrorIs(t, err, errEmptyName)
+
+	pet = Pet{}
+	err = BindURLEncodedBody(url.Values{}, &pet, nil)
+	assert.ErrorIs(t, err, errEmptyName)
+
+	SetValidator(nil)
+	assert.NoError(t, BindJSONBody(strings.NewReader(`{"name":""}`), &pet))
+}
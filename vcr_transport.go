@@ -0,0 +1,241 @@
+package runtime
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// VCRMode selects whether a VCRTransport replays or records interactions.
+type VCRMode int
+
+const (
+	// VCRReplay serves every request from a recorded interaction, failing
+	// with ErrVCRInteractionNotFound when there is none, which suits CI.
+	VCRReplay VCRMode = iota
+	// VCRRecord sends every request, recording the interaction over any
+	// previously recorded one.
+	VCRRecord
+	// VCRReplayOrRecord replays recorded interactions, and sends and
+	// records requests which have none.
+	VCRReplayOrRecord
+)
+
+// ErrVCRInteractionNotFound is returned by a VCRTransport replaying a
+// request which wasn't recorded.
+var ErrVCRInteractionNotFound = errors.New("no recorded interaction")
+
+// VCRInteraction is a recorded request and its response.
+type VCRInteraction struct {
+	OperationID    string      `json:"operationId,omitempty"`
+	Method         string      `json:"method"`
+	URL            string      `json:"url"`
+	RequestHeader  http.Header `json:"requestHeader,omitempty"`
+	RequestBody    VCRBody     `json:"requestBody,omitempty"`
+	StatusCode     int         `json:"statusCode"`
+	ResponseHeader http.Header `json:"responseHeader,omitempty"`
+	ResponseBody   VCRBody     `json:"responseBody,omitempty"`
+}
+
+// VCRBody is a recorded body. It is stored as a JSON string when it is
+// valid UTF-8, so that recordings stay readable, and as base64 otherwise.
+type VCRBody []byte
+
+func (b VCRBody) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(string(b)); err != nil {
+			return nil, err
+		}
+		return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+	}
+	return json.Marshal(struct {
+		Base64 []byte `json:"base64"`
+	}{b})
+}
+
+func (b *VCRBody) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = VCRBody(s)
+		return nil
+	}
+	var encoded struct {
+		Base64 string `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded.Base64)
+	if err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+// vcrSensitiveHeaders are never written to recordings.
+var vcrSensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// VCRTransport is an http.RoundTripper which records interactions with a
+// real API to disk and replays them later, so that integration tests
+// against third-party APIs are deterministic and can run offline.
+//
+// Each interaction is stored in its own file in Dir, named after the
+// operation ID in the request context and a hash of the method, path,
+// query parameters and request body, so that a request replays the
+// recording of an identical one regardless of the order its query
+// parameters were added in. The values of a repeated parameter must come in
+// the same order, as it may matter to the server. Headers aren't part of
+// the key.
+type VCRTransport struct {
+	// Base is the transport used to send requests when recording. It
+	// defaults to http.DefaultTransport.
+	Base http.RoundTripper
+	// Dir is the directory recordings are kept in.
+	Dir  string
+	Mode VCRMode
+	// Redact, if set, is called on every interaction before it is written,
+	// to scrub secrets such as API keys in URLs or tokens in bodies.
+	// Authorization, Proxy-Authorization, Cookie and Set-Cookie headers are
+	// always left out of recordings.
+	Redact func(*VCRInteraction)
+}
+
+func (t *VCRTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading request body: %w", err)
+		}
+	}
+	operationID := GetOperationID(req.Context())
+	path := filepath.Join(t.Dir, vcrFileName(operationID, req, reqBody))
+
+	if t.Mode != VCRRecord {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			var interaction VCRInteraction
+			if err := json.Unmarshal(data, &interaction); err != nil {
+				return nil, fmt.Errorf("error reading recording %s: %w", path, err)
+			}
+			return interaction.response(req), nil
+		case !errors.Is(err, fs.ErrNotExist):
+			return nil, err
+		case t.Mode == VCRReplay:
+			return nil, fmt.Errorf("%s %s (operation '%s'): %w", req.Method, req.URL, operationID, ErrVCRInteractionNotFound)
+		}
+	}
+
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(reqBody))
+	out.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(reqBody)), nil
+	}
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := VCRInteraction{
+		OperationID:    operationID,
+		Method:         req.Method,
+		URL:            req.URL.String(),
+		RequestHeader:  req.Header.Clone(),
+		RequestBody:    reqBody,
+		StatusCode:     resp.StatusCode,
+		ResponseHeader: resp.Header.Clone(),
+		ResponseBody:   respBody,
+	}
+	for _, h := range vcrSensitiveHeaders {
+		interaction.RequestHeader.Del(h)
+		interaction.ResponseHeader.Del(h)
+	}
+	if t.Redact != nil {
+		t.Redact(&interaction)
+	}
+	if err := writeVCRInteraction(path, &interaction); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func writeVCRInteraction(path string, interaction *VCRInteraction) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(interaction); err != nil {
+		return fmt.Errorf("error encoding recording: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// response rebuilds the recorded response to req.
+func (i *VCRInteraction) response(req *http.Request) *http.Response {
+	header := i.ResponseHeader.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	// Redact may have changed the length of the body.
+	header.Del("Content-Length")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.StatusCode, http.StatusText(i.StatusCode)),
+		StatusCode:    i.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(i.ResponseBody)),
+		ContentLength: int64(len(i.ResponseBody)),
+		Request:       req,
+	}
+}
+
+// vcrFileName returns the name of the file recording req.
+func vcrFileName(operationID string, req *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(req.Method + "\n" + req.URL.EscapedPath() + "\n" + keyQuery(req.URL.Query()) + "\n"))
+	h.Write(body)
+	name := operationID
+	if name == "" {
+		name = "request"
+	}
+	name = strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || r == '.' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+	return name + "-" + hex.EncodeToString(h.Sum(nil))[:16] + ".json"
+}
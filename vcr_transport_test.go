@@ -0,0 +1,92 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVCRTransport(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "session=secret")
+		_, _ = w.Write([]byte("echo:" + r.URL.RawQuery + ":" + string(body)))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	do := func(mode VCRMode, query string, body string) (string, error) {
+		transport := &VCRTransport{
+			Dir:  dir,
+			Mode: mode,
+			Redact: func(i *VCRInteraction) {
+				i.URL = strings.ReplaceAll(i.URL, "key=abc", "key=REDACTED")
+				i.ResponseBody = bytes.ReplaceAll(i.ResponseBody, []byte("key=abc"), []byte("key=REDACTED"))
+			},
+		}
+		ctx := WithOperationID(context.Background(), "searchPets")
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+"/pets?"+query, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer token")
+		resp, err := (&http.Client{Transport: transport}).Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer func() { _ = resp.Body.Close() }()
+		data, err := io.ReadAll(resp.Body)
+		return string(data), err
+	}
+
+	_, err := do(VCRReplay, "a=1&b=2", "x")
+	assert.True(t, errors.Is(err, ErrVCRInteractionNotFound))
+
+	body, err := do(VCRReplayOrRecord, "a=1&b=2&key=abc", "x")
+	require.NoError(t, err)
+	assert.Equal(t, "echo:a=1&b=2&key=abc:x", body)
+	assert.Equal(t, 1, hits)
+
+	// Query parameters in another order replay the same, redacted, recording.
+	body, err = do(VCRReplay, "key=abc&b=2&a=1", "x")
+	require.NoError(t, err)
+	assert.Equal(t, "echo:a=1&b=2&key=REDACTED:x", body)
+	assert.Equal(t, 1, hits)
+
+	_, err = do(VCRReplay, "a=1&b=2&key=abc", "y")
+	assert.True(t, errors.Is(err, ErrVCRInteractionNotFound), "the body is part of the key")
+
+	files, err := filepath.Glob(filepath.Join(dir, "searchPets-*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	recording, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.NotContains(t, string(recording), "Bearer token")
+	assert.NotContains(t, string(recording), "session=secret")
+	assert.NotContains(t, string(recording), "key=abc")
+	assert.Contains(t, string(recording), `"responseBody": "echo:a=1&b=2&key=REDACTED:x"`)
+
+	_, err = do(VCRRecord, "a=1&b=2&key=abc", "x")
+	require.NoError(t, err)
+	assert.Equal(t, 2, hits)
+}
+
+func TestVCRBody(t *testing.T) {
+	for _, body := range []VCRBody{VCRBody("text"), VCRBody{0xff, 0x00}} {
+		data, err := body.MarshalJSON()
+		require.NoError(t, err)
+		var decoded VCRBody
+		require.NoError(t, decoded.UnmarshalJSON(data))
+		assert.Equal(t, body, decoded)
+	}
+}
@@ -0,0 +1,101 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// XMLRoot describes the root element of an XML body, following the OpenAPI
+// xml object of the body's schema. All fields are optional.
+type XMLRoot struct {
+	// Name overrides the root element's name. It defaults to the XMLName
+	// field of the value, or else its type name.
+	Name string
+	// Namespace is the namespace URI of the root element.
+	Namespace string
+	// Prefix, together with Namespace, declares the namespace with a prefix,
+	// as in <prefix:name xmlns:prefix="namespace">, instead of as the default
+	// namespace.
+	Prefix string
+}
+
+// MarshalXMLBody serializes v as an application/xml body, including the XML
+// declaration.
+//
+// Properties follow the OpenAPI xml object through encoding/xml struct tags,
+// which generated models carry:
+//
+//	name:              `xml:"name"`
+//	attribute: true    `xml:"name,attr"`
+//	wrapped: true      `xml:"wrapper>item"` on an array
+//	namespace:         `xml:"https://example.com/schema name"`
+//
+// The root element's name and namespace come from root.
+func MarshalXMLBody(v interface{}, root XMLRoot) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	if err := enc.EncodeElement(v, xmlRootElement(v, root)); err != nil {
+		return nil, fmt.Errorf("error marshaling XML body: %w", err)
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalXMLBody decodes an application/xml body into dest, using the same
+// struct tag conventions as MarshalXMLBody. The root element's name is not
+// checked, so bodies produced with a different XMLRoot can still be read.
+func UnmarshalXMLBody(body io.Reader, dest interface{}) error {
+	if err := xml.NewDecoder(body).Decode(dest); err != nil {
+		return fmt.Errorf("error unmarshaling XML body: %w", err)
+	}
+	return validateBound(dest)
+}
+
+func xmlRootElement(v interface{}, root XMLRoot) xml.StartElement {
+	name := root.Name
+	if name == "" {
+		name = xmlTypeName(reflect.TypeOf(v))
+	}
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	switch {
+	case root.Namespace != "" && root.Prefix != "":
+		// encoding/xml only writes default namespace declarations, so spell
+		// out the prefixed one.
+		start.Name.Local = root.Prefix + ":" + name
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns:" + root.Prefix}, Value: root.Namespace})
+	case root.Namespace != "":
+		start.Name.Space = root.Namespace
+	}
+	return start
+}
+
+// xmlTypeName returns the element name encoding/xml would use for values of
+// type t when no name is given.
+func xmlTypeName(t reflect.Type) string {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return "root"
+	}
+	if t.Kind() == reflect.Struct {
+		if f, ok := t.FieldByName("XMLName"); ok {
+			// The tag is "[namespace ]name[,options]".
+			tag, _, _ := strings.Cut(f.Tag.Get("xml"), ",")
+			if fields := strings.Fields(tag); len(fields) > 0 {
+				return fields[len(fields)-1]
+			}
+		}
+	}
+	if t.Name() != "" {
+		return t.Name()
+	}
+	return "root"
+}
@@ -0,0 +1,50 @@
+package runtime
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type xmlPet struct {
+	ID   int      `xml:"id,attr"`
+	Name string   `xml:"https://example.com/pets name"`
+	Tags []string `xml:"tags>tag"`
+}
+
+type xmlNamedPet struct {
+	XMLName xml.Name `xml:"animal"`
+	Name    string   `xml:"name"`
+}
+
+func TestMarshalXMLBody(t *testing.T) {
+	pet := xmlPet{ID: 1, Name: "rex", Tags: []string{"good", "boy"}}
+
+	data, err := MarshalXMLBody(pet, XMLRoot{})
+	require.NoError(t, err)
+	assert.Equal(t, xml.Header+`<xmlPet id="1"><name xmlns="https://example.com/pets">rex</name>`+
+		`<tags><tag>good</tag><tag>boy</tag></tags></xmlPet>`, string(data))
+
+	data, err = MarshalXMLBody(&pet, XMLRoot{Name: "pet", Namespace: "https://example.com/schema", Prefix: "p"})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), xml.Header+`<p:pet xmlns:p="https://example.com/schema" id="1">`))
+
+	data, err = MarshalXMLBody(xmlNamedPet{Name: "rex"}, XMLRoot{Namespace: "https://example.com/schema"})
+	require.NoError(t, err)
+	assert.Equal(t, xml.Header+`<animal xmlns="https://example.com/schema"><name>rex</name></animal>`, string(data))
+}
+
+func TestUnmarshalXMLBody(t *testing.T) {
+	pet := xmlPet{ID: 1, Name: "rex", Tags: []string{"good", "boy"}}
+	data, err := MarshalXMLBody(pet, XMLRoot{Name: "pet", Namespace: "https://example.com/schema", Prefix: "p"})
+	require.NoError(t, err)
+
+	var decoded xmlPet
+	require.NoError(t, UnmarshalXMLBody(strings.NewReader(string(data)), &decoded))
+	assert.Equal(t, pet, decoded)
+
+	assert.Error(t, UnmarshalXMLBody(strings.NewReader("<pet>"), &decoded))
+}
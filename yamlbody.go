@@ -0,0 +1,131 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BindYAMLBody decodes an application/yaml body into dest. The document is
+// converted to JSON and then unmarshaled with encoding/json, so that dest's
+// json struct tags and custom JSON unmarshalers, such as those of the types
+// package, apply exactly as they do for JSON bodies. Timestamps, such as an
+// unquoted 2020-01-02, are passed on as the text they were written as, and
+// so are mapping keys which aren't strings, such as 1 or true, as JSON
+// object keys are. Keys which aren't scalars fail, as does a body holding
+// more than one document.
+func BindYAMLBody(body io.Reader, dest interface{}) error {
+	dec := yaml.NewDecoder(body)
+	var node yaml.Node
+	if err := dec.Decode(&node); err != nil && err != io.EOF {
+		return fmt.Errorf("error decoding YAML body: %w", err)
+	}
+	var doc interface{}
+	if node.Kind != 0 {
+		var trailing yaml.Node
+		if err := dec.Decode(&trailing); err != io.EOF {
+			return errors.New("error decoding YAML body: more than one document")
+		}
+		yamlTimestampsAsStrings(&node)
+		if err := yamlKeysAsStrings(&node); err != nil {
+			return fmt.Errorf("error decoding YAML body: %w", err)
+		}
+		if err := node.Decode(&doc); err != nil {
+			return fmt.Errorf("error decoding YAML body: %w", err)
+		}
+	}
+	data, err := jsonMarshal(doc)
+	if err != nil {
+		return fmt.Errorf("error converting YAML body to JSON: %w", err)
+	}
+	if err := jsonUnmarshal(data, dest); err != nil {
+		return fmt.Errorf("error unmarshaling YAML body: %w", err)
+	}
+	return validateBound(dest)
+}
+
+// yamlTimestampsAsStrings retags the timestamp scalars below node as
+// strings, which decoding would otherwise turn into a time.Time, marshaled
+// to JSON in RFC 3339 form whatever the original text, which a types.Date
+// can't unmarshal.
+func yamlTimestampsAsStrings(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!timestamp" {
+		node.Tag = "!!str"
+	}
+	for _, child := range node.Content {
+		yamlTimestampsAsStrings(child)
+	}
+}
+
+// yamlKeysAsStrings replaces the scalar mapping keys below node with string
+// scalars of the same text, as decoding a mapping with keys which aren't
+// strings yields a map[interface{}]interface{}, which can't be marshaled to
+// JSON. It fails for keys which aren't scalars.
+func yamlKeysAsStrings(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if key.Kind == yaml.AliasNode && key.Alias != nil {
+				key = key.Alias
+			}
+			if key.Kind != yaml.ScalarNode {
+				return fmt.Errorf("line %d: mapping keys must be scalars", key.Line)
+			}
+			// Merge keys bring in the keys of another mapping.
+			if key.ShortTag() != "!!str" && key.ShortTag() != "!!merge" {
+				node.Content[i] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.Value, Line: key.Line, Column: key.Column}
+			}
+		}
+	}
+	for _, child := range node.Content {
+		if err := yamlKeysAsStrings(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MarshalYAMLBody serializes v as an application/yaml body. Like
+// BindYAMLBody, it goes through JSON, so v's json struct tags and custom JSON
+// marshalers decide the document's shape.
+func MarshalYAMLBody(v interface{}) ([]byte, error) {
+	data, err := jsonMarshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling YAML body: %w", err)
+	}
+	var doc interface{}
+	dec := newJSONDecoder(bytes.NewReader(data))
+	// Keep numbers as written, rather than turning large integers into
+	// floats.
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("error marshaling YAML body: %w", err)
+	}
+	return yaml.Marshal(yamlNumbers(doc))
+}
+
+// yamlNumbers replaces the json.Numbers in doc with YAML scalars holding the
+// same text, which yaml.Marshal would otherwise quote as strings.
+func yamlNumbers(doc interface{}) interface{} {
+	switch d := doc.(type) {
+	case map[string]interface{}:
+		for k, v := range d {
+			d[k] = yamlNumbers(v)
+		}
+	case []interface{}:
+		for i, v := range d {
+			d[i] = yamlNumbers(v)
+		}
+	case json.Number:
+		tag := "!!int"
+		if _, err := d.Int64(); err != nil {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: d.String()}
+	}
+	return doc
+}
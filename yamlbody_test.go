@@ -0,0 +1,101 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oapi-codegen/runtime/types"
+)
+
+type yamlPet struct {
+	Name     string            `json:"name"`
+	ID       int64             `json:"id"`
+	Born     types.Date        `json:"born"`
+	Nickname *string           `json:"nickname,omitempty"`
+	Labels   map[string]string `json:"labels"`
+}
+
+func TestBindYAMLBody(t *testing.T) {
+	var pet yamlPet
+	require.NoError(t, BindYAMLBody(strings.NewReader(`
+name: rex
+id: 9007199254740993
+born: "2020-01-02"
+labels:
+  app: pets
+`), &pet))
+	assert.Equal(t, "rex", pet.Name)
+	assert.Equal(t, int64(9007199254740993), pet.ID)
+	assert.Equal(t, "2020-01-02", pet.Born.String())
+	assert.Nil(t, pet.Nickname)
+	assert.Equal(t, map[string]string{"app": "pets"}, pet.Labels)
+
+	var unquoted yamlPet
+	require.NoError(t, BindYAMLBody(strings.NewReader("born: 2020-01-02\n"), &unquoted))
+	assert.Equal(t, "2020-01-02", unquoted.Born.String())
+
+	var stamped struct {
+		At time.Time `json:"at"`
+	}
+	require.NoError(t, BindYAMLBody(strings.NewReader("at: 2020-01-02T03:04:05Z\n"), &stamped))
+	assert.Equal(t, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), stamped.At)
+
+	assert.Error(t, BindYAMLBody(strings.NewReader("name: [rex"), &pet))
+	assert.Error(t, BindYAMLBody(strings.NewReader("name: [rex]"), &pet))
+}
+
+func TestBindYAMLBody_NonStringKeys(t *testing.T) {
+	var dest struct {
+		Codes map[string]string `json:"codes"`
+		Flags map[string]int    `json:"flags"`
+	}
+	require.NoError(t, BindYAMLBody(strings.NewReader(`
+codes:
+  200: ok
+  404: not found
+flags:
+  true: 1
+`), &dest))
+	assert.Equal(t, map[string]string{"200": "ok", "404": "not found"}, dest.Codes)
+	assert.Equal(t, map[string]int{"true": 1}, dest.Flags)
+
+	var byCode map[int]string
+	require.NoError(t, BindYAMLBody(strings.NewReader("200: ok\n"), &byCode))
+	assert.Equal(t, map[int]string{200: "ok"}, byCode)
+
+	var doc map[string]interface{}
+	err := BindYAMLBody(strings.NewReader("? [a, b]\n: c\n"), &doc)
+	assert.ErrorContains(t, err, "mapping keys must be scalars")
+}
+
+func TestBindYAMLBody_MultipleDocuments(t *testing.T) {
+	var pet yamlPet
+	err := BindYAMLBody(strings.NewReader("name: rex\n---\nname: fido\n"), &pet)
+	assert.ErrorContains(t, err, "more than one document")
+	err = BindYAMLBody(strings.NewReader("name: rex\n--- [\n"), &pet)
+	assert.Error(t, err)
+
+	// Trailing comments and blank lines aren't documents.
+	require.NoError(t, BindYAMLBody(strings.NewReader("name: rex\n\n# done\n"), &pet))
+	assert.Equal(t, "rex", pet.Name)
+}
+
+func TestMarshalYAMLBody(t *testing.T) {
+	pet := yamlPet{
+		Name:   "rex",
+		ID:     9007199254740993,
+		Born:   types.Date{Time: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+		Labels: map[string]string{"app": "pets"},
+	}
+	data, err := MarshalYAMLBody(pet)
+	require.NoError(t, err)
+	assert.Equal(t, "born: \"2020-01-02\"\nid: 9007199254740993\nlabels:\n    app: pets\nname: rex\n", string(data))
+
+	var decoded yamlPet
+	require.NoError(t, BindYAMLBody(strings.NewReader(string(data)), &decoded))
+	assert.Equal(t, pet, decoded)
+}